@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/travisbale/mailman/internal/db/postgres"
+	"github.com/travisbale/mailman/internal/email"
+	"github.com/urfave/cli/v2"
+)
+
+// apiKeyCmd provides API key management commands
+var apiKeyCmd = &cli.Command{
+	Name:  "apikey",
+	Usage: "Manage the API keys services authenticate with",
+	Subcommands: []*cli.Command{
+		apiKeyCreateCmd,
+		apiKeyListCmd,
+		apiKeyRevokeCmd,
+	},
+}
+
+// apiKeyCreateCmd issues a new API key
+var apiKeyCreateCmd = &cli.Command{
+	Name:  "create",
+	Usage: "Issue an API key",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "name",
+			Usage:    "Service the key is issued to (e.g., billing-service)",
+			Required: true,
+		},
+		&cli.StringSliceFlag{
+			Name:     "scope",
+			Usage:    "Scope to grant, repeatable: " + strings.Join(email.Scopes, ", ") + " (adding and removing suppressions needs " + email.ScopeSuppressionsWrite + ")",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "client-identity",
+			Usage: "Mutual TLS client certificate identity (URI SAN, DNS SAN or common name) that authenticates as the key without its token",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		ctx := c.Context
+
+		db, err := postgres.NewDB(ctx, config.DatabaseURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		apiKeyService := email.NewAPIKeyService(postgres.NewAPIKeysDB(db))
+
+		key, token, err := apiKeyService.CreateAPIKey(ctx, c.String("name"), c.String("client-identity"), c.StringSlice("scope"))
+		if err != nil {
+			return fmt.Errorf("failed to create API key: %w", err)
+		}
+
+		fmt.Printf("API key created successfully\n")
+		fmt.Printf("  ID: %s\n", key.ID)
+		fmt.Printf("  Tenant: %s\n", key.TenantID)
+		fmt.Printf("  Name: %s\n", key.Name)
+		fmt.Printf("  Scopes: %s\n", strings.Join(key.Scopes, ", "))
+		if key.ClientIdentity != nil {
+			fmt.Printf("  Client identity: %s\n", *key.ClientIdentity)
+		}
+		fmt.Printf("\n%s\n\n", token)
+		fmt.Printf("Store the key now; it cannot be shown again.\n")
+
+		return nil
+	},
+}
+
+// apiKeyListCmd lists issued API keys
+var apiKeyListCmd = &cli.Command{
+	Name:  "list",
+	Usage: "List the API keys of every tenant, including revoked ones",
+	Action: func(c *cli.Context) error {
+		ctx := c.Context
+
+		db, err := postgres.NewDB(ctx, config.DatabaseURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		apiKeyService := email.NewAPIKeyService(postgres.NewAPIKeysDB(db))
+
+		keys, err := apiKeyService.ListAPIKeys(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list API keys: %w", err)
+		}
+
+		if len(keys) == 0 {
+			fmt.Println("No API keys found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		if _, err := fmt.Fprintln(w, "ID\tTENANT\tNAME\tSCOPES\tCLIENT IDENTITY\tCREATED\tREVOKED"); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+		if _, err := fmt.Fprintln(w, "--\t------\t----\t------\t---------------\t-------\t-------"); err != nil {
+			return fmt.Errorf("failed to write separator: %w", err)
+		}
+
+		for _, key := range keys {
+			revoked := "-"
+			if key.RevokedAt != nil {
+				revoked = key.RevokedAt.Format("2006-01-02")
+			}
+
+			clientIdentity := "-"
+			if key.ClientIdentity != nil {
+				clientIdentity = *key.ClientIdentity
+			}
+
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				key.ID,
+				key.TenantID,
+				key.Name,
+				strings.Join(key.Scopes, ","),
+				clientIdentity,
+				key.CreatedAt.Format("2006-01-02"),
+				revoked,
+			); err != nil {
+				return fmt.Errorf("failed to write API key row: %w", err)
+			}
+		}
+
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("failed to flush output: %w", err)
+		}
+
+		return nil
+	},
+}
+
+// apiKeyRevokeCmd revokes an API key
+var apiKeyRevokeCmd = &cli.Command{
+	Name:  "revoke",
+	Usage: "Permanently stop an API key from authenticating",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "id",
+			Usage:    "ID of the key, as shown by apikey list",
+			Required: true,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		ctx := c.Context
+
+		db, err := postgres.NewDB(ctx, config.DatabaseURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		apiKeyService := email.NewAPIKeyService(postgres.NewAPIKeysDB(db))
+
+		if err := apiKeyService.RevokeAPIKey(ctx, c.String("id")); err != nil {
+			return fmt.Errorf("failed to revoke API key: %w", err)
+		}
+
+		fmt.Printf("API key revoked: %s\n", c.String("id"))
+
+		return nil
+	},
+}
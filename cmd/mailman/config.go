@@ -1,31 +1,124 @@
 package main
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/travisbale/mailman/internal/app"
+	"github.com/travisbale/mailman/internal/email"
+	"github.com/urfave/cli/v2"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Debug          bool
-	DatabaseURL    string
-	HTTPAddress    string
-	GRPCAddress    string
-	SendGridAPIKey string
-	FromAddress    string
-	FromName       string
+	Debug              bool
+	LogFormat          string
+	LogLevel           string
+	DatabaseURL        string
+	Tenant             string
+	HTTPAddress        string
+	GRPCAddress        string
+	Providers          cli.StringSlice
+	SendGridAPIKey     string
+	SendGridWebhookKey string
+	SMTPHost           string
+	SMTPPort           int
+	SMTPUsername       string
+	SMTPPassword       string
+	SMTPTLSMode        string
+	SMTPAuth           string
+	SMTPPoolSize       int
+	FromAddress        string
+	FromName           string
+	ProviderRateLimit  string
+	DomainRateLimit    string
+	DomainRateLimits   cli.StringSlice
+	IdempotencyTTL     time.Duration
+	DisableAuth        bool
+	TLSCertFile        string
+	TLSKeyFile         string
+	TLSClientCAFile    string
+	OTLPEndpoint       string
 }
 
 // config is the global configuration populated by CLI flags
 var config = &Config{}
 
 // ToAppConfig converts the CLI config to an app.Config
-func (c *Config) ToAppConfig() *app.Config {
+func (c *Config) ToAppConfig() (*app.Config, error) {
+	rateLimits, err := c.rateLimits()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.IdempotencyTTL <= 0 {
+		return nil, fmt.Errorf("--idempotency-ttl must be positive")
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return nil, fmt.Errorf("--tls-cert and --tls-key must be set together")
+	}
+	if c.TLSClientCAFile != "" && c.TLSCertFile == "" {
+		return nil, fmt.Errorf("--tls-client-ca requires --tls-cert and --tls-key")
+	}
+
 	return &app.Config{
-		DatabaseURL:    c.DatabaseURL,
-		HTTPAddress:    c.HTTPAddress,
-		GRPCAddress:    c.GRPCAddress,
-		SendGridAPIKey: c.SendGridAPIKey,
+		DatabaseURL:        c.DatabaseURL,
+		HTTPAddress:        c.HTTPAddress,
+		GRPCAddress:        c.GRPCAddress,
+		Providers:          c.Providers.Value(),
+		SendGridAPIKey:     c.SendGridAPIKey,
+		SendGridWebhookKey: c.SendGridWebhookKey,
+		SMTP: app.SMTPConfig{
+			Host:     c.SMTPHost,
+			Port:     c.SMTPPort,
+			Username: c.SMTPUsername,
+			Password: c.SMTPPassword,
+			TLSMode:  c.SMTPTLSMode,
+			Auth:     c.SMTPAuth,
+			PoolSize: c.SMTPPoolSize,
+		},
 		FromAddress:    c.FromAddress,
 		FromName:       c.FromName,
+		RateLimits:     rateLimits,
+		IdempotencyTTL: c.IdempotencyTTL,
+		DisableAuth:    c.DisableAuth,
+		TLS: app.TLSConfig{
+			CertFile:     c.TLSCertFile,
+			KeyFile:      c.TLSKeyFile,
+			ClientCAFile: c.TLSClientCAFile,
+		},
+		OTLPEndpoint: c.OTLPEndpoint,
+		Version:      Version,
+	}, nil
+}
+
+// rateLimits parses the rate limit flags
+func (c *Config) rateLimits() (email.RateLimits, error) {
+	var limits email.RateLimits
+	var err error
+
+	if limits.Provider, err = email.ParseRateLimit(c.ProviderRateLimit); err != nil {
+		return limits, fmt.Errorf("--provider-rate-limit: %w", err)
+	}
+	if limits.Domain, err = email.ParseRateLimit(c.DomainRateLimit); err != nil {
+		return limits, fmt.Errorf("--domain-rate-limit: %w", err)
 	}
+
+	limits.Domains = make(map[string]email.RateLimit)
+	for _, override := range c.DomainRateLimits.Value() {
+		domain, limit, ok := strings.Cut(override, "=")
+		if !ok {
+			return limits, fmt.Errorf("--domain-rate-limits: expected <domain>=<limit>, got %q", override)
+		}
+
+		parsed, err := email.ParseRateLimit(limit)
+		if err != nil {
+			return limits, fmt.Errorf("--domain-rate-limits: %w", err)
+		}
+		limits.Domains[strings.ToLower(strings.TrimSpace(domain))] = parsed
+	}
+
+	return limits, nil
 }
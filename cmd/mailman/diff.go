@@ -0,0 +1,51 @@
+package main
+
+import "strings"
+
+// diffLines returns a line-by-line diff of a and b, prefixing each line with
+// "-" (only in a), "+" (only in b) or " " (in both). It uses a longest common
+// subsequence table, which is plenty fast for template-sized inputs.
+func diffLines(a, b string) []string {
+	from := strings.Split(a, "\n")
+	to := strings.Split(b, "\n")
+
+	// lcs[i][j] is the length of the LCS of from[i:] and to[j:]
+	lcs := make([][]int, len(from)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < len(from) && j < len(to) {
+		switch {
+		case from[i] == to[j]:
+			out = append(out, " "+from[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "-"+from[i])
+			i++
+		default:
+			out = append(out, "+"+to[j])
+			j++
+		}
+	}
+	for ; i < len(from); i++ {
+		out = append(out, "-"+from[i])
+	}
+	for ; j < len(to); j++ {
+		out = append(out, "+"+to[j])
+	}
+
+	return out
+}
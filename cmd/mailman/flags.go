@@ -1,6 +1,9 @@
 package main
 
 import (
+	"time"
+
+	"github.com/travisbale/mailman/internal/email"
 	"github.com/urfave/cli/v2"
 )
 
@@ -14,6 +17,24 @@ var (
 		Destination: &config.Debug,
 	}
 
+	// LogFormatFlag selects text or JSON log output (global flag)
+	LogFormatFlag = &cli.StringFlag{
+		Name:        "log-format",
+		Usage:       "Log output format: text or json",
+		EnvVars:     []string{"LOG_FORMAT"},
+		Value:       "text",
+		Destination: &config.LogFormat,
+	}
+
+	// LogLevelFlag sets the minimum level of logs written (global flag)
+	LogLevelFlag = &cli.StringFlag{
+		Name:        "log-level",
+		Usage:       "Minimum log level: debug, info, warn or error (--debug implies debug)",
+		EnvVars:     []string{"LOG_LEVEL"},
+		Value:       "info",
+		Destination: &config.LogLevel,
+	}
+
 	// DatabaseURLFlag defines the PostgreSQL connection URL (global flag)
 	DatabaseURLFlag = &cli.StringFlag{
 		Name:        "database-url",
@@ -23,6 +44,15 @@ var (
 		Destination: &config.DatabaseURL,
 	}
 
+	// TenantFlag selects the tenant that tenant-scoped commands act for (global flag)
+	TenantFlag = &cli.StringFlag{
+		Name:        "tenant",
+		Usage:       "Tenant that template, sender, suppression, API key and job commands act for",
+		EnvVars:     []string{"MAILMAN_TENANT"},
+		Value:       email.DefaultTenant,
+		Destination: &config.Tenant,
+	}
+
 	// HTTPAddressFlag defines the HTTP server bind address
 	HTTPAddressFlag = &cli.StringFlag{
 		Name:        "http-address",
@@ -41,6 +71,14 @@ var (
 		Destination: &config.GRPCAddress,
 	}
 
+	// ProvidersFlag defines the email providers to deliver through, in failover order
+	ProvidersFlag = &cli.StringSliceFlag{
+		Name:        "providers",
+		Usage:       "Email providers in failover order: sendgrid, smtp or console (defaults to every configured provider)",
+		EnvVars:     []string{"EMAIL_PROVIDERS"},
+		Destination: &config.Providers,
+	}
+
 	// SendGridAPIKeyFlag defines the SendGrid API key for sending emails
 	SendGridAPIKeyFlag = &cli.StringFlag{
 		Name:        "sendgrid-api-key",
@@ -49,6 +87,73 @@ var (
 		Destination: &config.SendGridAPIKey,
 	}
 
+	// SendGridWebhookKeyFlag defines the key that verifies SendGrid Event Webhook signatures
+	SendGridWebhookKeyFlag = &cli.StringFlag{
+		Name:        "sendgrid-webhook-key",
+		Usage:       "SendGrid Event Webhook verification key; enables POST /webhooks/sendgrid",
+		EnvVars:     []string{"SENDGRID_WEBHOOK_KEY"},
+		Destination: &config.SendGridWebhookKey,
+	}
+
+	// SMTPHostFlag defines the SMTP relay host; setting it selects SMTP delivery
+	SMTPHostFlag = &cli.StringFlag{
+		Name:        "smtp-host",
+		Usage:       "SMTP relay host for sending emails",
+		EnvVars:     []string{"SMTP_HOST"},
+		Destination: &config.SMTPHost,
+	}
+
+	// SMTPPortFlag defines the SMTP relay port
+	SMTPPortFlag = &cli.IntFlag{
+		Name:        "smtp-port",
+		Usage:       "SMTP relay port (defaults to 465 for implicit TLS, otherwise 587)",
+		EnvVars:     []string{"SMTP_PORT"},
+		Destination: &config.SMTPPort,
+	}
+
+	// SMTPUsernameFlag defines the SMTP authentication username
+	SMTPUsernameFlag = &cli.StringFlag{
+		Name:        "smtp-username",
+		Usage:       "SMTP authentication username (authentication is skipped when empty)",
+		EnvVars:     []string{"SMTP_USERNAME"},
+		Destination: &config.SMTPUsername,
+	}
+
+	// SMTPPasswordFlag defines the SMTP authentication password
+	SMTPPasswordFlag = &cli.StringFlag{
+		Name:        "smtp-password",
+		Usage:       "SMTP authentication password",
+		EnvVars:     []string{"SMTP_PASSWORD"},
+		Destination: &config.SMTPPassword,
+	}
+
+	// SMTPTLSModeFlag defines how the SMTP connection is secured
+	SMTPTLSModeFlag = &cli.StringFlag{
+		Name:        "smtp-tls",
+		Usage:       "SMTP TLS mode: starttls, implicit or none",
+		EnvVars:     []string{"SMTP_TLS"},
+		Value:       "starttls",
+		Destination: &config.SMTPTLSMode,
+	}
+
+	// SMTPAuthFlag defines the SMTP authentication mechanism
+	SMTPAuthFlag = &cli.StringFlag{
+		Name:        "smtp-auth",
+		Usage:       "SMTP authentication mechanism: plain or login",
+		EnvVars:     []string{"SMTP_AUTH"},
+		Value:       "plain",
+		Destination: &config.SMTPAuth,
+	}
+
+	// SMTPPoolSizeFlag defines how many idle SMTP connections are kept for reuse
+	SMTPPoolSizeFlag = &cli.IntFlag{
+		Name:        "smtp-pool-size",
+		Usage:       "Maximum number of idle SMTP connections kept open",
+		EnvVars:     []string{"SMTP_POOL_SIZE"},
+		Value:       5,
+		Destination: &config.SMTPPoolSize,
+	}
+
 	// FromAddressFlag defines the from email address
 	FromAddressFlag = &cli.StringFlag{
 		Name:        "from-address",
@@ -66,4 +171,77 @@ var (
 		Value:       "Mailman",
 		Destination: &config.FromName,
 	}
+
+	// ProviderRateLimitFlag limits how fast emails are handed to the provider
+	ProviderRateLimitFlag = &cli.StringFlag{
+		Name:        "provider-rate-limit",
+		Usage:       "Maximum sends through the provider, e.g. 100/s or 5000/h (unlimited when empty)",
+		EnvVars:     []string{"PROVIDER_RATE_LIMIT"},
+		Destination: &config.ProviderRateLimit,
+	}
+
+	// DomainRateLimitFlag limits how fast emails are sent to any one recipient domain
+	DomainRateLimitFlag = &cli.StringFlag{
+		Name:        "domain-rate-limit",
+		Usage:       "Maximum sends per recipient domain, e.g. 60/m (unlimited when empty)",
+		EnvVars:     []string{"DOMAIN_RATE_LIMIT"},
+		Destination: &config.DomainRateLimit,
+	}
+
+	// DomainRateLimitsFlag overrides the per-domain rate limit for specific domains
+	DomainRateLimitsFlag = &cli.StringSliceFlag{
+		Name:        "domain-rate-limits",
+		Usage:       "Per-domain overrides of --domain-rate-limit, e.g. example.com=10/m",
+		EnvVars:     []string{"DOMAIN_RATE_LIMITS"},
+		Destination: &config.DomainRateLimits,
+	}
+
+	// IdempotencyTTLFlag sets how long idempotency keys are remembered
+	IdempotencyTTLFlag = &cli.DurationFlag{
+		Name:        "idempotency-ttl",
+		Usage:       "How long a send's idempotency key returns the original message ID, e.g. 24h",
+		EnvVars:     []string{"IDEMPOTENCY_TTL"},
+		Value:       24 * time.Hour,
+		Destination: &config.IdempotencyTTL,
+	}
+
+	// DisableAuthFlag turns off API key authentication for the gRPC API
+	DisableAuthFlag = &cli.BoolFlag{
+		Name:        "disable-auth",
+		Usage:       "Accept gRPC calls without an API key (local development only)",
+		EnvVars:     []string{"DISABLE_AUTH"},
+		Destination: &config.DisableAuth,
+	}
+
+	// TLSCertFlag defines the certificate the gRPC server presents
+	TLSCertFlag = &cli.StringFlag{
+		Name:        "tls-cert",
+		Usage:       "PEM certificate for the gRPC server; reloaded when the file changes (plaintext when empty)",
+		EnvVars:     []string{"TLS_CERT_FILE"},
+		Destination: &config.TLSCertFile,
+	}
+
+	// TLSKeyFlag defines the private key of the gRPC server's certificate
+	TLSKeyFlag = &cli.StringFlag{
+		Name:        "tls-key",
+		Usage:       "PEM private key for --tls-cert",
+		EnvVars:     []string{"TLS_KEY_FILE"},
+		Destination: &config.TLSKeyFile,
+	}
+
+	// TLSClientCAFlag defines the CAs that gRPC client certificates must chain to
+	TLSClientCAFlag = &cli.StringFlag{
+		Name:        "tls-client-ca",
+		Usage:       "PEM CA bundle that client certificates must be signed by; enables mutual TLS",
+		EnvVars:     []string{"TLS_CLIENT_CA_FILE"},
+		Destination: &config.TLSClientCAFile,
+	}
+
+	// OTLPEndpointFlag defines the OpenTelemetry collector that traces are exported to
+	OTLPEndpointFlag = &cli.StringFlag{
+		Name:        "otlp-endpoint",
+		Usage:       "OTLP/gRPC collector URL for traces, e.g. http://localhost:4317 (disabled when empty)",
+		EnvVars:     []string{"OTEL_EXPORTER_OTLP_ENDPOINT"},
+		Destination: &config.OTLPEndpoint,
+	}
 )
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/travisbale/mailman/internal/db/postgres"
+	"github.com/travisbale/mailman/internal/email"
+	"github.com/travisbale/mailman/internal/queue/river"
+	"github.com/urfave/cli/v2"
+)
+
+// jobStates are the states jobs can be filtered by
+var jobStates = []email.State{
+	email.StateScheduled,
+	email.StateQueued,
+	email.StateSending,
+	email.StateSent,
+	email.StateRetrying,
+	email.StateFailed,
+	email.StateCancelled,
+}
+
+// jobsCmd provides email job inspection and dead-letter management commands
+var jobsCmd = &cli.Command{
+	Name:  "jobs",
+	Usage: "Inspect queued emails and manage those that failed",
+	Subcommands: []*cli.Command{
+		jobsListCmd,
+		jobsShowCmd,
+		jobsRetryCmd,
+		jobsCancelCmd,
+		jobsDiscardCmd,
+	},
+}
+
+// jobFilterFlags select the jobs that commands act on
+var jobFilterFlags = []cli.Flag{
+	&cli.StringSliceFlag{
+		Name:  "state",
+		Usage: "Only jobs in this state (repeatable): " + joinStates(jobStates),
+	},
+	&cli.StringFlag{
+		Name:  "template",
+		Usage: "Only jobs sent from this template",
+	},
+	&cli.StringFlag{
+		Name:  "recipient",
+		Usage: "Only jobs addressed to this recipient in To, CC or BCC",
+	},
+	&cli.StringFlag{
+		Name:  "since",
+		Usage: "Only jobs created since a time (RFC 3339) or a duration ago (e.g., 6h)",
+	},
+	&cli.StringFlag{
+		Name:  "until",
+		Usage: "Only jobs created before a time (RFC 3339) or a duration ago (e.g., 1h)",
+	},
+}
+
+// jobsListCmd lists email jobs
+var jobsListCmd = &cli.Command{
+	Name:  "list",
+	Usage: "List email jobs, newest first",
+	Flags: append([]cli.Flag{
+		&cli.IntFlag{
+			Name:  "limit",
+			Usage: "Maximum number of jobs to list",
+			Value: 50,
+		},
+		&cli.StringFlag{
+			Name:  "page-token",
+			Usage: "Continue a previous listing from its next page token",
+		},
+	}, jobFilterFlags...),
+	Action: func(c *cli.Context) error {
+		ctx := c.Context
+
+		filter, err := jobFilter(c)
+		if err != nil {
+			return err
+		}
+
+		return withEmailService(ctx, func(emailService *email.Service) error {
+			jobs, nextPageToken, err := emailService.ListJobs(ctx, filter, c.Int("limit"), c.String("page-token"))
+			if err != nil {
+				return fmt.Errorf("failed to list jobs: %w", err)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+			if _, err := fmt.Fprintln(w, "MESSAGE ID\tSTATE\tTEMPLATE\tTO\tATTEMPTS\tCREATED\tLAST ERROR"); err != nil {
+				return fmt.Errorf("failed to write header: %w", err)
+			}
+			if _, err := fmt.Fprintln(w, "----------\t-----\t--------\t--\t--------\t-------\t----------"); err != nil {
+				return fmt.Errorf("failed to write separator: %w", err)
+			}
+
+			for _, job := range jobs {
+				if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d/%d\t%s\t%s\n",
+					job.MessageID,
+					job.State,
+					orDash(job.TemplateName),
+					orDash(job.To.String()),
+					job.Attempts,
+					job.MaxAttempts,
+					job.CreatedAt.Format("2006-01-02 15:04:05"),
+					orDash(truncate(job.LastError, 60)),
+				); err != nil {
+					return fmt.Errorf("failed to write job row: %w", err)
+				}
+			}
+
+			if err := w.Flush(); err != nil {
+				return fmt.Errorf("failed to flush output: %w", err)
+			}
+
+			if nextPageToken != "" {
+				fmt.Printf("\nMore jobs match; continue with --page-token %s\n", nextPageToken)
+			}
+
+			return nil
+		})
+	},
+}
+
+// jobsShowCmd shows an email job and the error from each failed attempt
+var jobsShowCmd = &cli.Command{
+	Name:  "show",
+	Usage: "Show an email job with the error from every failed attempt",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "id",
+			Usage:    "Message ID",
+			Required: true,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		ctx := c.Context
+
+		return withEmailService(ctx, func(emailService *email.Service) error {
+			job, err := emailService.GetJob(ctx, c.String("id"))
+			if err != nil {
+				return fmt.Errorf("failed to get job: %w", err)
+			}
+
+			fmt.Printf("Message ID: %s\n", job.MessageID)
+			fmt.Printf("  State: %s\n", job.State)
+			fmt.Printf("  Template: %s\n", orDash(job.TemplateName))
+			fmt.Printf("  From: %s\n", job.From)
+			fmt.Printf("  To: %s\n", job.To)
+			if len(job.CC) > 0 {
+				fmt.Printf("  CC: %s\n", job.CC)
+			}
+			if len(job.BCC) > 0 {
+				fmt.Printf("  BCC: %s\n", job.BCC)
+			}
+			fmt.Printf("  Subject: %s\n", job.Subject)
+			fmt.Printf("  Attempts: %d/%d\n", job.Attempts, job.MaxAttempts)
+			fmt.Printf("  Created: %s\n", job.CreatedAt.Format(time.RFC3339))
+			fmt.Printf("  Scheduled: %s\n", job.ScheduledAt.Format(time.RFC3339))
+			if job.FinalizedAt != nil {
+				fmt.Printf("  Finalized: %s\n", job.FinalizedAt.Format(time.RFC3339))
+			}
+			if job.Provider != "" {
+				fmt.Printf("  Provider: %s\n", job.Provider)
+			}
+
+			if len(job.Errors) > 0 {
+				fmt.Printf("\nErrors:\n")
+				for _, jobErr := range job.Errors {
+					fmt.Printf("  Attempt %d (%s): %s\n", jobErr.Attempt, jobErr.OccurredAt.Format(time.RFC3339), jobErr.Error)
+				}
+			}
+
+			return nil
+		})
+	},
+}
+
+// jobsRetryCmd retries failed emails
+var jobsRetryCmd = &cli.Command{
+	Name:  "retry",
+	Usage: "Retry failed emails straight away, such as those that ran out of attempts during a provider outage",
+	Flags: jobChangeFlags,
+	Action: func(c *cli.Context) error {
+		return changeJobs(c, "retried", func(emailService *email.Service) jobChange {
+			return jobChange{emailService.RetryMessages, emailService.RetryJobs}
+		})
+	},
+}
+
+// jobsCancelCmd cancels pending emails
+var jobsCancelCmd = &cli.Command{
+	Name:  "cancel",
+	Usage: "Stop pending emails from being sent",
+	Flags: jobChangeFlags,
+	Action: func(c *cli.Context) error {
+		return changeJobs(c, "cancelled", func(emailService *email.Service) jobChange {
+			return jobChange{emailService.CancelMessages, emailService.CancelJobs}
+		})
+	},
+}
+
+// jobsDiscardCmd deletes failed and cancelled emails
+var jobsDiscardCmd = &cli.Command{
+	Name:  "discard",
+	Usage: "Delete failed or cancelled emails from the queue",
+	Flags: jobChangeFlags,
+	Action: func(c *cli.Context) error {
+		return changeJobs(c, "discarded", func(emailService *email.Service) jobChange {
+			return jobChange{emailService.DiscardMessages, emailService.DiscardJobs}
+		})
+	},
+}
+
+// jobChangeFlags select the jobs that retry, cancel and discard change
+var jobChangeFlags = append([]cli.Flag{
+	&cli.StringSliceFlag{
+		Name:  "id",
+		Usage: "Message ID (repeatable); the filter flags are used instead if omitted",
+	},
+	&cli.BoolFlag{
+		Name:  "all",
+		Usage: "Change every eligible job when no --id or filter flags are given",
+	},
+}, jobFilterFlags...)
+
+// jobChange changes the jobs listed by message ID, or every eligible job matching a filter
+type jobChange struct {
+	each func(context.Context, []string) (int, []email.SkippedJob, error)
+	all  func(context.Context, email.JobFilter) (int, error)
+}
+
+// changeJobs applies a change to the jobs selected by --id, or to every job
+// matching the filter flags, and reports how many were changed
+func changeJobs(c *cli.Context, verb string, changeFor func(*email.Service) jobChange) error {
+	ctx := c.Context
+
+	messageIDs := c.StringSlice("id")
+	filtered := slices.ContainsFunc(jobFilterFlags, func(flag cli.Flag) bool {
+		return c.IsSet(flag.Names()[0])
+	})
+
+	switch {
+	case len(messageIDs) > 0 && (filtered || c.Bool("all")):
+		return errors.New("--id cannot be combined with filter flags or --all")
+	case len(messageIDs) == 0 && !filtered && !c.Bool("all"):
+		return errors.New("select jobs with --id or filter flags, or pass --all")
+	}
+
+	filter, err := jobFilter(c)
+	if err != nil {
+		return err
+	}
+
+	return withEmailService(ctx, func(emailService *email.Service) error {
+		change := changeFor(emailService)
+
+		if len(messageIDs) == 0 {
+			changed, err := change.all(ctx, filter)
+			if err != nil {
+				return fmt.Errorf("failed after %d jobs %s: %w", changed, verb, err)
+			}
+			fmt.Printf("%d jobs %s\n", changed, verb)
+			return nil
+		}
+
+		changed, skipped, err := change.each(ctx, messageIDs)
+		for _, job := range skipped {
+			fmt.Printf("Skipped %s: %v\n", job.MessageID, job.Err)
+		}
+		if err != nil {
+			return fmt.Errorf("failed after %d jobs %s: %w", changed, verb, err)
+		}
+		fmt.Printf("%d jobs %s\n", changed, verb)
+
+		return nil
+	})
+}
+
+// withEmailService calls fn with an email service reading and changing jobs in
+// the database
+func withEmailService(ctx context.Context, fn func(*email.Service) error) error {
+	db, err := postgres.NewDB(ctx, config.DatabaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	// The queue is only inspected and changed here, never worked
+	jobQueue, err := river.NewJobQueue(db, nil, 0)
+	if err != nil {
+		return err
+	}
+
+	return fn(&email.Service{Queue: jobQueue})
+}
+
+// jobFilter builds a job filter from the filter flags
+func jobFilter(c *cli.Context) (email.JobFilter, error) {
+	filter := email.JobFilter{
+		TemplateName: c.String("template"),
+	}
+
+	for _, value := range c.StringSlice("state") {
+		state := email.State(strings.ToLower(value))
+		if !slices.Contains(jobStates, state) {
+			return email.JobFilter{}, fmt.Errorf("invalid state %q: use %s", value, joinStates(jobStates))
+		}
+		filter.States = append(filter.States, state)
+	}
+
+	if recipient := c.String("recipient"); recipient != "" {
+		addr, err := email.ParseAddress(recipient)
+		if err != nil {
+			return email.JobFilter{}, err
+		}
+		filter.Recipient = addr.Email
+	}
+
+	var err error
+	if filter.CreatedAfter, err = parseJobTime(c.String("since")); err != nil {
+		return email.JobFilter{}, fmt.Errorf("invalid --since: %w", err)
+	}
+	if filter.CreatedBefore, err = parseJobTime(c.String("until")); err != nil {
+		return email.JobFilter{}, fmt.Errorf("invalid --until: %w", err)
+	}
+
+	return filter, nil
+}
+
+// parseJobTime parses an RFC 3339 time or a duration before now. An empty value
+// gives nil.
+func parseJobTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if ago, err := time.ParseDuration(value); err == nil {
+		t := time.Now().Add(-ago)
+		return &t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%q is neither an RFC 3339 time nor a duration", value)
+	}
+	return &t, nil
+}
+
+// joinStates lists states for flag usage and errors
+func joinStates(states []email.State) string {
+	names := make([]string, len(states))
+	for i, state := range states {
+		names[i] = string(state)
+	}
+	return strings.Join(names, ", ")
+}
+
+// truncate shortens s to at most n runes for display
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
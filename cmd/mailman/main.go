@@ -5,6 +5,8 @@ import (
 	"log/slog"
 	"os"
 
+	"github.com/travisbale/mailman/internal/email"
+	"github.com/travisbale/mailman/internal/logging"
 	"github.com/urfave/cli/v2"
 )
 
@@ -14,18 +16,25 @@ func main() {
 		Usage: "Internal email service for payroll platform",
 		Flags: []cli.Flag{
 			DebugFlag,
+			LogFormatFlag,
+			LogLevelFlag,
 			DatabaseURLFlag,
+			TenantFlag,
 		},
 		Before: func(c *cli.Context) error {
-			var level slog.Level
+			level := config.LogLevel
 			if config.Debug {
-				level = slog.LevelDebug
-			} else {
-				level = slog.LevelInfo
+				level = "debug"
 			}
 
-			opts := &slog.HandlerOptions{Level: level}
-			slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, opts)))
+			logger, err := logging.New(os.Stderr, config.LogFormat, level)
+			if err != nil {
+				return err
+			}
+			slog.SetDefault(logger)
+
+			// Template, sender, suppression, API key and job commands act for the selected tenant
+			c.Context = email.WithTenant(c.Context, config.Tenant)
 
 			return nil
 		},
@@ -33,6 +42,11 @@ func main() {
 			startCmd,
 			migrateCmd,
 			templateCmd,
+			senderCmd,
+			suppressionCmd,
+			apiKeyCmd,
+			tenantCmd,
+			jobsCmd,
 			versionCmd,
 		},
 	}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/travisbale/mailman/internal/db/postgres"
+	"github.com/travisbale/mailman/internal/email"
+	"github.com/urfave/cli/v2"
+)
+
+// senderCmd provides sender identity management commands
+var senderCmd = &cli.Command{
+	Name:  "sender",
+	Usage: "Manage the sender identities emails may be sent from",
+	Subcommands: []*cli.Command{
+		senderAddCmd,
+		senderListCmd,
+		senderRemoveCmd,
+	},
+}
+
+// senderAddCmd registers a sender identity
+var senderAddCmd = &cli.Command{
+	Name:  "add",
+	Usage: "Allow emails to be sent from an address",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "address",
+			Usage:    "Sender email address (e.g., billing@example.com)",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "name",
+			Usage: "Display name shown to recipients (e.g., Example Billing)",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		ctx := c.Context
+
+		db, err := postgres.NewDB(ctx, config.DatabaseURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		senderService := email.NewSenderService(postgres.NewSendersDB(db))
+
+		sender, err := senderService.AddSender(ctx, c.String("address"), c.String("name"))
+		if err != nil {
+			return fmt.Errorf("failed to add sender: %w", err)
+		}
+
+		fmt.Printf("Sender added successfully\n")
+		fmt.Printf("  Address: %s\n", sender.Address)
+		if sender.Name != "" {
+			fmt.Printf("  Name: %s\n", sender.Name)
+		}
+
+		return nil
+	},
+}
+
+// senderListCmd lists the registered sender identities
+var senderListCmd = &cli.Command{
+	Name:  "list",
+	Usage: "List allowed sender identities",
+	Action: func(c *cli.Context) error {
+		ctx := c.Context
+
+		db, err := postgres.NewDB(ctx, config.DatabaseURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		senderService := email.NewSenderService(postgres.NewSendersDB(db))
+
+		senders, err := senderService.ListSenders(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list senders: %w", err)
+		}
+
+		if len(senders) == 0 {
+			fmt.Println("No senders found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		if _, err := fmt.Fprintln(w, "ADDRESS\tNAME\tCREATED"); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+		if _, err := fmt.Fprintln(w, "-------\t----\t-------"); err != nil {
+			return fmt.Errorf("failed to write separator: %w", err)
+		}
+
+		for _, sender := range senders {
+			name := "-"
+			if sender.Name != "" {
+				name = sender.Name
+			}
+
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n",
+				sender.Address,
+				name,
+				sender.CreatedAt.Format("2006-01-02"),
+			); err != nil {
+				return fmt.Errorf("failed to write sender row: %w", err)
+			}
+		}
+
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("failed to flush output: %w", err)
+		}
+
+		return nil
+	},
+}
+
+// senderRemoveCmd unregisters a sender identity
+var senderRemoveCmd = &cli.Command{
+	Name:  "remove",
+	Usage: "Stop allowing emails to be sent from an address",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "address",
+			Usage:    "Sender email address",
+			Required: true,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		ctx := c.Context
+
+		db, err := postgres.NewDB(ctx, config.DatabaseURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		senderService := email.NewSenderService(postgres.NewSendersDB(db))
+
+		if err := senderService.RemoveSender(ctx, c.String("address")); err != nil {
+			return fmt.Errorf("failed to remove sender: %w", err)
+		}
+
+		fmt.Printf("Sender removed: %s\n", c.String("address"))
+
+		return nil
+	},
+}
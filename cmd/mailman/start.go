@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
@@ -20,12 +21,33 @@ var startCmd = &cli.Command{
 	Flags: []cli.Flag{
 		HTTPAddressFlag,
 		GRPCAddressFlag,
+		ProvidersFlag,
 		SendGridAPIKeyFlag,
+		SendGridWebhookKeyFlag,
+		SMTPHostFlag,
+		SMTPPortFlag,
+		SMTPUsernameFlag,
+		SMTPPasswordFlag,
+		SMTPTLSModeFlag,
+		SMTPAuthFlag,
+		SMTPPoolSizeFlag,
 		FromAddressFlag,
 		FromNameFlag,
+		ProviderRateLimitFlag,
+		DomainRateLimitFlag,
+		DomainRateLimitsFlag,
+		IdempotencyTTLFlag,
+		DisableAuthFlag,
+		TLSCertFlag,
+		TLSKeyFlag,
+		TLSClientCAFlag,
+		OTLPEndpointFlag,
 	},
 	Action: func(c *cli.Context) error {
-		appConfig := config.ToAppConfig()
+		appConfig, err := config.ToAppConfig()
+		if err != nil {
+			return err
+		}
 
 		server, err := app.NewServer(c.Context, appConfig)
 		if err != nil {
@@ -38,13 +60,13 @@ var startCmd = &cli.Command{
 		group, ctx := errgroup.WithContext(ctx)
 
 		group.Go(func() error {
-			fmt.Printf("Starting mailman service (HTTP: %s, gRPC: %s)\n", appConfig.HTTPAddress, appConfig.GRPCAddress)
+			slog.Info("Starting mailman service", "version", Version)
 			return server.Start(ctx)
 		})
 
 		group.Go(func() error {
 			<-ctx.Done()
-			fmt.Println("Shutting down gracefully...")
+			slog.Info("Shutting down gracefully")
 
 			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 			defer cancel()
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/travisbale/mailman/internal/db/postgres"
+	"github.com/travisbale/mailman/internal/email"
+	"github.com/urfave/cli/v2"
+)
+
+// suppressionCmd provides suppression list management commands
+var suppressionCmd = &cli.Command{
+	Name:  "suppression",
+	Usage: "Manage the addresses emails must not be sent to",
+	Subcommands: []*cli.Command{
+		suppressionAddCmd,
+		suppressionListCmd,
+		suppressionRemoveCmd,
+	},
+}
+
+// suppressionAddCmd suppresses an address
+var suppressionAddCmd = &cli.Command{
+	Name:  "add",
+	Usage: "Stop sending emails to an address",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "address",
+			Usage:    "Recipient email address",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "reason",
+			Usage: "Why the address is suppressed: bounce, complaint, unsubscribe or manual",
+			Value: email.SuppressionReasonManual,
+		},
+		&cli.DurationFlag{
+			Name:  "expires-in",
+			Usage: "Lift the suppression after this long (e.g., 720h); permanent if omitted",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		ctx := c.Context
+
+		db, err := postgres.NewDB(ctx, config.DatabaseURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		suppressionService := email.NewSuppressionService(postgres.NewSuppressionsDB(db))
+
+		var expiresAt *time.Time
+		if expiresIn := c.Duration("expires-in"); expiresIn > 0 {
+			t := time.Now().Add(expiresIn)
+			expiresAt = &t
+		}
+
+		suppression, err := suppressionService.Suppress(ctx, c.String("address"), c.String("reason"), "cli", expiresAt)
+		if err != nil {
+			return fmt.Errorf("failed to add suppression: %w", err)
+		}
+
+		fmt.Printf("Suppression added successfully\n")
+		fmt.Printf("  Address: %s\n", suppression.Address)
+		fmt.Printf("  Reason: %s\n", suppression.Reason)
+		if suppression.ExpiresAt != nil {
+			fmt.Printf("  Expires: %s\n", suppression.ExpiresAt.Format(time.RFC3339))
+		}
+
+		return nil
+	},
+}
+
+// suppressionListCmd lists the suppression list
+var suppressionListCmd = &cli.Command{
+	Name:  "list",
+	Usage: "List suppressed addresses",
+	Action: func(c *cli.Context) error {
+		ctx := c.Context
+
+		db, err := postgres.NewDB(ctx, config.DatabaseURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		suppressionService := email.NewSuppressionService(postgres.NewSuppressionsDB(db))
+
+		suppressions, err := suppressionService.ListSuppressions(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list suppressions: %w", err)
+		}
+
+		if len(suppressions) == 0 {
+			fmt.Println("No suppressions found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		if _, err := fmt.Fprintln(w, "ADDRESS\tREASON\tSOURCE\tEXPIRES\tCREATED"); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+		if _, err := fmt.Fprintln(w, "-------\t------\t------\t-------\t-------"); err != nil {
+			return fmt.Errorf("failed to write separator: %w", err)
+		}
+
+		for _, suppression := range suppressions {
+			source := "-"
+			if suppression.Source != "" {
+				source = suppression.Source
+			}
+
+			expires := "never"
+			if suppression.ExpiresAt != nil {
+				expires = suppression.ExpiresAt.Format("2006-01-02 15:04")
+			}
+
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				suppression.Address,
+				suppression.Reason,
+				source,
+				expires,
+				suppression.CreatedAt.Format("2006-01-02"),
+			); err != nil {
+				return fmt.Errorf("failed to write suppression row: %w", err)
+			}
+		}
+
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("failed to flush output: %w", err)
+		}
+
+		return nil
+	},
+}
+
+// suppressionRemoveCmd lifts the suppression on an address
+var suppressionRemoveCmd = &cli.Command{
+	Name:  "remove",
+	Usage: "Allow emails to be sent to an address again",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "address",
+			Usage:    "Recipient email address",
+			Required: true,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		ctx := c.Context
+
+		db, err := postgres.NewDB(ctx, config.DatabaseURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		suppressionService := email.NewSuppressionService(postgres.NewSuppressionsDB(db))
+
+		if err := suppressionService.RemoveSuppression(ctx, c.String("address")); err != nil {
+			return fmt.Errorf("failed to remove suppression: %w", err)
+		}
+
+		fmt.Printf("Suppression removed: %s\n", c.String("address"))
+
+		return nil
+	},
+}
@@ -1,7 +1,7 @@
 package main
 
 import (
-	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -9,6 +9,7 @@ import (
 
 	"github.com/travisbale/mailman/internal/db/postgres"
 	"github.com/travisbale/mailman/internal/email"
+	"github.com/travisbale/mailman/internal/renderers/html"
 	"github.com/urfave/cli/v2"
 )
 
@@ -19,6 +20,10 @@ var templateCmd = &cli.Command{
 	Subcommands: []*cli.Command{
 		templateAddCmd,
 		templateListCmd,
+		templateVersionsCmd,
+		templateDiffCmd,
+		templateRollbackCmd,
+		templateRenderCmd,
 	},
 }
 
@@ -32,6 +37,10 @@ var templateAddCmd = &cli.Command{
 			Usage:    "Template name (e.g., welcome_email, password_reset)",
 			Required: true,
 		},
+		&cli.StringFlag{
+			Name:  "locale",
+			Usage: "Locale of this variant (e.g., fr-CA); omit to add the default variant other locales fall back to",
+		},
 		&cli.StringFlag{
 			Name:     "subject",
 			Usage:    "Email subject line (supports Go template syntax)",
@@ -54,6 +63,15 @@ var templateAddCmd = &cli.Command{
 			Name:  "vars",
 			Usage: "Comma-separated list of required template variables (e.g., UserName,AppName)",
 		},
+		&cli.StringFlag{
+			Name:  "sender",
+			Usage: "Default sender address, which must be registered with 'mailman sender add' (optional)",
+		},
+		&cli.StringFlag{
+			Name:  "suppression-policy",
+			Usage: "What to do when a recipient is suppressed: 'reject' fails the send, 'drop' skips the recipient",
+			Value: email.SuppressionPolicyReject,
+		},
 		&cli.IntFlag{
 			Name:  "version",
 			Usage: "Template version number",
@@ -84,9 +102,16 @@ var templateAddCmd = &cli.Command{
 
 		fmt.Printf("Template created successfully\n")
 		fmt.Printf("  Name: %s\n", created.Name)
+		if created.Locale != "" {
+			fmt.Printf("  Locale: %s\n", created.Locale)
+		}
 		if created.BaseTemplateName != nil {
 			fmt.Printf("  Base template: %s\n", *created.BaseTemplateName)
 		}
+		if created.DefaultSender != nil {
+			fmt.Printf("  Default sender: %s\n", *created.DefaultSender)
+		}
+		fmt.Printf("  Suppression policy: %s\n", created.SuppressionPolicy)
 		fmt.Printf("  Version: %d\n", created.Version)
 		if len(created.Variables) > 0 {
 			fmt.Printf("  Variables: %s\n", strings.Join(created.Variables, ", "))
@@ -134,14 +159,27 @@ func buildTemplate(c *cli.Context) (*email.Template, error) {
 		baseTemplatePtr = &baseName
 	}
 
+	var defaultSenderPtr *string
+	if sender := c.String("sender"); sender != "" {
+		defaultSenderPtr = &sender
+	}
+
+	policy := c.String("suppression-policy")
+	if policy != email.SuppressionPolicyReject && policy != email.SuppressionPolicyDrop {
+		return nil, fmt.Errorf("invalid suppression policy %q: must be reject or drop", policy)
+	}
+
 	return &email.Template{
-		Name:             c.String("name"),
-		Subject:          c.String("subject"),
-		HTMLBody:         htmlBody,
-		TextBody:         textBodyPtr,
-		BaseTemplateName: baseTemplatePtr,
-		Variables:        vars,
-		Version:          int32(c.Int("version")),
+		Name:              c.String("name"),
+		Locale:            c.String("locale"),
+		Subject:           c.String("subject"),
+		HTMLBody:          htmlBody,
+		TextBody:          textBodyPtr,
+		BaseTemplateName:  baseTemplatePtr,
+		Variables:         vars,
+		DefaultSender:     defaultSenderPtr,
+		SuppressionPolicy: policy,
+		Version:           int32(c.Int("version")),
 	}, nil
 }
 
@@ -150,7 +188,7 @@ var templateListCmd = &cli.Command{
 	Name:  "list",
 	Usage: "List all email templates",
 	Action: func(c *cli.Context) error {
-		ctx := context.Background()
+		ctx := c.Context
 
 		db, err := postgres.NewDB(ctx, config.DatabaseURL)
 		if err != nil {
@@ -173,14 +211,19 @@ var templateListCmd = &cli.Command{
 
 		// Print templates in table format
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		if _, err := fmt.Fprintln(w, "NAME\tBASE\tVERSION\tVARIABLES\tCREATED"); err != nil {
+		if _, err := fmt.Fprintln(w, "NAME\tLOCALE\tBASE\tVERSION\tVARIABLES\tCREATED"); err != nil {
 			return fmt.Errorf("failed to write header: %w", err)
 		}
-		if _, err := fmt.Fprintln(w, "----\t----\t-------\t---------\t-------"); err != nil {
+		if _, err := fmt.Fprintln(w, "----\t------\t----\t-------\t---------\t-------"); err != nil {
 			return fmt.Errorf("failed to write separator: %w", err)
 		}
 
 		for _, tmpl := range templates {
+			locale := "-"
+			if tmpl.Locale != "" {
+				locale = tmpl.Locale
+			}
+
 			base := "-"
 			if tmpl.BaseTemplateName != nil {
 				base = *tmpl.BaseTemplateName
@@ -191,8 +234,9 @@ var templateListCmd = &cli.Command{
 				vars = strings.Join(tmpl.Variables, ", ")
 			}
 
-			if _, err := fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n",
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n",
 				tmpl.Name,
+				locale,
 				base,
 				tmpl.Version,
 				vars,
@@ -209,3 +253,289 @@ var templateListCmd = &cli.Command{
 		return nil
 	},
 }
+
+// templateVersionsCmd lists the published revisions of a template
+var templateVersionsCmd = &cli.Command{
+	Name:  "versions",
+	Usage: "List the version history of a template",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "name",
+			Usage:    "Template name",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "locale",
+			Usage: "Locale of the variant (e.g., fr-CA); omit for the default variant",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		ctx := c.Context
+
+		db, err := postgres.NewDB(ctx, config.DatabaseURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		templateService := email.NewTemplateService(postgres.NewTemplatesDB(db))
+
+		versions, err := templateService.ListTemplateVersions(ctx, c.String("name"), c.String("locale"))
+		if err != nil {
+			return fmt.Errorf("failed to list template versions: %w", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		if _, err := fmt.Fprintln(w, "VERSION\tSUBJECT\tBASE\tVARIABLES\tPUBLISHED"); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+		if _, err := fmt.Fprintln(w, "-------\t-------\t----\t---------\t---------"); err != nil {
+			return fmt.Errorf("failed to write separator: %w", err)
+		}
+
+		for _, v := range versions {
+			base := "-"
+			if v.BaseTemplateName != nil {
+				base = *v.BaseTemplateName
+			}
+
+			vars := "-"
+			if len(v.Variables) > 0 {
+				vars = strings.Join(v.Variables, ", ")
+			}
+
+			if _, err := fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n",
+				v.Version,
+				v.Subject,
+				base,
+				vars,
+				v.CreatedAt.Format("2006-01-02 15:04:05"),
+			); err != nil {
+				return fmt.Errorf("failed to write version row: %w", err)
+			}
+		}
+
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("failed to flush output: %w", err)
+		}
+
+		return nil
+	},
+}
+
+// templateDiffCmd shows what changed between two revisions of a template
+var templateDiffCmd = &cli.Command{
+	Name:  "diff",
+	Usage: "Show the differences between two versions of a template",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "name",
+			Usage:    "Template name",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "locale",
+			Usage: "Locale of the variant (e.g., fr-CA); omit for the default variant",
+		},
+		&cli.IntFlag{
+			Name:     "from",
+			Usage:    "Older version to compare",
+			Required: true,
+		},
+		&cli.IntFlag{
+			Name:  "to",
+			Usage: "Newer version to compare (defaults to the current version)",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		ctx := c.Context
+		name := c.String("name")
+		locale := c.String("locale")
+
+		db, err := postgres.NewDB(ctx, config.DatabaseURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		templateService := email.NewTemplateService(postgres.NewTemplatesDB(db))
+
+		from, err := templateService.GetTemplateVersion(ctx, name, locale, int32(c.Int("from")))
+		if err != nil {
+			return fmt.Errorf("failed to load version %d: %w", c.Int("from"), err)
+		}
+
+		var to *email.Template
+		if c.IsSet("to") {
+			to, err = templateService.GetTemplateVersion(ctx, name, locale, int32(c.Int("to")))
+		} else {
+			to, err = templateService.GetTemplate(ctx, name, locale)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load version to compare: %w", err)
+		}
+
+		fmt.Printf("--- %s version %d\n", email.DescribeTemplate(name, from.Locale), from.Version)
+		fmt.Printf("+++ %s version %d\n", email.DescribeTemplate(name, to.Locale), to.Version)
+
+		fields := []struct {
+			label    string
+			from, to string
+		}{
+			{"subject", from.Subject, to.Subject},
+			{"base", derefString(from.BaseTemplateName), derefString(to.BaseTemplateName)},
+			{"variables", strings.Join(from.Variables, ", "), strings.Join(to.Variables, ", ")},
+			{"default_sender", derefString(from.DefaultSender), derefString(to.DefaultSender)},
+			{"suppression_policy", from.SuppressionPolicy, to.SuppressionPolicy},
+			{"html_body", from.HTMLBody, to.HTMLBody},
+			{"text_body", derefString(from.TextBody), derefString(to.TextBody)},
+		}
+
+		changed := false
+		for _, f := range fields {
+			if f.from == f.to {
+				continue
+			}
+			changed = true
+
+			fmt.Printf("@@ %s @@\n", f.label)
+			for _, line := range diffLines(f.from, f.to) {
+				fmt.Println(line)
+			}
+		}
+
+		if !changed {
+			fmt.Println("No differences.")
+		}
+
+		return nil
+	},
+}
+
+// templateRollbackCmd republishes the content of an earlier revision
+var templateRollbackCmd = &cli.Command{
+	Name:  "rollback",
+	Usage: "Publish the content of an earlier version as a new version",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "name",
+			Usage:    "Template name",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "locale",
+			Usage: "Locale of the variant (e.g., fr-CA); omit for the default variant",
+		},
+		&cli.IntFlag{
+			Name:     "version",
+			Usage:    "Version whose content should be restored",
+			Required: true,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		ctx := c.Context
+
+		db, err := postgres.NewDB(ctx, config.DatabaseURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		templateService := email.NewTemplateService(postgres.NewTemplatesDB(db))
+
+		restored, err := templateService.RollbackTemplate(ctx, c.String("name"), c.String("locale"), int32(c.Int("version")))
+		if err != nil {
+			return fmt.Errorf("failed to roll back template: %w", err)
+		}
+
+		fmt.Printf("Template rolled back successfully\n")
+		fmt.Printf("  Name: %s\n", email.DescribeTemplate(restored.Name, restored.Locale))
+		fmt.Printf("  Restored content from version: %d\n", c.Int("version"))
+		fmt.Printf("  New version: %d\n", restored.Version)
+
+		return nil
+	},
+}
+
+// templateRenderCmd previews a template with sample variables without sending anything
+var templateRenderCmd = &cli.Command{
+	Name:  "render",
+	Usage: "Render a template with the given variables without sending it",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "name",
+			Usage:    "Template name",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "locale",
+			Usage: "Locale of the variant (e.g., fr-CA); omit for the default variant",
+		},
+		&cli.IntFlag{
+			Name:  "version",
+			Usage: "Version to render (defaults to the latest)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "var",
+			Usage: "Template variable as key=value (repeatable)",
+		},
+		&cli.StringFlag{
+			Name:  "data",
+			Usage: `Structured variables as a JSON object, e.g. '{"Items": [{"Name": "Mug"}]}'`,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		ctx := c.Context
+
+		variables := make(map[string]any)
+		if data := c.String("data"); data != "" {
+			if err := json.Unmarshal([]byte(data), &variables); err != nil {
+				return fmt.Errorf("invalid --data: %w", err)
+			}
+		}
+		for _, v := range c.StringSlice("var") {
+			key, value, ok := strings.Cut(v, "=")
+			if !ok {
+				return fmt.Errorf("invalid --var %q: expected key=value", v)
+			}
+			variables[key] = value
+		}
+
+		db, err := postgres.NewDB(ctx, config.DatabaseURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		templatesDB := postgres.NewTemplatesDB(db)
+		emailService := &email.Service{
+			Templates: templatesDB,
+			Renderer:  html.New(),
+		}
+
+		rendered, err := emailService.Render(ctx, email.RenderRequest{
+			TemplateName:    c.String("name"),
+			TemplateVersion: int32(c.Int("version")),
+			Locale:          c.String("locale"),
+			Variables:       variables,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to render template: %w", err)
+		}
+
+		fmt.Printf("Subject: %s\n", rendered.Subject)
+		fmt.Printf("\n--- HTML ---\n%s\n", rendered.HTMLBody)
+		if rendered.TextBody != "" {
+			fmt.Printf("\n--- Text ---\n%s\n", rendered.TextBody)
+		}
+
+		return nil
+	},
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/travisbale/mailman/internal/app"
+	"github.com/travisbale/mailman/internal/db/postgres"
+	"github.com/travisbale/mailman/internal/email"
+	"github.com/urfave/cli/v2"
+)
+
+// tenantCmd provides tenant management commands
+var tenantCmd = &cli.Command{
+	Name:  "tenant",
+	Usage: "Manage the tenants that share the server",
+	Subcommands: []*cli.Command{
+		tenantAddCmd,
+		tenantListCmd,
+		tenantUpdateCmd,
+	},
+}
+
+// tenantFlags are the settings shared by tenant add and update
+var tenantFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "name",
+		Usage: "Display name (e.g., Payroll)",
+	},
+	&cli.StringFlag{
+		Name:  "from-address",
+		Usage: "Default from address, replacing the server's FROM_ADDRESS",
+	},
+	&cli.StringFlag{
+		Name:  "from-name",
+		Usage: "Default from name, used with --from-address",
+	},
+	&cli.StringFlag{
+		Name:  "provider",
+		Usage: "Provider to send through before the server's others: " + strings.Join(app.ProviderNames, ", "),
+	},
+	&cli.StringFlag{
+		Name:  "sendgrid-api-key",
+		Usage: "The tenant's own SendGrid API key, used with --provider sendgrid",
+	},
+	&cli.StringFlag{
+		Name:  "sendgrid-webhook-key",
+		Usage: "Verification key of the tenant's own SendGrid event webhook, which posts to /webhooks/sendgrid/<tenant ID>",
+	},
+	&cli.StringFlag{
+		Name:  "smtp-host",
+		Usage: "The tenant's own SMTP relay, used with --provider smtp",
+	},
+	&cli.IntFlag{
+		Name:  "smtp-port",
+		Usage: "SMTP relay port (default: 587, or 465 with --smtp-tls implicit)",
+	},
+	&cli.StringFlag{
+		Name:  "smtp-username",
+		Usage: "SMTP authentication username",
+	},
+	&cli.StringFlag{
+		Name:  "smtp-password",
+		Usage: "SMTP authentication password",
+	},
+	&cli.StringFlag{
+		Name:  "smtp-tls",
+		Usage: "SMTP TLS mode: starttls, implicit or none (default: starttls)",
+	},
+	&cli.StringFlag{
+		Name:  "smtp-auth",
+		Usage: "SMTP authentication mechanism: plain or login (default: plain)",
+	},
+	&cli.BoolFlag{
+		Name:  "provider-fallback",
+		Usage: "Fail over from the tenant's own account to the server's providers, sending under the server's account",
+	},
+}
+
+// credentialFlags are the tenant flags holding its own provider credentials
+var credentialFlags = []string{"sendgrid-api-key", "sendgrid-webhook-key", "smtp-host", "smtp-port", "smtp-username", "smtp-password", "smtp-tls", "smtp-auth"}
+
+// tenantAddCmd creates a tenant
+var tenantAddCmd = &cli.Command{
+	Name:  "add",
+	Usage: "Add a tenant with its own templates and API keys",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:     "id",
+			Usage:    "Tenant ID used by --tenant (e.g., payroll)",
+			Required: true,
+		},
+	}, tenantFlags...),
+	Action: func(c *cli.Context) error {
+		ctx := c.Context
+
+		tenant := &email.Tenant{
+			ID:               c.String("id"),
+			Name:             c.String("name"),
+			FromAddress:      c.String("from-address"),
+			FromName:         c.String("from-name"),
+			Provider:         c.String("provider"),
+			ProviderFallback: c.Bool("provider-fallback"),
+		}
+		setCredentials(c, tenant)
+		if err := app.CheckTenantProvider(tenant); err != nil {
+			return err
+		}
+
+		db, err := postgres.NewDB(ctx, config.DatabaseURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		tenantService := email.NewTenantService(postgres.NewTenantsDB(db))
+
+		tenant, err = tenantService.AddTenant(ctx, tenant)
+		if err != nil {
+			return fmt.Errorf("failed to add tenant: %w", err)
+		}
+
+		fmt.Printf("Tenant added successfully\n")
+		printTenant(tenant)
+
+		return nil
+	},
+}
+
+// tenantListCmd lists the tenants
+var tenantListCmd = &cli.Command{
+	Name:  "list",
+	Usage: "List tenants",
+	Action: func(c *cli.Context) error {
+		ctx := c.Context
+
+		db, err := postgres.NewDB(ctx, config.DatabaseURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		tenantService := email.NewTenantService(postgres.NewTenantsDB(db))
+
+		tenants, err := tenantService.ListTenants(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list tenants: %w", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		if _, err := fmt.Fprintln(w, "ID\tNAME\tFROM\tPROVIDER\tACCOUNT\tCREATED"); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+		if _, err := fmt.Fprintln(w, "--\t----\t----\t--------\t-------\t-------"); err != nil {
+			return fmt.Errorf("failed to write separator: %w", err)
+		}
+
+		for _, tenant := range tenants {
+			account := "-"
+			if tenant.Provider != "" {
+				account = providerAccount(tenant)
+			}
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				tenant.ID,
+				orDash(tenant.Name),
+				orDash(tenant.FromAddress),
+				orDash(tenant.Provider),
+				account,
+				tenant.CreatedAt.Format("2006-01-02"),
+			); err != nil {
+				return fmt.Errorf("failed to write tenant row: %w", err)
+			}
+		}
+
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("failed to flush output: %w", err)
+		}
+
+		return nil
+	},
+}
+
+// tenantUpdateCmd changes a tenant's settings
+var tenantUpdateCmd = &cli.Command{
+	Name:  "update",
+	Usage: "Change a tenant's name or sending defaults; pass an empty value to clear one",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:     "id",
+			Usage:    "Tenant ID",
+			Required: true,
+		},
+	}, tenantFlags...),
+	Action: func(c *cli.Context) error {
+		ctx := c.Context
+
+		db, err := postgres.NewDB(ctx, config.DatabaseURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+
+		tenantService := email.NewTenantService(postgres.NewTenantsDB(db))
+
+		tenant, err := tenantService.GetTenant(ctx, c.String("id"))
+		if err != nil {
+			return fmt.Errorf("failed to update tenant: %w", err)
+		}
+
+		// Only the settings that were passed change
+		for flag, field := range map[string]*string{
+			"name":         &tenant.Name,
+			"from-address": &tenant.FromAddress,
+			"from-name":    &tenant.FromName,
+			"provider":     &tenant.Provider,
+		} {
+			if c.IsSet(flag) {
+				*field = c.String(flag)
+			}
+		}
+		if c.IsSet("provider-fallback") {
+			tenant.ProviderFallback = c.Bool("provider-fallback")
+		}
+		setCredentials(c, tenant)
+		if err := app.CheckTenantProvider(tenant); err != nil {
+			return err
+		}
+
+		tenant, err = tenantService.UpdateTenant(ctx, tenant)
+		if err != nil {
+			return fmt.Errorf("failed to update tenant: %w", err)
+		}
+
+		fmt.Printf("Tenant updated successfully\n")
+		printTenant(tenant)
+
+		return nil
+	},
+}
+
+// setCredentials applies the credential flags that were passed to a tenant's
+// own provider credentials. Clearing every credential sends through the server's
+// account again.
+func setCredentials(c *cli.Context, tenant *email.Tenant) {
+	if !slices.ContainsFunc(credentialFlags, c.IsSet) {
+		return
+	}
+
+	var credentials email.ProviderCredentials
+	if tenant.Credentials != nil {
+		credentials = *tenant.Credentials
+	}
+
+	for flag, field := range map[string]*string{
+		"sendgrid-api-key":     &credentials.SendGridAPIKey,
+		"sendgrid-webhook-key": &credentials.SendGridWebhookKey,
+		"smtp-host":            &credentials.SMTPHost,
+		"smtp-username":        &credentials.SMTPUsername,
+		"smtp-password":        &credentials.SMTPPassword,
+		"smtp-tls":             &credentials.SMTPTLSMode,
+		"smtp-auth":            &credentials.SMTPAuth,
+	} {
+		if c.IsSet(flag) {
+			*field = c.String(flag)
+		}
+	}
+	if c.IsSet("smtp-port") {
+		credentials.SMTPPort = c.Int("smtp-port")
+	}
+
+	tenant.Credentials = nil
+	if credentials != (email.ProviderCredentials{}) {
+		tenant.Credentials = &credentials
+	}
+}
+
+// printTenant prints a tenant's settings
+func printTenant(tenant *email.Tenant) {
+	fmt.Printf("  ID: %s\n", tenant.ID)
+	if tenant.Name != "" {
+		fmt.Printf("  Name: %s\n", tenant.Name)
+	}
+	if tenant.FromAddress != "" {
+		fmt.Printf("  From: %s\n", email.Address{Name: tenant.FromName, Email: tenant.FromAddress})
+	}
+	if tenant.Provider != "" {
+		fmt.Printf("  Provider: %s (%s)\n", tenant.Provider, providerAccount(tenant))
+	}
+}
+
+// providerAccount describes whose account a tenant's provider sends through,
+// without showing its credentials
+func providerAccount(tenant *email.Tenant) string {
+	if tenant.Credentials != nil && tenant.ProviderFallback {
+		return "tenant's own account, then server's"
+	}
+	if tenant.Credentials != nil {
+		return "tenant's own account"
+	}
+	return "server's account"
+}
+
+// orDash shows empty table cells as a dash
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,135 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/travisbale/mailman/internal/email"
+	"github.com/travisbale/mailman/internal/logging"
+	"github.com/travisbale/mailman/internal/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type apiKeyAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (*email.APIKey, error)
+	AuthenticateClient(ctx context.Context, identity string) (*email.APIKey, error)
+}
+
+// methodScopes lists the scope each RPC requires. Methods missing from the map are
+// refused, so new RPCs stay closed until they are given a scope.
+var methodScopes = map[string]string{
+	pb.MailmanService_SendEmail_FullMethodName:         email.ScopeSend,
+	pb.MailmanService_SendEmailBatch_FullMethodName:    email.ScopeSend,
+	pb.MailmanService_GetEmailStatus_FullMethodName:    email.ScopeSend,
+	pb.MailmanService_CancelEmail_FullMethodName:       email.ScopeSend,
+	pb.MailmanService_RescheduleEmail_FullMethodName:   email.ScopeSend,
+	pb.MailmanService_ListSuppressions_FullMethodName:  email.ScopeSend,
+	pb.MailmanService_AddSuppression_FullMethodName:    email.ScopeSuppressionsWrite,
+	pb.MailmanService_RemoveSuppression_FullMethodName: email.ScopeSuppressionsWrite,
+	pb.MailmanService_RenderEmail_FullMethodName:       email.ScopeTemplatesRead,
+	pb.MailmanService_ListTemplates_FullMethodName:     email.ScopeTemplatesRead,
+	pb.MailmanService_GetTemplate_FullMethodName:       email.ScopeTemplatesRead,
+	pb.MailmanService_CreateTemplate_FullMethodName:    email.ScopeTemplatesWrite,
+	pb.MailmanService_UpdateTemplate_FullMethodName:    email.ScopeTemplatesWrite,
+	pb.MailmanService_DeleteTemplate_FullMethodName:    email.ScopeTemplatesWrite,
+	pb.MailmanService_ListJobs_FullMethodName:          email.ScopeJobsRead,
+	pb.MailmanService_GetJob_FullMethodName:            email.ScopeJobsRead,
+	pb.MailmanService_RetryJobs_FullMethodName:         email.ScopeJobsWrite,
+	pb.MailmanService_CancelJobs_FullMethodName:        email.ScopeJobsWrite,
+	pb.MailmanService_DiscardJobs_FullMethodName:       email.ScopeJobsWrite,
+}
+
+// authInterceptor requires every call to present an API key that grants the
+// method's scope, either sent as a bearer token in the authorization header or,
+// over mutual TLS, bound to the identity of the client's certificate
+type authInterceptor struct {
+	keys apiKeyAuthenticator
+}
+
+func (a *authInterceptor) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := a.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *authInterceptor) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// authorize checks the call's API key against the scope the method requires, and
+// returns a context acting for the key's tenant. A bearer token takes precedence
+// over the client certificate.
+func (a *authInterceptor) authorize(ctx context.Context, method string) (context.Context, error) {
+	key, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.AddAttrs(ctx, "api_key", key.Name, "tenant", key.TenantID)
+
+	scope, ok := methodScopes[method]
+	if !ok || !key.HasScope(scope) {
+		return nil, status.Errorf(codes.PermissionDenied, "API key %q may not call %s", key.Name, method)
+	}
+
+	return email.WithTenant(ctx, key.TenantID), nil
+}
+
+// authenticate returns the API key the call presents as a bearer token, or the
+// one bound to its verified client certificate
+func (a *authInterceptor) authenticate(ctx context.Context) (*email.APIKey, error) {
+	var key *email.APIKey
+	var err error
+
+	if token := bearerToken(ctx); token != "" {
+		key, err = a.keys.Authenticate(ctx, token)
+	} else if identity, ok := ClientIdentity(ctx); ok {
+		key, err = a.keys.AuthenticateClient(ctx, identity)
+		if errors.Is(err, email.ErrInvalidAPIKey) {
+			return nil, status.Errorf(codes.Unauthenticated, "client certificate %q is not bound to an API key", identity)
+		}
+	} else {
+		return nil, status.Error(codes.Unauthenticated, "missing API key")
+	}
+
+	if err != nil {
+		if errors.Is(err, email.ErrInvalidAPIKey) {
+			return nil, status.Error(codes.Unauthenticated, "invalid API key")
+		}
+		return nil, internalError(ctx, err, "failed to authenticate")
+	}
+
+	return key, nil
+}
+
+// serverStream replaces the context of a stream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// bearerToken returns the token from the call's authorization header, if any
+func bearerToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		scheme, token, ok := strings.Cut(value, " ")
+		if ok && strings.EqualFold(scheme, "bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
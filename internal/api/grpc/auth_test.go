@@ -0,0 +1,303 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/internal/email"
+	"github.com/travisbale/mailman/internal/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// fakeAPIKeyDB is an in-memory key store keyed by ID. Every lookup fails with err
+// when it is set.
+type fakeAPIKeyDB struct {
+	keys map[string]*email.APIKey
+	err  error
+}
+
+func (f *fakeAPIKeyDB) GetAPIKey(_ context.Context, id string) (*email.APIKey, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	key, ok := f.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", email.ErrAPIKeyNotFound, id)
+	}
+	return key, nil
+}
+
+func (f *fakeAPIKeyDB) GetAPIKeyByClientIdentity(_ context.Context, identity string) (*email.APIKey, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	for _, key := range f.keys {
+		if key.ClientIdentity != nil && *key.ClientIdentity == identity && key.RevokedAt == nil {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: client identity %s", email.ErrAPIKeyNotFound, identity)
+}
+
+func (f *fakeAPIKeyDB) ListAPIKeys(_ context.Context) ([]*email.APIKey, error) {
+	panic("not implemented")
+}
+
+func (f *fakeAPIKeyDB) CreateAPIKey(_ context.Context, key *email.APIKey) (*email.APIKey, error) {
+	f.keys[key.ID] = key
+	return key, nil
+}
+
+func (f *fakeAPIKeyDB) RevokeAPIKey(_ context.Context, id string) error {
+	now := time.Now()
+	f.keys[id].RevokedAt = &now
+	return nil
+}
+
+// fakeServerStream is a server stream that only carries a context
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (f *fakeServerStream) Context() context.Context {
+	return f.ctx
+}
+
+// newAuthInterceptor returns an interceptor checking keys issued by keys
+func newAuthInterceptor(db *fakeAPIKeyDB) (*authInterceptor, *email.APIKeyService) {
+	keys := email.NewAPIKeyService(db)
+	return &authInterceptor{keys: keys}, keys
+}
+
+// createKey issues a key for the tenant with the given scopes and returns its token
+func createKey(t *testing.T, keys *email.APIKeyService, tenantID string, scopes ...string) (*email.APIKey, string) {
+	t.Helper()
+
+	key, token, err := keys.CreateAPIKey(email.WithTenant(context.Background(), tenantID), "billing-service", "", scopes)
+	require.NoError(t, err)
+	return key, token
+}
+
+// withAuthorization returns a context for an incoming call with the authorization header
+func withAuthorization(value string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", value))
+}
+
+// callUnary calls a unary handler for method through the interceptor and
+// returns the tenant the handler acted for
+func callUnary(a *authInterceptor, ctx context.Context, method string) (string, error) {
+	var tenant string
+	_, err := a.unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, _ any) (any, error) {
+		tenant = email.TenantFromContext(ctx)
+		return nil, nil
+	})
+	return tenant, err
+}
+
+func TestAuthInterceptor_Authentication(t *testing.T) {
+	t.Parallel()
+
+	db := &fakeAPIKeyDB{keys: make(map[string]*email.APIKey)}
+	a, keys := newAuthInterceptor(db)
+
+	_, token := createKey(t, keys, "payroll", email.ScopeSend)
+	revoked, revokedToken := createKey(t, keys, "payroll", email.ScopeSend)
+	require.NoError(t, keys.RevokeAPIKey(context.Background(), revoked.ID))
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		code    codes.Code
+		message string
+	}{
+		{name: "no metadata", ctx: context.Background(), code: codes.Unauthenticated, message: "missing API key"},
+		{name: "no authorization header", ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", token)), code: codes.Unauthenticated, message: "missing API key"},
+		{name: "not a bearer token", ctx: withAuthorization("Basic " + token), code: codes.Unauthenticated, message: "missing API key"},
+		{name: "empty bearer token", ctx: withAuthorization("Bearer "), code: codes.Unauthenticated, message: "missing API key"},
+		{name: "malformed key", ctx: withAuthorization("Bearer not-a-key"), code: codes.Unauthenticated, message: "invalid API key"},
+		{name: "unknown key", ctx: withAuthorization("Bearer mm_unknown_secret"), code: codes.Unauthenticated, message: "invalid API key"},
+		{name: "wrong secret", ctx: withAuthorization("Bearer " + token + "x"), code: codes.Unauthenticated, message: "invalid API key"},
+		{name: "revoked key", ctx: withAuthorization("Bearer " + revokedToken), code: codes.Unauthenticated, message: "invalid API key"},
+		{name: "valid key", ctx: withAuthorization("Bearer " + token), code: codes.OK},
+		{name: "scheme is case insensitive", ctx: withAuthorization("bearer " + token), code: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := callUnary(a, tt.ctx, pb.MailmanService_SendEmail_FullMethodName)
+			assert.Equal(t, tt.code, status.Code(err))
+			if tt.message != "" {
+				assert.Equal(t, tt.message, status.Convert(err).Message())
+			}
+		})
+	}
+}
+
+func TestAuthInterceptor_StoreUnavailable(t *testing.T) {
+	t.Parallel()
+
+	db := &fakeAPIKeyDB{keys: make(map[string]*email.APIKey)}
+	a, keys := newAuthInterceptor(db)
+	_, token := createKey(t, keys, "payroll", email.ScopeSend)
+
+	// Failing to look the key up is not the caller's fault, and hides the cause
+	db.err = errors.New("connection refused")
+	_, err := callUnary(a, withAuthorization("Bearer "+token), pb.MailmanService_SendEmail_FullMethodName)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.NotContains(t, err.Error(), "connection refused")
+}
+
+func TestAuthInterceptor_Scopes(t *testing.T) {
+	t.Parallel()
+
+	a, keys := newAuthInterceptor(&fakeAPIKeyDB{keys: make(map[string]*email.APIKey)})
+
+	for _, scope := range email.Scopes {
+		_, allowed := createKey(t, keys, "payroll", scope)
+		_, denied := createKey(t, keys, "payroll", slices.DeleteFunc(slices.Clone(email.Scopes), func(s string) bool { return s == scope })...)
+
+		for method, required := range methodScopes {
+			if required != scope {
+				continue
+			}
+
+			t.Run(method, func(t *testing.T) {
+				_, err := callUnary(a, withAuthorization("Bearer "+allowed), method)
+				require.NoError(t, err)
+
+				// Every other scope together still isn't enough
+				_, err = callUnary(a, withAuthorization("Bearer "+denied), method)
+				assert.Equal(t, codes.PermissionDenied, status.Code(err))
+			})
+		}
+	}
+}
+
+func TestAuthInterceptor_UnmappedMethod(t *testing.T) {
+	t.Parallel()
+
+	a, keys := newAuthInterceptor(&fakeAPIKeyDB{keys: make(map[string]*email.APIKey)})
+	_, token := createKey(t, keys, "payroll", email.Scopes...)
+
+	// New RPCs are refused until they are given a scope, whatever the key grants
+	_, err := callUnary(a, withAuthorization("Bearer "+token), "/mailman.v1.MailmanService/DropDatabase")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestMethodScopes(t *testing.T) {
+	t.Parallel()
+
+	// Every RPC the service serves is given a scope, so none are refused by accident
+	for _, method := range pb.MailmanService_ServiceDesc.Methods {
+		fullMethod := "/" + pb.MailmanService_ServiceDesc.ServiceName + "/" + method.MethodName
+		assert.Contains(t, methodScopes, fullMethod)
+	}
+	for _, scope := range methodScopes {
+		assert.Contains(t, email.Scopes, scope)
+	}
+}
+
+func TestAuthInterceptor_Tenant(t *testing.T) {
+	t.Parallel()
+
+	a, keys := newAuthInterceptor(&fakeAPIKeyDB{keys: make(map[string]*email.APIKey)})
+	_, payroll := createKey(t, keys, "payroll", email.ScopeSend)
+	_, billing := createKey(t, keys, "billing", email.ScopeSend)
+
+	// Calls act for the tenant the key was issued to
+	tenant, err := callUnary(a, withAuthorization("Bearer "+payroll), pb.MailmanService_SendEmail_FullMethodName)
+	require.NoError(t, err)
+	assert.Equal(t, "payroll", tenant)
+
+	tenant, err = callUnary(a, withAuthorization("Bearer "+billing), pb.MailmanService_SendEmail_FullMethodName)
+	require.NoError(t, err)
+	assert.Equal(t, "billing", tenant)
+}
+
+func TestAuthInterceptor_Stream(t *testing.T) {
+	t.Parallel()
+
+	a, keys := newAuthInterceptor(&fakeAPIKeyDB{keys: make(map[string]*email.APIKey)})
+	_, token := createKey(t, keys, "payroll", email.ScopeSend)
+	_, readOnly := createKey(t, keys, "payroll", email.ScopeTemplatesRead)
+
+	callStream := func(authorization string) (string, error) {
+		var tenant string
+		ss := &fakeServerStream{ctx: withAuthorization(authorization)}
+		info := &grpc.StreamServerInfo{FullMethod: pb.MailmanService_SendEmail_FullMethodName}
+		err := a.stream(nil, ss, info, func(_ any, stream grpc.ServerStream) error {
+			tenant = email.TenantFromContext(stream.Context())
+			return nil
+		})
+		return tenant, err
+	}
+
+	// Streams are authorized like unary calls, and handlers see the key's tenant
+	tenant, err := callStream("Bearer " + token)
+	require.NoError(t, err)
+	assert.Equal(t, "payroll", tenant)
+
+	_, err = callStream("")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = callStream("Bearer " + readOnly)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+// withClientCertificate returns ctx for a call over mutual TLS whose verified
+// client certificate was issued to commonName
+func withClientCertificate(ctx context.Context, commonName string) context.Context {
+	return peer.NewContext(ctx, &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: commonName}}}},
+		}},
+	})
+}
+
+func TestAuthInterceptor_ClientCertificate(t *testing.T) {
+	t.Parallel()
+
+	db := &fakeAPIKeyDB{keys: make(map[string]*email.APIKey)}
+	a, keys := newAuthInterceptor(db)
+
+	bound, _, err := keys.CreateAPIKey(email.WithTenant(context.Background(), "payroll"), "payroll-service", "payroll.internal", []string{email.ScopeSend})
+	require.NoError(t, err)
+	_, billing := createKey(t, keys, "billing", email.ScopeSend)
+
+	// A certificate bound to a key acts for the key's tenant with its scopes
+	tenant, err := callUnary(a, withClientCertificate(context.Background(), "payroll.internal"), pb.MailmanService_SendEmail_FullMethodName)
+	require.NoError(t, err)
+	assert.Equal(t, "payroll", tenant)
+
+	_, err = callUnary(a, withClientCertificate(context.Background(), "payroll.internal"), pb.MailmanService_CreateTemplate_FullMethodName)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// An unbound certificate isn't enough on its own
+	_, err = callUnary(a, withClientCertificate(context.Background(), "unknown.internal"), pb.MailmanService_SendEmail_FullMethodName)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// A bearer token takes precedence over the certificate
+	tenant, err = callUnary(a, withClientCertificate(withAuthorization("Bearer "+billing), "payroll.internal"), pb.MailmanService_SendEmail_FullMethodName)
+	require.NoError(t, err)
+	assert.Equal(t, "billing", tenant)
+
+	// Revoking the key stops the certificate authenticating
+	require.NoError(t, keys.RevokeAPIKey(context.Background(), bound.ID))
+	_, err = callUnary(a, withClientCertificate(context.Background(), "payroll.internal"), pb.MailmanService_SendEmail_FullMethodName)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -5,29 +5,331 @@ import (
 	"errors"
 
 	"github.com/travisbale/mailman/internal/email"
+	"github.com/travisbale/mailman/internal/logging"
 	"github.com/travisbale/mailman/internal/pb"
 	"github.com/travisbale/mailman/sdk"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // SendEmail validates the request, then delegates to the email service for
 // template rendering and job enqueueing.
 func (s *Server) SendEmail(ctx context.Context, req *pb.SendEmailRequest) (*pb.SendEmailResponse, error) {
-	sdkReq := sdk.SendEmailRequest{
-		TemplateID: req.TemplateId,
-		To:         req.To,
+	logging.AddAttrs(ctx, "template", req.TemplateId)
+	if req.IdempotencyKey != "" {
+		logging.AddAttrs(ctx, "idempotency_key", req.IdempotencyKey)
+	}
+
+	resp, err := s.sendEmail(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.AddAttrs(ctx, "message_id", resp.MessageId)
+	return resp, nil
+}
+
+// sendEmail sends a single email, for SendEmail and best-effort batches
+func (s *Server) sendEmail(ctx context.Context, req *pb.SendEmailRequest) (*pb.SendEmailResponse, error) {
+	sendReq, err := buildSendRequest(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	messageID, err := s.emailService.Send(ctx, sendReq)
+	if err != nil {
+		return nil, sendError(ctx, err, req)
+	}
+
+	return &pb.SendEmailResponse{MessageId: messageID}, nil
+}
+
+// SendEmailBatch enqueues multiple emails in a single request. Atomic batches
+// are rejected as a whole if any email fails; best-effort batches report a
+// result for every email.
+func (s *Server) SendEmailBatch(ctx context.Context, req *pb.SendEmailBatchRequest) (*pb.SendEmailBatchResponse, error) {
+	logging.AddAttrs(ctx, "batch_size", len(req.Emails))
+
+	if size := batchAttachmentSize(req); size > sdk.MaxBatchAttachmentSize {
+		return nil, status.Errorf(codes.InvalidArgument, "attachments exceed the %d byte batch limit", sdk.MaxBatchAttachmentSize)
+	}
+
+	if req.Mode == pb.BatchMode_BATCH_MODE_BEST_EFFORT {
+		return s.sendEmailBatchBestEffort(ctx, req)
+	}
+
+	sendReqs := make([]email.SendRequest, len(req.Emails))
+	for i, emailReq := range req.Emails {
+		sendReq, err := buildSendRequest(emailReq)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "email at index %d: %v", i, err)
+		}
+		sendReqs[i] = sendReq
+	}
+
+	messageIDs, err := s.emailService.SendBatch(ctx, sendReqs)
+	if err != nil {
+		var batchErr *email.BatchError
+		if !errors.As(err, &batchErr) {
+			return nil, internalError(ctx, err, "failed to send emails")
+		}
+
+		st := status.Convert(sendError(ctx, batchErr.Err, req.Emails[batchErr.Index]))
+		return nil, status.Errorf(st.Code(), "email at index %d: %s", batchErr.Index, st.Message())
+	}
+
+	results := make([]*pb.SendEmailBatchResult, len(messageIDs))
+	for i, messageID := range messageIDs {
+		results[i] = &pb.SendEmailBatchResult{MessageId: messageID}
+	}
+
+	return &pb.SendEmailBatchResponse{Results: results}, nil
+}
+
+// batchAttachmentSize totals the attachment content across every email in a batch
+func batchAttachmentSize(req *pb.SendEmailBatchRequest) int {
+	var total int
+	for _, emailReq := range req.Emails {
+		for _, a := range emailReq.Attachments {
+			total += len(a.Content)
+		}
+	}
+	return total
+}
+
+// sendEmailBatchBestEffort enqueues each email independently, recording the
+// outcome of every email instead of stopping at the first failure
+func (s *Server) sendEmailBatchBestEffort(ctx context.Context, req *pb.SendEmailBatchRequest) (*pb.SendEmailBatchResponse, error) {
+	results := make([]*pb.SendEmailBatchResult, len(req.Emails))
+
+	for i, emailReq := range req.Emails {
+		resp, err := s.sendEmail(ctx, emailReq)
+		if err != nil {
+			st := status.Convert(err)
+			results[i] = &pb.SendEmailBatchResult{Code: int32(st.Code()), Error: st.Message()}
+			continue
+		}
+		results[i] = &pb.SendEmailBatchResult{MessageId: resp.MessageId}
+	}
+
+	return &pb.SendEmailBatchResponse{Results: results}, nil
+}
+
+// RenderEmail renders a template with the same validation as SendEmail, without
+// enqueueing anything
+func (s *Server) RenderEmail(ctx context.Context, req *pb.RenderEmailRequest) (*pb.RenderEmailResponse, error) {
+	logging.AddAttrs(ctx, "template", req.TemplateId)
+
+	sdkReq := sdk.RenderEmailRequest{
+		TemplateID:      req.TemplateId,
+		TemplateVersion: req.TemplateVersion,
+		Locale:          req.Locale,
+		Variables:       req.Variables,
+		Data:            req.Data.AsMap(),
+	}
+
+	if err := sdkReq.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	rendered, err := s.emailService.Render(ctx, email.RenderRequest{
+		TemplateName:    req.TemplateId,
+		TemplateVersion: req.TemplateVersion,
+		Locale:          req.Locale,
+		Variables:       mergeVariables(req.Variables, sdkReq.Data),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, email.ErrTemplateNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, email.ErrMissingVariable), errors.Is(err, email.ErrInvalidLocale):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		default:
+			return nil, internalError(ctx, err, "failed to render email")
+		}
+	}
+
+	return &pb.RenderEmailResponse{
+		Subject:  rendered.Subject,
+		HtmlBody: rendered.HTMLBody,
+		TextBody: rendered.TextBody,
+	}, nil
+}
+
+// GetEmailStatus reports the delivery state of a previously enqueued email
+func (s *Server) GetEmailStatus(ctx context.Context, req *pb.GetEmailStatusRequest) (*pb.GetEmailStatusResponse, error) {
+	logging.AddAttrs(ctx, "message_id", req.MessageId)
+
+	if req.MessageId == "" {
+		return nil, status.Error(codes.InvalidArgument, "message_id is required")
+	}
+
+	emailStatus, err := s.emailService.GetStatus(ctx, req.MessageId)
+	if err != nil {
+		if errors.Is(err, email.ErrMessageNotFound) {
+			return nil, status.Errorf(codes.NotFound, "message not found: %s", req.MessageId)
+		}
+		return nil, internalError(ctx, err, "failed to get email status")
+	}
+
+	resp := &pb.GetEmailStatusResponse{
+		MessageId:   emailStatus.MessageID,
+		State:       convertStateToProto(emailStatus.State),
+		Attempts:    int32(emailStatus.Attempts),
+		MaxAttempts: int32(emailStatus.MaxAttempts),
+		LastError:   emailStatus.LastError,
+		CreatedAt:   timestamppb.New(emailStatus.CreatedAt),
+		ScheduledAt: timestamppb.New(emailStatus.ScheduledAt),
+		Provider:    emailStatus.Provider,
+	}
+
+	if emailStatus.AttemptedAt != nil {
+		resp.AttemptedAt = timestamppb.New(*emailStatus.AttemptedAt)
+	}
+	if emailStatus.FinalizedAt != nil {
+		resp.FinalizedAt = timestamppb.New(*emailStatus.FinalizedAt)
+	}
+
+	for _, event := range emailStatus.Events {
+		resp.Events = append(resp.Events, &pb.DeliveryEvent{
+			Type:       event.Type,
+			Recipient:  event.Recipient,
+			Reason:     event.Reason,
+			OccurredAt: timestamppb.New(event.OccurredAt),
+			Provider:   event.Provider,
+		})
+	}
+
+	return resp, nil
+}
+
+// CancelEmail stops a pending email, or every pending email sharing a cancellation key
+func (s *Server) CancelEmail(ctx context.Context, req *pb.CancelEmailRequest) (*pb.CancelEmailResponse, error) {
+	logging.AddAttrs(ctx, "message_id", req.MessageId, "cancellation_key", req.CancellationKey)
+
+	sdkReq := sdk.CancelEmailRequest{
+		MessageID:       req.MessageId,
+		CancellationKey: req.CancellationKey,
 	}
 
 	if err := sdkReq.Validate(); err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
 	}
 
+	if req.CancellationKey != "" {
+		cancelled, err := s.emailService.CancelGroup(ctx, req.CancellationKey)
+		if err != nil {
+			return nil, internalError(ctx, err, "failed to cancel emails")
+		}
+		return &pb.CancelEmailResponse{CancelledCount: int32(cancelled)}, nil
+	}
+
+	if err := s.emailService.Cancel(ctx, req.MessageId); err != nil {
+		return nil, pendingEmailError(ctx, err, "failed to cancel email")
+	}
+
+	return &pb.CancelEmailResponse{CancelledCount: 1}, nil
+}
+
+// RescheduleEmail moves a pending email, or every pending email sharing a
+// cancellation key, to a new delivery time
+func (s *Server) RescheduleEmail(ctx context.Context, req *pb.RescheduleEmailRequest) (*pb.RescheduleEmailResponse, error) {
+	logging.AddAttrs(ctx, "message_id", req.MessageId, "cancellation_key", req.CancellationKey)
+
+	sdkReq := sdk.RescheduleEmailRequest{
+		MessageID:       req.MessageId,
+		CancellationKey: req.CancellationKey,
+	}
+	if req.ScheduledAt != nil {
+		sdkReq.ScheduledAt = req.ScheduledAt.AsTime()
+	}
+
+	if err := sdkReq.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if req.CancellationKey != "" {
+		rescheduled, err := s.emailService.RescheduleGroup(ctx, req.CancellationKey, sdkReq.ScheduledAt)
+		if err != nil {
+			return nil, internalError(ctx, err, "failed to reschedule emails")
+		}
+		return &pb.RescheduleEmailResponse{RescheduledCount: int32(rescheduled)}, nil
+	}
+
+	if err := s.emailService.Reschedule(ctx, req.MessageId, sdkReq.ScheduledAt); err != nil {
+		return nil, pendingEmailError(ctx, err, "failed to reschedule email")
+	}
+
+	return &pb.RescheduleEmailResponse{RescheduledCount: 1}, nil
+}
+
+// pendingEmailError maps errors from changing a single pending email onto gRPC status codes
+func pendingEmailError(ctx context.Context, err error, internalMsg string) error {
+	switch {
+	case errors.Is(err, email.ErrMessageNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, email.ErrMessageNotPending):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return internalError(ctx, err, internalMsg)
+	}
+}
+
+// convertStateToProto maps a domain delivery state to its protobuf enum
+func convertStateToProto(state email.State) pb.EmailState {
+	switch state {
+	case email.StateScheduled:
+		return pb.EmailState_EMAIL_STATE_SCHEDULED
+	case email.StateQueued:
+		return pb.EmailState_EMAIL_STATE_QUEUED
+	case email.StateSending:
+		return pb.EmailState_EMAIL_STATE_SENDING
+	case email.StateSent:
+		return pb.EmailState_EMAIL_STATE_SENT
+	case email.StateRetrying:
+		return pb.EmailState_EMAIL_STATE_RETRYING
+	case email.StateFailed:
+		return pb.EmailState_EMAIL_STATE_FAILED
+	case email.StateCancelled:
+		return pb.EmailState_EMAIL_STATE_CANCELLED
+	default:
+		return pb.EmailState_EMAIL_STATE_UNSPECIFIED
+	}
+}
+
+// buildSendRequest validates a protobuf send request and converts it to a domain request
+func buildSendRequest(req *pb.SendEmailRequest) (email.SendRequest, error) {
+	sdkReq := sdk.SendEmailRequest{
+		TemplateID:      req.TemplateId,
+		TemplateVersion: req.TemplateVersion,
+		Locale:          req.Locale,
+		Recipients:      req.To,
+		CC:              req.Cc,
+		BCC:             req.Bcc,
+		ReplyTo:         req.ReplyTo,
+		Sender:          req.Sender,
+		CancellationKey: req.CancellationKey,
+		IdempotencyKey:  req.IdempotencyKey,
+		Variables:       req.Variables,
+		Data:            req.Data.AsMap(),
+		Attachments:     convertAttachmentsFromProto(req.Attachments),
+	}
+
+	if err := sdkReq.Validate(); err != nil {
+		return email.SendRequest{}, err
+	}
+
 	sendReq := email.SendRequest{
-		To:           req.To,
-		TemplateName: req.TemplateId,
-		Variables:    req.Variables,
-		Priority:     req.Priority,
+		TemplateName:    req.TemplateId,
+		TemplateVersion: req.TemplateVersion,
+		Locale:          req.Locale,
+		Variables:       mergeVariables(req.Variables, sdkReq.Data),
+		Attachments:     convertAttachmentsToDomain(sdkReq.Attachments),
+		Priority:        req.Priority,
+		CancellationKey: req.CancellationKey,
+		IdempotencyKey:  req.IdempotencyKey,
+		DryRun:          req.DryRun,
 	}
 
 	if req.ScheduledAt != nil {
@@ -35,55 +337,122 @@ func (s *Server) SendEmail(ctx context.Context, req *pb.SendEmailRequest) (*pb.S
 		sendReq.ScheduledAt = &scheduledAt
 	}
 
-	if err := s.emailService.Send(ctx, sendReq); err != nil {
-		switch {
-		case errors.Is(err, email.ErrTemplateNotFound):
-			return nil, status.Errorf(codes.NotFound, "template not found: %s", req.TemplateId)
-		case errors.Is(err, email.ErrMissingVariable):
-			return nil, status.Error(codes.InvalidArgument, err.Error())
-		default:
-			return nil, status.Errorf(codes.Internal, "failed to send email")
+	if err := parseAddresses(req, &sendReq); err != nil {
+		return email.SendRequest{}, err
+	}
+
+	return sendReq, nil
+}
+
+// sendError maps errors from sending an email onto gRPC status codes
+func sendError(ctx context.Context, err error, req *pb.SendEmailRequest) error {
+	switch {
+	case errors.Is(err, email.ErrTemplateNotFound):
+		if req.TemplateVersion > 0 {
+			return status.Errorf(codes.NotFound, "template not found: %s version %d", req.TemplateId, req.TemplateVersion)
 		}
+		return status.Errorf(codes.NotFound, "template not found: %s", req.TemplateId)
+	case errors.Is(err, email.ErrMissingVariable), errors.Is(err, email.ErrInvalidLocale):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, email.ErrSenderNotAllowed):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, email.ErrRecipientSuppressed):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return internalError(ctx, err, "failed to send email")
 	}
+}
 
-	return &pb.SendEmailResponse{}, nil
+// mergeVariables combines string and structured variables into the single map
+// templates are rendered with. Validation has already ruled out shared keys.
+func mergeVariables(variables map[string]string, data map[string]any) map[string]any {
+	merged := make(map[string]any, len(variables)+len(data))
+	for key, value := range variables {
+		merged[key] = value
+	}
+	for key, value := range data {
+		merged[key] = value
+	}
+	return merged
 }
 
-// SendEmailBatch enqueues multiple emails in a single request
-func (s *Server) SendEmailBatch(ctx context.Context, req *pb.SendEmailBatchRequest) (*pb.SendEmailBatchResponse, error) {
-	results := make([]*pb.SendEmailResponse, 0, len(req.Emails))
+// parseAddresses converts the request's address strings into domain addresses
+func parseAddresses(req *pb.SendEmailRequest, sendReq *email.SendRequest) error {
+	var err error
+	if sendReq.To, err = email.ParseAddresses(req.To); err != nil {
+		return err
+	}
+	if sendReq.CC, err = email.ParseAddresses(req.Cc); err != nil {
+		return err
+	}
+	if sendReq.BCC, err = email.ParseAddresses(req.Bcc); err != nil {
+		return err
+	}
 
-	for _, emailReq := range req.Emails {
-		resp, err := s.SendEmail(ctx, emailReq)
+	if req.ReplyTo != "" {
+		replyTo, err := email.ParseAddress(req.ReplyTo)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		results = append(results, resp)
+		sendReq.ReplyTo = &replyTo
 	}
 
-	return &pb.SendEmailBatchResponse{
-		Results: results,
-	}, nil
+	// Only the address identifies a sender; its display name comes from the registry
+	if req.Sender != "" {
+		sender, err := email.ParseAddress(req.Sender)
+		if err != nil {
+			return err
+		}
+		sendReq.Sender = sender.Email
+	}
+
+	return nil
 }
 
-// ListTemplates returns all available email templates
-func (s *Server) ListTemplates(ctx context.Context, req *pb.ListTemplatesRequest) (*pb.ListTemplatesResponse, error) {
-	templates, err := s.templatesDB.List(ctx)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to list templates")
+// convertAttachmentsFromProto converts protobuf attachments for validation
+func convertAttachmentsFromProto(attachments []*pb.Attachment) []sdk.Attachment {
+	if len(attachments) == 0 {
+		return nil
 	}
 
-	pbTemplates := make([]*pb.EmailTemplate, 0, len(templates))
-	for _, t := range templates {
-		pbTemplates = append(pbTemplates, &pb.EmailTemplate{
-			Id:        t.Name,
-			Subject:   t.Subject,
-			Variables: t.Variables,
-			Version:   t.Version,
-		})
+	sdkAttachments := make([]sdk.Attachment, len(attachments))
+	for i, a := range attachments {
+		sdkAttachments[i] = sdk.Attachment{
+			Filename:    a.Filename,
+			ContentType: a.ContentType,
+			Content:     a.Content,
+			Disposition: a.Disposition,
+			ContentID:   a.ContentId,
+		}
 	}
+	return sdkAttachments
+}
 
-	return &pb.ListTemplatesResponse{
-		Templates: pbTemplates,
-	}, nil
+// convertAttachmentsToDomain converts validated attachments, filling in defaults
+// so delivery clients never see an empty content type or disposition
+func convertAttachmentsToDomain(attachments []sdk.Attachment) []email.Attachment {
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	domainAttachments := make([]email.Attachment, len(attachments))
+	for i, a := range attachments {
+		contentType := a.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		disposition := a.Disposition
+		if disposition == "" {
+			disposition = email.DispositionAttachment
+		}
+
+		domainAttachments[i] = email.Attachment{
+			Filename:    a.Filename,
+			ContentType: contentType,
+			Content:     a.Content,
+			Disposition: disposition,
+			ContentID:   a.ContentID,
+		}
+	}
+	return domainAttachments
 }
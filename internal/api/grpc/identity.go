@@ -0,0 +1,46 @@
+package grpc
+
+import (
+	"context"
+	"crypto/x509"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// ClientIdentity returns who the verified client certificate of a mutual TLS call
+// was issued to, and false for calls made without one. The first URI SAN is
+// preferred, as SPIFFE IDs are, then the first DNS SAN, then the subject's common
+// name.
+func ClientIdentity(ctx context.Context) (string, bool) {
+	cert := clientCertificate(ctx)
+	if cert == nil {
+		return "", false
+	}
+
+	switch {
+	case len(cert.URIs) > 0:
+		return cert.URIs[0].String(), true
+	case len(cert.DNSNames) > 0:
+		return cert.DNSNames[0], true
+	case cert.Subject.CommonName != "":
+		return cert.Subject.CommonName, true
+	}
+
+	return "", false
+}
+
+// clientCertificate returns the leaf of the call's verified client certificate chain
+func clientCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	return info.State.VerifiedChains[0][0]
+}
@@ -0,0 +1,271 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/travisbale/mailman/internal/email"
+	"github.com/travisbale/mailman/internal/logging"
+	"github.com/travisbale/mailman/internal/pb"
+	"github.com/travisbale/mailman/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ListJobs returns a page of enqueued emails matching the filter, newest first
+func (s *Server) ListJobs(ctx context.Context, req *pb.ListJobsRequest) (*pb.ListJobsResponse, error) {
+	if req.PageSize < 0 || req.PageSize > sdk.MaxJobPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be between 0 and %d", sdk.MaxJobPageSize)
+	}
+
+	filter, err := buildJobFilter(req.Filter)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	jobs, nextPageToken, err := s.emailService.ListJobs(ctx, filter, int(req.PageSize), req.PageToken)
+	if err != nil {
+		return nil, jobError(ctx, err, "failed to list jobs")
+	}
+
+	pbJobs := make([]*pb.Job, len(jobs))
+	for i, job := range jobs {
+		pbJobs[i] = convertJobToProto(job)
+	}
+
+	return &pb.ListJobsResponse{
+		Jobs:          pbJobs,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// GetJob returns an enqueued email with the error from every failed attempt
+func (s *Server) GetJob(ctx context.Context, req *pb.GetJobRequest) (*pb.GetJobResponse, error) {
+	logging.AddAttrs(ctx, "message_id", req.MessageId)
+
+	if req.MessageId == "" {
+		return nil, status.Error(codes.InvalidArgument, "message_id is required")
+	}
+
+	job, err := s.emailService.GetJob(ctx, req.MessageId)
+	if err != nil {
+		return nil, jobError(ctx, err, "failed to get job")
+	}
+
+	return &pb.GetJobResponse{Job: convertJobToProto(job)}, nil
+}
+
+// RetryJobs makes failed emails, selected by ID or by filter, available for
+// delivery again
+func (s *Server) RetryJobs(ctx context.Context, req *pb.RetryJobsRequest) (*pb.RetryJobsResponse, error) {
+	retried, skipped, err := s.changeJobs(ctx, req.MessageIds, req.Filter, s.emailService.RetryMessages, s.emailService.RetryJobs)
+	logging.AddAttrs(ctx, "retried", retried)
+	resp := &pb.RetryJobsResponse{RetriedCount: int32(retried), Skipped: skipped}
+	if err != nil {
+		return nil, partialJobError(jobError(ctx, err, "failed to retry jobs"), resp)
+	}
+
+	return resp, nil
+}
+
+// CancelJobs stops pending emails, selected by ID or by filter, from being sent
+func (s *Server) CancelJobs(ctx context.Context, req *pb.CancelJobsRequest) (*pb.CancelJobsResponse, error) {
+	cancelled, skipped, err := s.changeJobs(ctx, req.MessageIds, req.Filter, s.emailService.CancelMessages, s.emailService.CancelJobs)
+	logging.AddAttrs(ctx, "cancelled", cancelled)
+	resp := &pb.CancelJobsResponse{CancelledCount: int32(cancelled), Skipped: skipped}
+	if err != nil {
+		return nil, partialJobError(jobError(ctx, err, "failed to cancel jobs"), resp)
+	}
+
+	return resp, nil
+}
+
+// DiscardJobs deletes failed and cancelled emails, selected by ID or by filter
+func (s *Server) DiscardJobs(ctx context.Context, req *pb.DiscardJobsRequest) (*pb.DiscardJobsResponse, error) {
+	discarded, skipped, err := s.changeJobs(ctx, req.MessageIds, req.Filter, s.emailService.DiscardMessages, s.emailService.DiscardJobs)
+	logging.AddAttrs(ctx, "discarded", discarded)
+	resp := &pb.DiscardJobsResponse{DiscardedCount: int32(discarded), Skipped: skipped}
+	if err != nil {
+		return nil, partialJobError(jobError(ctx, err, "failed to discard jobs"), resp)
+	}
+
+	return resp, nil
+}
+
+// changeJobs applies a change to each listed email or to every email matching
+// the filter, and returns how many were changed, even if it fails part way, and
+// the listed emails that were skipped
+func (s *Server) changeJobs(ctx context.Context, messageIDs []string, pbFilter *pb.JobFilter,
+	changeEach func(context.Context, []string) (int, []email.SkippedJob, error),
+	changeAll func(context.Context, email.JobFilter) (int, error)) (int, []*pb.SkippedJob, error) {
+	if err := validateJobSelection(messageIDs, pbFilter); err != nil {
+		return 0, nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if pbFilter != nil {
+		filter, err := buildJobFilter(pbFilter)
+		if err != nil {
+			return 0, nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		changed, err := changeAll(ctx, filter)
+		return changed, nil, err
+	}
+
+	changed, skipped, err := changeEach(ctx, messageIDs)
+
+	pbSkipped := make([]*pb.SkippedJob, len(skipped))
+	for i, job := range skipped {
+		pbSkipped[i] = &pb.SkippedJob{MessageId: job.MessageID, Reason: job.Err.Error()}
+	}
+	if len(skipped) > 0 {
+		logging.AddAttrs(ctx, "skipped", len(skipped))
+	}
+
+	return changed, pbSkipped, err
+}
+
+// partialJobError attaches the response to a bulk change's error status, so
+// callers learn how many emails were changed before it failed
+func partialJobError(err error, resp protoadapt.MessageV1) error {
+	st, detailErr := status.Convert(err).WithDetails(resp)
+	if detailErr != nil {
+		return err
+	}
+	return st.Err()
+}
+
+// validateJobSelection checks that exactly one way of selecting emails was given
+func validateJobSelection(messageIDs []string, filter *pb.JobFilter) error {
+	sdkReq := sdk.JobsRequest{MessageIDs: messageIDs}
+	if filter != nil {
+		// The filter's contents are validated when it is built
+		sdkReq.Filter = &sdk.JobFilter{}
+	}
+	return sdkReq.Validate()
+}
+
+// buildJobFilter validates a protobuf job filter and converts it to a domain filter
+func buildJobFilter(pbFilter *pb.JobFilter) (email.JobFilter, error) {
+	if pbFilter == nil {
+		return email.JobFilter{}, nil
+	}
+
+	sdkFilter := sdk.JobFilter{
+		TemplateID: pbFilter.TemplateId,
+		Recipient:  pbFilter.Recipient,
+	}
+	if pbFilter.CreatedAfter != nil {
+		createdAfter := pbFilter.CreatedAfter.AsTime()
+		sdkFilter.CreatedAfter = &createdAfter
+	}
+	if pbFilter.CreatedBefore != nil {
+		createdBefore := pbFilter.CreatedBefore.AsTime()
+		sdkFilter.CreatedBefore = &createdBefore
+	}
+
+	filter := email.JobFilter{
+		TemplateName:  pbFilter.TemplateId,
+		CreatedAfter:  sdkFilter.CreatedAfter,
+		CreatedBefore: sdkFilter.CreatedBefore,
+	}
+
+	if err := sdkFilter.Validate(); err != nil {
+		return email.JobFilter{}, err
+	}
+	if pbFilter.Recipient != "" {
+		recipient, err := email.ParseAddress(pbFilter.Recipient)
+		if err != nil {
+			return email.JobFilter{}, err
+		}
+		filter.Recipient = recipient.Email
+	}
+
+	for _, pbState := range pbFilter.States {
+		state, ok := convertStateFromProto(pbState)
+		if !ok {
+			return email.JobFilter{}, fmt.Errorf("invalid state %v", pbState)
+		}
+		filter.States = append(filter.States, state)
+	}
+
+	return filter, nil
+}
+
+// jobError maps errors from inspecting or changing emails onto gRPC status codes
+func jobError(ctx context.Context, err error, internalMsg string) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, email.ErrMessageNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, email.ErrMessageNotPending), errors.Is(err, email.ErrMessageNotFailed), errors.Is(err, email.ErrMessageNotFinished):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, email.ErrInvalidPageToken):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return internalError(ctx, err, internalMsg)
+	}
+}
+
+// convertJobToProto converts a domain job to its protobuf representation
+func convertJobToProto(job *email.Job) *pb.Job {
+	pbJob := &pb.Job{
+		MessageId:   job.MessageID,
+		State:       convertStateToProto(job.State),
+		TemplateId:  job.TemplateName,
+		To:          job.To.Strings(),
+		Cc:          job.CC.Strings(),
+		Bcc:         job.BCC.Strings(),
+		From:        job.From.String(),
+		Subject:     job.Subject,
+		Attempts:    int32(job.Attempts),
+		MaxAttempts: int32(job.MaxAttempts),
+		CreatedAt:   timestamppb.New(job.CreatedAt),
+		ScheduledAt: timestamppb.New(job.ScheduledAt),
+		Provider:    job.Provider,
+	}
+
+	if job.AttemptedAt != nil {
+		pbJob.AttemptedAt = timestamppb.New(*job.AttemptedAt)
+	}
+	if job.FinalizedAt != nil {
+		pbJob.FinalizedAt = timestamppb.New(*job.FinalizedAt)
+	}
+
+	for _, jobErr := range job.Errors {
+		pbJob.Errors = append(pbJob.Errors, &pb.JobError{
+			Attempt:    int32(jobErr.Attempt),
+			Error:      jobErr.Error,
+			OccurredAt: timestamppb.New(jobErr.OccurredAt),
+		})
+	}
+
+	return pbJob
+}
+
+// convertStateFromProto maps a protobuf delivery state to its domain value
+func convertStateFromProto(state pb.EmailState) (email.State, bool) {
+	switch state {
+	case pb.EmailState_EMAIL_STATE_SCHEDULED:
+		return email.StateScheduled, true
+	case pb.EmailState_EMAIL_STATE_QUEUED:
+		return email.StateQueued, true
+	case pb.EmailState_EMAIL_STATE_SENDING:
+		return email.StateSending, true
+	case pb.EmailState_EMAIL_STATE_SENT:
+		return email.StateSent, true
+	case pb.EmailState_EMAIL_STATE_RETRYING:
+		return email.StateRetrying, true
+	case pb.EmailState_EMAIL_STATE_FAILED:
+		return email.StateFailed, true
+	case pb.EmailState_EMAIL_STATE_CANCELLED:
+		return email.StateCancelled, true
+	default:
+		return "", false
+	}
+}
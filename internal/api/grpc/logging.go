@@ -0,0 +1,45 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/travisbale/mailman/internal/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// loggingInterceptor logs every unary request once it has been handled, with any
+// fields the handler added to the request's context
+func loggingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx = logging.NewContext(ctx, "method", info.FullMethod)
+	if client, ok := ClientIdentity(ctx); ok {
+		logging.AddAttrs(ctx, "client", client)
+	}
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+
+	code := status.Code(err)
+	logger := logging.FromContext(ctx).With("code", code.String(), "duration", time.Since(start))
+
+	switch code {
+	case codes.OK:
+		logger.Debug("Request handled")
+	case codes.Internal, codes.Unknown, codes.Unavailable, codes.DataLoss:
+		logger.Error("Request failed", "status", status.Convert(err).Message())
+	default:
+		logger.Info("Request rejected", "status", status.Convert(err).Message())
+	}
+
+	return resp, err
+}
+
+// internalError hides the cause of an unexpected failure from the client, and
+// records it for the request's log line instead
+func internalError(ctx context.Context, err error, msg string) error {
+	logging.AddAttrs(ctx, slog.Any("error", err))
+	return status.Error(codes.Internal, msg)
+}
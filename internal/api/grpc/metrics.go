@@ -0,0 +1,22 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/travisbale/mailman/internal/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// metricsInterceptor counts and times every unary request by method and status code
+func metricsInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+
+	code := status.Code(err).String()
+	metrics.GRPCRequests.WithLabelValues(info.FullMethod, code).Inc()
+	metrics.GRPCRequestDuration.WithLabelValues(info.FullMethod, code).Observe(time.Since(start).Seconds())
+
+	return resp, err
+}
@@ -3,39 +3,95 @@ package grpc
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net"
+	"time"
 
 	"github.com/travisbale/mailman/internal/email"
 	"github.com/travisbale/mailman/internal/pb"
+	"github.com/travisbale/mailman/sdk"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 type emailService interface {
-	Send(ctx context.Context, req email.SendRequest) error
+	Send(ctx context.Context, req email.SendRequest) (string, error)
+	SendBatch(ctx context.Context, reqs []email.SendRequest) ([]string, error)
+	Render(ctx context.Context, req email.RenderRequest) (*email.RenderedTemplate, error)
+	GetStatus(ctx context.Context, messageID string) (*email.Status, error)
+	Cancel(ctx context.Context, messageID string) error
+	CancelGroup(ctx context.Context, cancellationKey string) (int, error)
+	Reschedule(ctx context.Context, messageID string, scheduledAt time.Time) error
+	RescheduleGroup(ctx context.Context, cancellationKey string, scheduledAt time.Time) (int, error)
+	ListJobs(ctx context.Context, filter email.JobFilter, pageSize int, pageToken string) ([]*email.Job, string, error)
+	GetJob(ctx context.Context, messageID string) (*email.Job, error)
+	RetryMessages(ctx context.Context, messageIDs []string) (int, []email.SkippedJob, error)
+	RetryJobs(ctx context.Context, filter email.JobFilter) (int, error)
+	CancelMessages(ctx context.Context, messageIDs []string) (int, []email.SkippedJob, error)
+	CancelJobs(ctx context.Context, filter email.JobFilter) (int, error)
+	DiscardMessages(ctx context.Context, messageIDs []string) (int, []email.SkippedJob, error)
+	DiscardJobs(ctx context.Context, filter email.JobFilter) (int, error)
 }
 
-type templatesDB interface {
-	List(ctx context.Context) ([]*email.Template, error)
+type templateService interface {
+	CreateTemplate(ctx context.Context, template *email.Template) (*email.Template, error)
+	GetTemplate(ctx context.Context, name, locale string) (*email.Template, error)
+	UpdateTemplate(ctx context.Context, template *email.Template) (*email.Template, error)
+	DeleteTemplate(ctx context.Context, name, locale string, version int32) error
+	ListTemplates(ctx context.Context) ([]*email.Template, error)
+}
+
+type suppressionService interface {
+	Suppress(ctx context.Context, address, reason, source string, expiresAt *time.Time) (*email.Suppression, error)
+	ListSuppressions(ctx context.Context) ([]*email.Suppression, error)
+	RemoveSuppression(ctx context.Context, address string) error
 }
 
 // Server implements the MailmanService gRPC service
 type Server struct {
 	pb.UnimplementedMailmanServiceServer
-	emailService emailService
-	templatesDB  templatesDB
-	grpcServer   *grpc.Server
-	address      string
+	emailService       emailService
+	templateService    templateService
+	suppressionService suppressionService
+	grpcServer         *grpc.Server
+	address            string
+	tls                bool
 }
 
-// NewServer creates a new gRPC server
-func NewServer(address string, emailService emailService, templatesDB templatesDB) *Server {
-	grpcServer := grpc.NewServer()
+// NewServer creates a new gRPC server. Calls must present an API key when apiKeys
+// is set; a nil apiKeys leaves the API open to anyone who can reach it. The server
+// only accepts TLS connections when certs is set.
+func NewServer(address string, emailService emailService, templateService templateService, suppressionService suppressionService, apiKeys apiKeyAuthenticator, certs *Certificates) *Server {
+	unary := []grpc.UnaryServerInterceptor{loggingInterceptor, metricsInterceptor}
+	var stream []grpc.StreamServerInterceptor
+	if apiKeys != nil {
+		auth := &authInterceptor{keys: apiKeys}
+		unary = append(unary, auth.unary)
+		stream = append(stream, auth.stream)
+	}
+
+	opts := []grpc.ServerOption{
+		// Attachments push requests well past gRPC's 4 MB default
+		grpc.MaxRecvMsgSize(sdk.MaxMessageSize),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+		// Continues traces started by clients and starts a span for every request
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+	}
+	if certs != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(certs.TLSConfig())))
+	}
+
+	grpcServer := grpc.NewServer(opts...)
 
 	server := &Server{
-		emailService: emailService,
-		templatesDB:  templatesDB,
-		grpcServer:   grpcServer,
-		address:      address,
+		emailService:       emailService,
+		templateService:    templateService,
+		suppressionService: suppressionService,
+		grpcServer:         grpcServer,
+		address:            address,
+		tls:                certs != nil,
 	}
 
 	pb.RegisterMailmanServiceServer(grpcServer, server)
@@ -50,7 +106,7 @@ func (s *Server) ListenAndServe() error {
 		return fmt.Errorf("failed to listen on %s: %w", s.address, err)
 	}
 
-	fmt.Printf("Starting gRPC server on %s\n", s.address)
+	slog.Info("Starting gRPC server", "address", s.address, "tls", s.tls)
 	if err := s.grpcServer.Serve(listener); err != nil {
 		return fmt.Errorf("failed to serve gRPC: %w", err)
 	}
@@ -0,0 +1,96 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/travisbale/mailman/internal/email"
+	"github.com/travisbale/mailman/internal/pb"
+	"github.com/travisbale/mailman/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AddSuppression stops emails from being sent to an address
+func (s *Server) AddSuppression(ctx context.Context, req *pb.AddSuppressionRequest) (*pb.AddSuppressionResponse, error) {
+	sdkReq := sdk.AddSuppressionRequest{
+		Address: req.Address,
+		Reason:  sdk.SuppressionReason(req.Reason),
+		Source:  req.Source,
+	}
+	if req.ExpiresAt != nil {
+		expiresAt := req.ExpiresAt.AsTime()
+		sdkReq.ExpiresAt = &expiresAt
+	}
+
+	if err := sdkReq.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	source := req.Source
+	if source == "" {
+		source = "api"
+	}
+
+	suppression, err := s.suppressionService.Suppress(ctx, req.Address, req.Reason, source, sdkReq.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, email.ErrInvalidAddress) || errors.Is(err, email.ErrInvalidSuppressionReason) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, internalError(ctx, err, "failed to add suppression")
+	}
+
+	return &pb.AddSuppressionResponse{
+		Suppression: convertSuppressionToProto(suppression),
+	}, nil
+}
+
+// ListSuppressions returns every suppression, including expired ones
+func (s *Server) ListSuppressions(ctx context.Context, req *pb.ListSuppressionsRequest) (*pb.ListSuppressionsResponse, error) {
+	suppressions, err := s.suppressionService.ListSuppressions(ctx)
+	if err != nil {
+		return nil, internalError(ctx, err, "failed to list suppressions")
+	}
+
+	pbSuppressions := make([]*pb.Suppression, 0, len(suppressions))
+	for _, suppression := range suppressions {
+		pbSuppressions = append(pbSuppressions, convertSuppressionToProto(suppression))
+	}
+
+	return &pb.ListSuppressionsResponse{
+		Suppressions: pbSuppressions,
+	}, nil
+}
+
+// RemoveSuppression allows emails to be sent to an address again
+func (s *Server) RemoveSuppression(ctx context.Context, req *pb.RemoveSuppressionRequest) (*pb.RemoveSuppressionResponse, error) {
+	if req.Address == "" {
+		return nil, status.Error(codes.InvalidArgument, "address is required")
+	}
+
+	if err := s.suppressionService.RemoveSuppression(ctx, req.Address); err != nil {
+		if errors.Is(err, email.ErrSuppressionNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, internalError(ctx, err, "failed to remove suppression")
+	}
+
+	return &pb.RemoveSuppressionResponse{}, nil
+}
+
+// convertSuppressionToProto converts a domain suppression to its protobuf representation
+func convertSuppressionToProto(suppression *email.Suppression) *pb.Suppression {
+	pbSuppression := &pb.Suppression{
+		Address:   suppression.Address,
+		Reason:    suppression.Reason,
+		Source:    suppression.Source,
+		CreatedAt: timestamppb.New(suppression.CreatedAt),
+	}
+
+	if suppression.ExpiresAt != nil {
+		pbSuppression.ExpiresAt = timestamppb.New(*suppression.ExpiresAt)
+	}
+
+	return pbSuppression
+}
@@ -0,0 +1,191 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/travisbale/mailman/internal/email"
+	"github.com/travisbale/mailman/internal/logging"
+	"github.com/travisbale/mailman/internal/pb"
+	"github.com/travisbale/mailman/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ListTemplates returns all available email templates
+func (s *Server) ListTemplates(ctx context.Context, req *pb.ListTemplatesRequest) (*pb.ListTemplatesResponse, error) {
+	templates, err := s.templateService.ListTemplates(ctx)
+	if err != nil {
+		return nil, internalError(ctx, err, "failed to list templates")
+	}
+
+	pbTemplates := make([]*pb.EmailTemplate, 0, len(templates))
+	for _, t := range templates {
+		pbTemplates = append(pbTemplates, convertTemplateToProto(t))
+	}
+
+	return &pb.ListTemplatesResponse{
+		Templates: pbTemplates,
+	}, nil
+}
+
+// CreateTemplate validates and stores a new email template
+func (s *Server) CreateTemplate(ctx context.Context, req *pb.CreateTemplateRequest) (*pb.CreateTemplateResponse, error) {
+	logging.AddAttrs(ctx, "template", req.Id, "locale", req.Locale)
+
+	sdkReq := sdk.CreateTemplateRequest{
+		ID:                req.Id,
+		Locale:            req.Locale,
+		HTMLBody:          req.HtmlBody,
+		SuppressionPolicy: sdk.SuppressionPolicy(req.SuppressionPolicy),
+	}
+
+	if err := sdkReq.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	created, err := s.templateService.CreateTemplate(ctx, &email.Template{
+		Name:              req.Id,
+		Locale:            req.Locale,
+		Subject:           req.Subject,
+		HTMLBody:          req.HtmlBody,
+		TextBody:          optionalString(req.TextBody),
+		BaseTemplateName:  optionalString(req.BaseTemplateName),
+		Variables:         req.Variables,
+		DefaultSender:     optionalString(req.DefaultSender),
+		SuppressionPolicy: req.SuppressionPolicy,
+	})
+	if err != nil {
+		return nil, templateError(ctx, err, "failed to create template")
+	}
+
+	return &pb.CreateTemplateResponse{
+		Template: convertTemplateToProto(created),
+	}, nil
+}
+
+// GetTemplate returns the variant of a template a send in the requested locale
+// would use, including its bodies
+func (s *Server) GetTemplate(ctx context.Context, req *pb.GetTemplateRequest) (*pb.GetTemplateResponse, error) {
+	logging.AddAttrs(ctx, "template", req.Id, "locale", req.Locale)
+
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	template, err := s.templateService.GetTemplate(ctx, req.Id, req.Locale)
+	if err != nil {
+		return nil, templateError(ctx, err, "failed to get template")
+	}
+
+	return &pb.GetTemplateResponse{
+		Template: convertTemplateToProto(template),
+	}, nil
+}
+
+// UpdateTemplate replaces a template's content using optimistic concurrency on its version
+func (s *Server) UpdateTemplate(ctx context.Context, req *pb.UpdateTemplateRequest) (*pb.UpdateTemplateResponse, error) {
+	logging.AddAttrs(ctx, "template", req.Id, "locale", req.Locale)
+
+	sdkReq := sdk.UpdateTemplateRequest{
+		ID:                req.Id,
+		Locale:            req.Locale,
+		HTMLBody:          req.HtmlBody,
+		SuppressionPolicy: sdk.SuppressionPolicy(req.SuppressionPolicy),
+		Version:           req.Version,
+	}
+
+	if err := sdkReq.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	updated, err := s.templateService.UpdateTemplate(ctx, &email.Template{
+		Name:              req.Id,
+		Locale:            req.Locale,
+		Subject:           req.Subject,
+		HTMLBody:          req.HtmlBody,
+		TextBody:          optionalString(req.TextBody),
+		BaseTemplateName:  optionalString(req.BaseTemplateName),
+		Variables:         req.Variables,
+		DefaultSender:     optionalString(req.DefaultSender),
+		SuppressionPolicy: req.SuppressionPolicy,
+		Version:           req.Version,
+	})
+	if err != nil {
+		return nil, templateError(ctx, err, "failed to update template")
+	}
+
+	return &pb.UpdateTemplateResponse{
+		Template: convertTemplateToProto(updated),
+	}, nil
+}
+
+// DeleteTemplate removes one variant of a template. A default variant cannot be
+// removed while other templates inherit from it.
+func (s *Server) DeleteTemplate(ctx context.Context, req *pb.DeleteTemplateRequest) (*pb.DeleteTemplateResponse, error) {
+	logging.AddAttrs(ctx, "template", req.Id, "locale", req.Locale)
+
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	if err := s.templateService.DeleteTemplate(ctx, req.Id, req.Locale, req.Version); err != nil {
+		return nil, templateError(ctx, err, "failed to delete template")
+	}
+
+	return &pb.DeleteTemplateResponse{}, nil
+}
+
+// templateError maps template service errors onto gRPC status codes
+func templateError(ctx context.Context, err error, internalMsg string) error {
+	switch {
+	case errors.Is(err, email.ErrTemplateNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, email.ErrTemplateExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, email.ErrVersionConflict):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, email.ErrTemplateInUse):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, email.ErrCircularReference), errors.Is(err, email.ErrSenderNotAllowed), errors.Is(err, email.ErrInvalidLocale):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return internalError(ctx, err, internalMsg)
+	}
+}
+
+// convertTemplateToProto converts a domain template to its protobuf representation
+func convertTemplateToProto(t *email.Template) *pb.EmailTemplate {
+	pbTemplate := &pb.EmailTemplate{
+		Id:                t.Name,
+		Locale:            t.Locale,
+		Subject:           t.Subject,
+		Variables:         t.Variables,
+		Version:           t.Version,
+		HtmlBody:          t.HTMLBody,
+		SuppressionPolicy: t.SuppressionPolicy,
+		CreatedAt:         timestamppb.New(t.CreatedAt),
+		UpdatedAt:         timestamppb.New(t.UpdatedAt),
+	}
+
+	if t.TextBody != nil {
+		pbTemplate.TextBody = *t.TextBody
+	}
+	if t.BaseTemplateName != nil {
+		pbTemplate.BaseTemplateName = *t.BaseTemplateName
+	}
+	if t.DefaultSender != nil {
+		pbTemplate.DefaultSender = *t.DefaultSender
+	}
+
+	return pbTemplate
+}
+
+// optionalString maps proto3's empty-string default to a nil pointer
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,160 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Certificates holds the server's TLS certificate and, for mutual TLS, the CAs
+// that client certificates must chain to. Both are swapped in place when their
+// files change, so rotating a certificate doesn't need a restart.
+type Certificates struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool // Nil unless client certificates are required
+}
+
+// LoadCertificates reads the server's certificate and key. When clientCAFile is
+// set, clients must present a certificate signed by one of its CAs.
+func LoadCertificates(certFile, keyFile, clientCAFile string) (*Certificates, error) {
+	c := &Certificates{
+		certFile:     certFile,
+		keyFile:      keyFile,
+		clientCAFile: clientCAFile,
+	}
+
+	if err := c.Reload(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Reload reads the certificate files again. The previous certificates stay in
+// use if any file is missing or invalid.
+func (c *Certificates) Reload() error {
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if c.clientCAFile != "" {
+		pem, err := os.ReadFile(c.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA file: %w", err)
+		}
+
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return errors.New("failed to load client CA file: no PEM certificates found")
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cert = &cert
+	c.clientCAs = clientCAs
+
+	return nil
+}
+
+// TLSConfig returns a server configuration that hands each new connection the
+// certificates loaded most recently
+func (c *Certificates) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			c.mu.RLock()
+			defer c.mu.RUnlock()
+
+			config := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*c.cert},
+				NextProtos:   []string{"h2"}, // Replaces the config gRPC negotiates ALPN with
+			}
+			if c.clientCAs != nil {
+				config.ClientAuth = tls.RequireAndVerifyClientCert
+				config.ClientCAs = c.clientCAs
+			}
+
+			return config, nil
+		},
+	}
+}
+
+// affectedBy reports whether a change to a path in a watched directory may have
+// replaced a certificate file
+func (c *Certificates) affectedBy(path string) bool {
+	path = filepath.Clean(path)
+
+	// Kubernetes updates mounted secrets by swapping the ..data symlink the files
+	// resolve through
+	if filepath.Base(path) == "..data" {
+		return true
+	}
+
+	for _, file := range []string{c.certFile, c.keyFile, c.clientCAFile} {
+		if file != "" && filepath.Clean(file) == path {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Watch reloads the certificates whenever their files change, until the context
+// is cancelled. The directories are watched rather than the files themselves, so
+// files replaced by a rename, as Kubernetes does when a secret is updated, are
+// still picked up.
+func (c *Certificates) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch TLS certificates: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+
+	for _, file := range []string{c.certFile, c.keyFile, c.clientCAFile} {
+		if file == "" {
+			continue
+		}
+		if err := watcher.Add(filepath.Dir(file)); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", file, err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-watcher.Events:
+			if event.Op == fsnotify.Chmod || !c.affectedBy(event.Name) {
+				continue
+			}
+
+			// A certificate and its key are rarely written at the same instant, so
+			// a failed reload is expected until the second file lands
+			if err := c.Reload(); err != nil {
+				slog.Warn("Failed to reload TLS certificates", "error", err)
+				continue
+			}
+			slog.Info("Reloaded TLS certificates", "file", event.Name)
+		case err := <-watcher.Errors:
+			slog.Warn("TLS certificate watcher failed", "error", err)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package grpc_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mailmangrpc "github.com/travisbale/mailman/internal/api/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// writeCertificate writes a new self-signed certificate and key for commonName
+// to certFile and keyFile
+func writeCertificate(t *testing.T, certFile, keyFile, commonName string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+}
+
+// servedCommonName returns the common name of the certificate a new connection
+// would be served
+func servedCommonName(t *testing.T, certs *mailmangrpc.Certificates) string {
+	t.Helper()
+
+	config, err := certs.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+	require.Len(t, config.Certificates, 1)
+
+	leaf, err := x509.ParseCertificate(config.Certificates[0].Certificate[0])
+	require.NoError(t, err)
+	return leaf.Subject.CommonName
+}
+
+func TestCertificates_Reload(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	writeCertificate(t, certFile, keyFile, "first")
+
+	certs, err := mailmangrpc.LoadCertificates(certFile, keyFile, "")
+	require.NoError(t, err)
+	assert.Equal(t, "first", servedCommonName(t, certs))
+
+	// A broken certificate leaves the previous one in use
+	require.NoError(t, os.WriteFile(certFile, []byte("not a certificate"), 0o600))
+	require.Error(t, certs.Reload())
+	assert.Equal(t, "first", servedCommonName(t, certs))
+
+	writeCertificate(t, certFile, keyFile, "second")
+	require.NoError(t, certs.Reload())
+	assert.Equal(t, "second", servedCommonName(t, certs))
+}
+
+func TestCertificates_Watch(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	writeCertificate(t, certFile, keyFile, "first")
+
+	certs, err := mailmangrpc.LoadCertificates(certFile, keyFile, "")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- certs.Watch(ctx) }()
+
+	// The watcher may not be ready yet, so keep replacing the certificate until
+	// it is picked up
+	assert.Eventually(t, func() bool {
+		writeCertificate(t, certFile, keyFile, "second")
+		return servedCommonName(t, certs) == "second"
+	}, 5*time.Second, 50*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestCertificates_ClientCA(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	caFile := filepath.Join(dir, "ca.crt")
+	writeCertificate(t, certFile, keyFile, "server")
+	writeCertificate(t, caFile, filepath.Join(dir, "ca.key"), "client-ca")
+
+	certs, err := mailmangrpc.LoadCertificates(certFile, keyFile, caFile)
+	require.NoError(t, err)
+
+	config, err := certs.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, config.ClientAuth)
+	assert.NotNil(t, config.ClientCAs)
+
+	_, err = mailmangrpc.LoadCertificates(certFile, keyFile, keyFile)
+	assert.Error(t, err)
+}
+
+func TestClientIdentity(t *testing.T) {
+	t.Parallel()
+
+	spiffeID, err := url.Parse("spiffe://example.com/billing")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		cert     *x509.Certificate
+		expected string
+	}{
+		{
+			name:     "URI SAN",
+			cert:     &x509.Certificate{URIs: []*url.URL{spiffeID}, DNSNames: []string{"billing.internal"}, Subject: pkix.Name{CommonName: "billing"}},
+			expected: "spiffe://example.com/billing",
+		},
+		{
+			name:     "DNS SAN",
+			cert:     &x509.Certificate{DNSNames: []string{"billing.internal"}, Subject: pkix.Name{CommonName: "billing"}},
+			expected: "billing.internal",
+		},
+		{
+			name:     "common name",
+			cert:     &x509.Certificate{Subject: pkix.Name{CommonName: "billing"}},
+			expected: "billing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := peer.NewContext(context.Background(), &peer.Peer{
+				AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+					VerifiedChains: [][]*x509.Certificate{{tt.cert}},
+				}},
+			})
+
+			identity, ok := mailmangrpc.ClientIdentity(ctx)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, identity)
+		})
+	}
+
+	_, ok := mailmangrpc.ClientIdentity(context.Background())
+	assert.False(t, ok)
+}
@@ -4,17 +4,42 @@ import (
 	"context"
 	"net/http"
 	"sync"
+
+	"github.com/travisbale/mailman/internal/email"
 )
 
 type database interface {
 	Health(ctx context.Context) error
 }
 
+type eventWebhook interface {
+	ParseEvents(header http.Header, payload []byte) ([]*email.DeliveryEvent, error)
+}
+
+type tenantEventWebhook interface {
+	ParseEvents(ctx context.Context, tenantID string, header http.Header, payload []byte) ([]*email.DeliveryEvent, error)
+}
+
+type eventRecorder interface {
+	Record(ctx context.Context, events []*email.DeliveryEvent) error
+}
+
 // Router holds all HTTP handler dependencies in a single struct.
 // Implements http.Handler — routes and middleware are initialized on first request.
 type Router struct {
 	DB database
 
+	// SendGridWebhook is nil unless a webhook verification key is configured
+	SendGridWebhook eventWebhook
+
+	// TenantSendGridWebhook accepts events from tenants' own SendGrid accounts when set
+	TenantSendGridWebhook tenantEventWebhook
+
+	Events eventRecorder
+
+	// Metrics serves Prometheus metrics on /metrics when set
+	Metrics http.Handler
+
 	once    sync.Once
 	handler http.Handler
 }
@@ -35,4 +60,16 @@ func (r *Router) init() {
 // registerRoutes configures all HTTP routes with their handlers
 func (r *Router) registerRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("HEAD /healthz", r.handleHealth)
+
+	if r.Metrics != nil {
+		mux.Handle("GET /metrics", r.Metrics)
+	}
+
+	if r.SendGridWebhook != nil {
+		mux.HandleFunc("POST /webhooks/sendgrid", r.handleSendGridEvents)
+	}
+
+	if r.TenantSendGridWebhook != nil {
+		mux.HandleFunc("POST /webhooks/sendgrid/{tenant}", r.handleTenantSendGridEvents)
+	}
 }
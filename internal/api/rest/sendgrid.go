@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/travisbale/mailman/internal/clients/sendgrid"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// maxWebhookBodySize bounds event batches; SendGrid posts well under 1 MB at a time
+const maxWebhookBodySize = 5 << 20
+
+// handleSendGridEvents records a batch of events from SendGrid's signed Event Webhook.
+// Failing to record returns a 5xx so SendGrid retries the batch.
+func (r *Router) handleSendGridEvents(w http.ResponseWriter, req *http.Request) {
+	r.recordSendGridEvents(w, req, func(payload []byte) ([]*email.DeliveryEvent, error) {
+		return r.SendGridWebhook.ParseEvents(req.Header, payload)
+	})
+}
+
+// handleTenantSendGridEvents records a batch of events from a tenant's own
+// SendGrid account, signed with that account's key
+func (r *Router) handleTenantSendGridEvents(w http.ResponseWriter, req *http.Request) {
+	tenantID := req.PathValue("tenant")
+	r.recordSendGridEvents(w, req, func(payload []byte) ([]*email.DeliveryEvent, error) {
+		return r.TenantSendGridWebhook.ParseEvents(req.Context(), tenantID, req.Header, payload)
+	})
+}
+
+// recordSendGridEvents reads a webhook request, parses its events with parse and
+// records them
+func (r *Router) recordSendGridEvents(w http.ResponseWriter, req *http.Request, parse func(payload []byte) ([]*email.DeliveryEvent, error)) {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxWebhookBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	events, err := parse(payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, sendgrid.ErrInvalidSignature):
+			w.WriteHeader(http.StatusUnauthorized)
+		case errors.Is(err, sendgrid.ErrInvalidPayload):
+			w.WriteHeader(http.StatusBadRequest)
+		case errors.Is(err, email.ErrTenantNotFound), errors.Is(err, sendgrid.ErrWebhookNotConfigured):
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			slog.Error("Failed to verify SendGrid events", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if err := r.Events.Record(req.Context(), events); err != nil {
+		slog.Error("Failed to record SendGrid events", "events", len(events), "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,162 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/travisbale/mailman/internal/clients/console"
+	"github.com/travisbale/mailman/internal/clients/sendgrid"
+	"github.com/travisbale/mailman/internal/clients/smtp"
+	"github.com/travisbale/mailman/internal/email"
+	"github.com/travisbale/mailman/internal/queue/river"
+)
+
+// Provider names accepted in Config.Providers
+const (
+	ProviderSendGrid = "sendgrid"
+	ProviderSMTP     = "smtp"
+	ProviderConsole  = "console"
+)
+
+// ProviderNames lists every provider name accepted in Config.Providers
+var ProviderNames = []string{ProviderSendGrid, ProviderSMTP, ProviderConsole}
+
+// providerNames returns the configured delivery order, defaulting to every
+// provider that has credentials, or the console when none do
+func (c *Config) providerNames() []string {
+	if len(c.Providers) > 0 {
+		return c.Providers
+	}
+
+	var names []string
+	if c.SendGridAPIKey != "" {
+		names = append(names, ProviderSendGrid)
+	}
+	if c.SMTP.Host != "" {
+		names = append(names, ProviderSMTP)
+	}
+	if len(names) == 0 {
+		names = append(names, ProviderConsole)
+	}
+	return names
+}
+
+// newProviders creates an email client for each configured provider, in delivery order
+func newProviders(config *Config) ([]river.Provider, error) {
+	names := config.providerNames()
+
+	providers := make([]river.Provider, 0, len(names))
+	for _, name := range names {
+		if name == ProviderConsole && len(names) > 1 {
+			return nil, fmt.Errorf("the %s provider cannot be combined with other providers", ProviderConsole)
+		}
+
+		client, err := newProvider(name, config)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, river.Provider{Name: name, Client: client})
+	}
+
+	return providers, nil
+}
+
+// newProvider creates the email client for a single provider
+func newProvider(name string, config *Config) (river.EmailClient, error) {
+	switch name {
+	case ProviderSendGrid:
+		if config.SendGridAPIKey == "" {
+			return nil, fmt.Errorf("provider %s requires a SendGrid API key", name)
+		}
+		return sendgrid.New(config.SendGridAPIKey), nil
+	case ProviderSMTP:
+		if config.SMTP.Host == "" {
+			return nil, fmt.Errorf("provider %s requires an SMTP host", name)
+		}
+		smtpClient, err := smtp.New(smtp.Config{
+			Host:     config.SMTP.Host,
+			Port:     config.SMTP.Port,
+			Username: config.SMTP.Username,
+			Password: config.SMTP.Password,
+			TLSMode:  smtp.TLSMode(config.SMTP.TLSMode),
+			Auth:     smtp.AuthMechanism(config.SMTP.Auth),
+			PoolSize: config.SMTP.PoolSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SMTP client: %w", err)
+		}
+		return smtpClient, nil
+	case ProviderConsole:
+		return console.New(), nil
+	default:
+		return nil, fmt.Errorf("unknown email provider %q", name)
+	}
+}
+
+// CheckTenantProvider rejects a tenant provider the server doesn't know, and
+// credentials that can't be used to send through it
+func CheckTenantProvider(tenant *email.Tenant) error {
+	if tenant.Provider != "" && !slices.Contains(ProviderNames, tenant.Provider) {
+		return fmt.Errorf("unknown provider %q, expected one of %s", tenant.Provider, strings.Join(ProviderNames, ", "))
+	}
+	if tenant.Credentials == nil {
+		return nil
+	}
+
+	provider, err := newTenantProvider(tenant)
+	if err != nil {
+		return err
+	}
+	closeProvider(provider)
+
+	if key := tenant.Credentials.SendGridWebhookKey; key != "" {
+		if _, err := sendgrid.NewWebhook(key); err != nil {
+			return fmt.Errorf("invalid SendGrid webhook key: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// newTenantProvider creates the email client for a tenant's own account with its provider
+func newTenantProvider(tenant *email.Tenant) (*river.Provider, error) {
+	credentials := tenant.Credentials
+	smtpSet := credentials.SMTPHost != "" || credentials.SMTPPort != 0 || credentials.SMTPUsername != "" ||
+		credentials.SMTPPassword != "" || credentials.SMTPTLSMode != "" || credentials.SMTPAuth != ""
+
+	switch {
+	case tenant.Provider == ProviderConsole:
+		return nil, fmt.Errorf("the %s provider does not take credentials", ProviderConsole)
+	case tenant.Provider == ProviderSendGrid && smtpSet:
+		return nil, errors.New("SMTP settings cannot be used with the sendgrid provider")
+	case tenant.Provider == ProviderSMTP && (credentials.SendGridAPIKey != "" || credentials.SendGridWebhookKey != ""):
+		return nil, errors.New("SendGrid keys cannot be used with the smtp provider")
+	}
+
+	client, err := newProvider(tenant.Provider, &Config{
+		SendGridAPIKey: credentials.SendGridAPIKey,
+		SMTP: SMTPConfig{
+			Host:     credentials.SMTPHost,
+			Port:     credentials.SMTPPort,
+			Username: credentials.SMTPUsername,
+			Password: credentials.SMTPPassword,
+			TLSMode:  credentials.SMTPTLSMode,
+			Auth:     credentials.SMTPAuth,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials for tenant %s: %w", tenant.ID, err)
+	}
+
+	return &river.Provider{Name: tenant.Provider, Client: client, Tenant: tenant.ID}, nil
+}
+
+// closeProvider releases a provider's open connections, if it holds any
+func closeProvider(provider *river.Provider) {
+	if closer, ok := provider.Client.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
@@ -3,7 +3,10 @@ package app
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/riverqueue/river/riverdriver/riverpgxv5"
@@ -11,33 +14,68 @@ import (
 
 	"github.com/travisbale/mailman/internal/api/grpc"
 	"github.com/travisbale/mailman/internal/api/rest"
-	"github.com/travisbale/mailman/internal/clients/console"
 	"github.com/travisbale/mailman/internal/clients/sendgrid"
 	"github.com/travisbale/mailman/internal/db/postgres"
 	"github.com/travisbale/mailman/internal/email"
+	"github.com/travisbale/mailman/internal/metrics"
 	"github.com/travisbale/mailman/internal/queue/river"
 	"github.com/travisbale/mailman/internal/renderers/html"
 	"github.com/travisbale/mailman/internal/renderers/json"
+	"github.com/travisbale/mailman/internal/tracing"
 	"golang.org/x/sync/errgroup"
 )
 
 // Config holds application configuration
 type Config struct {
-	DatabaseURL    string
-	HTTPAddress    string
-	GRPCAddress    string
-	SendGridAPIKey string
-	FromAddress    string
-	FromName       string
+	DatabaseURL        string
+	HTTPAddress        string
+	GRPCAddress        string
+	Providers          []string // Delivery order; providers after the first are failovers
+	SendGridAPIKey     string
+	SendGridWebhookKey string // Empty disables the SendGrid event webhook
+	SMTP               SMTPConfig
+	FromAddress        string
+	FromName           string
+	RateLimits         email.RateLimits
+	IdempotencyTTL     time.Duration // How long an idempotency key returns the email it first sent
+	TLS                TLSConfig
+	DisableAuth        bool   // Accept gRPC calls without an API key
+	OTLPEndpoint       string // Empty disables exporting traces
+	Version            string
+}
+
+// SMTPConfig holds settings for delivering email through an SMTP relay
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	TLSMode  string
+	Auth     string
+	PoolSize int
+}
+
+// TLSConfig holds the certificate files the gRPC server uses. The server accepts
+// plaintext connections when CertFile is empty, and requires client certificates
+// when ClientCAFile is set.
+type TLSConfig struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
 }
 
 // Server represents the mailman application
 type Server struct {
 	config      *Config
 	db          *postgres.DB
+	templates   *postgres.TemplateCache
 	queueClient *river.JobQueue
+	emailClient river.EmailClient
 	httpServer  *http.Server
 	grpcServer  *grpc.Server
+	certs       *grpc.Certificates // Nil when the gRPC server doesn't use TLS
+
+	shutdownTracing func(context.Context) error
 }
 
 // NewServer creates and initializes a new application
@@ -62,49 +100,123 @@ func NewServer(ctx context.Context, config *Config) (*Server, error) {
 		return nil, fmt.Errorf("failed to run River migrations: %w", err)
 	}
 
-	templatesDB := postgres.NewTemplatesDB(db)
+	// Replicas drop cached templates when any of them changes one, see Server.Start
+	templatesDB := postgres.NewTemplateCache(postgres.NewTemplatesDB(db))
 
-	// Select email client and renderer based on configuration
-	var emailClient river.EmailClient
-	var emailRenderer email.Renderer
+	providers, err := newProviders(config)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
 
-	if config.SendGridAPIKey != "" {
-		fmt.Println("Using SendGrid email client with HTML renderer")
-		emailClient = sendgrid.New(config.SendGridAPIKey)
-		emailRenderer = html.New(templatesDB)
-	} else {
-		fmt.Println("Using console email client with JSON renderer")
-		emailClient = console.New()
-		emailRenderer = json.New()
+	tenantsDB := postgres.NewTenantsDB(db)
+
+	// The console prints emails as JSON; real providers need rendered HTML, so
+	// tenants only send through their own accounts alongside real providers
+	var emailRenderer email.Renderer = json.New()
+	var tenantProviders river.TenantProviders
+	if providers[0].Name != ProviderConsole {
+		htmlRenderer := html.New()
+		templatesDB.Subscribe(htmlRenderer.Reset)
+		emailRenderer = htmlRenderer
+		tenantProviders = newTenantProviders(tenantsDB)
+	}
+
+	names := make([]string, len(providers))
+	for i, provider := range providers {
+		names[i] = provider.Name
 	}
+	slog.Info("Delivering email", "providers", strings.Join(names, ", then "))
+
+	// Render latency is recorded whichever renderer is in use
+	emailRenderer = metrics.NewRenderer(emailRenderer)
+
+	// Each email draws from the limits of the provider that delivers it
+	rateLimiter := email.NewRateLimiter(postgres.NewRateLimitsDB(db), config.RateLimits)
+	emailClient := river.NewFailoverClient(rateLimiter, tenantProviders, providers...)
 
-	jobQueue, err := river.NewJobQueue(db, emailClient)
+	jobQueue, err := river.NewJobQueue(db, emailClient, config.IdempotencyTTL)
 	if err != nil {
 		db.Close()
 		return nil, fmt.Errorf("failed to initialize queue client: %w", err)
 	}
+	if err := metrics.Registry.Register(jobQueue.DepthCollector()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to register queue metrics: %w", err)
+	}
+
+	suppressionsDB := postgres.NewSuppressionsDB(db)
+	deliveryEventsDB := postgres.NewDeliveryEventsDB(db)
 
 	emailService := &email.Service{
-		Templates:   templatesDB,
-		Renderer:    emailRenderer,
-		Queue:       jobQueue,
-		FromAddress: config.FromAddress,
-		FromName:    config.FromName,
+		Templates:    templatesDB,
+		Senders:      postgres.NewSendersDB(db),
+		Suppressions: suppressionsDB,
+		Events:       deliveryEventsDB,
+		Tenants:      tenantsDB,
+		Renderer:     emailRenderer,
+		Queue:        jobQueue,
+		FromAddress:  config.FromAddress,
+		FromName:     config.FromName,
+	}
+
+	router := &rest.Router{
+		DB:                    db,
+		TenantSendGridWebhook: sendgrid.NewTenantWebhooks(tenantsDB),
+		Events:                email.NewEventService(deliveryEventsDB),
+		Metrics:               metrics.Handler(),
+	}
+	if config.SendGridWebhookKey != "" {
+		webhook, err := sendgrid.NewWebhook(config.SendGridWebhookKey)
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to configure SendGrid webhook: %w", err)
+		}
+		router.SendGridWebhook = webhook
 	}
 
 	httpServer := &http.Server{
 		Addr:              config.HTTPAddress,
-		Handler:           &rest.Router{DB: db},
+		Handler:           router,
 		ReadHeaderTimeout: 5 * time.Second, // Prevents Slowloris attacks
 	}
-	grpcServer := grpc.NewServer(config.GRPCAddress, emailService, templatesDB)
+	templateService := email.NewTemplateService(templatesDB)
+	suppressionService := email.NewSuppressionService(suppressionsDB)
+	var certs *grpc.Certificates
+	if config.TLS.CertFile != "" {
+		certs, err = grpc.LoadCertificates(config.TLS.CertFile, config.TLS.KeyFile, config.TLS.ClientCAFile)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	var grpcServer *grpc.Server
+	if config.DisableAuth {
+		slog.Warn("gRPC authentication is disabled; anyone who can reach the server can send email")
+		grpcServer = grpc.NewServer(config.GRPCAddress, emailService, templateService, suppressionService, nil, certs)
+	} else {
+		apiKeys := email.NewAPIKeyService(postgres.NewAPIKeysDB(db))
+		grpcServer = grpc.NewServer(config.GRPCAddress, emailService, templateService, suppressionService, apiKeys, certs)
+	}
+
+	shutdownTracing, err := tracing.Setup(ctx, config.OTLPEndpoint, config.Version)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize tracing: %w", err)
+	}
 
 	return &Server{
 		config:      config,
 		db:          db,
+		templates:   templatesDB,
 		queueClient: jobQueue,
+		emailClient: emailClient,
 		httpServer:  httpServer,
 		grpcServer:  grpcServer,
+		certs:       certs,
+
+		shutdownTracing: shutdownTracing,
 	}, nil
 }
 
@@ -116,7 +228,14 @@ func (s *Server) Start(ctx context.Context) error {
 
 	// Start both HTTP and gRPC servers concurrently
 	group, _ := errgroup.WithContext(ctx)
-	group.Go(func() error { return s.httpServer.ListenAndServe() })
+	group.Go(func() error { return s.templates.Listen(ctx) })
+	if s.certs != nil {
+		group.Go(func() error { return s.certs.Watch(ctx) })
+	}
+	group.Go(func() error {
+		slog.Info("Starting HTTP server", "address", s.httpServer.Addr)
+		return s.httpServer.ListenAndServe()
+	})
 	group.Go(func() error { return s.grpcServer.ListenAndServe() })
 
 	return group.Wait()
@@ -128,26 +247,40 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	group, gctx := errgroup.WithContext(ctx)
 
 	group.Go(func() error {
-		fmt.Println("Stopping HTTP server...")
+		slog.Info("Stopping HTTP server")
 		return s.httpServer.Shutdown(gctx)
 	})
 
 	group.Go(func() error {
-		fmt.Println("Stopping gRPC server...")
+		slog.Info("Stopping gRPC server")
 		s.grpcServer.Stop()
 		return nil
 	})
 
 	group.Go(func() error {
-		fmt.Println("Stopping queue client...")
+		slog.Info("Stopping queue client")
 		return s.queueClient.Stop(gctx)
 	})
 
 	err := group.Wait()
 
-	fmt.Println("Closing database connection...")
+	// Pooled clients hold open connections that must be released after workers stop
+	if closer, ok := s.emailClient.(io.Closer); ok {
+		slog.Info("Closing email client")
+		if closeErr := closer.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	// Flush spans from the requests and jobs that just finished
+	slog.Info("Flushing traces")
+	if tracingErr := s.shutdownTracing(ctx); tracingErr != nil && err == nil {
+		err = tracingErr
+	}
+
+	slog.Info("Closing database connection")
 	s.db.Close()
 
-	fmt.Println("Shutdown complete")
+	slog.Info("Shutdown complete")
 	return err
 }
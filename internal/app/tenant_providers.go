@@ -0,0 +1,112 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/travisbale/mailman/internal/email"
+	"github.com/travisbale/mailman/internal/queue/river"
+)
+
+// tenantProviderTTL is how long a tenant's provider is reused before its settings
+// are read again, so changes made with the CLI reach running servers
+const tenantProviderTTL = time.Minute
+
+type tenantGetter interface {
+	GetTenant(ctx context.Context, id string) (*email.Tenant, error)
+}
+
+// tenantProvider is the client built from a tenant's credentials
+type tenantProvider struct {
+	name        string
+	credentials *email.ProviderCredentials
+	provider    *river.Provider // Nil if the tenant sends through the server's providers
+	fallback    bool
+	loadedAt    time.Time
+}
+
+// tenantProviders builds and reuses a client for each tenant that sends through
+// its own provider account
+type tenantProviders struct {
+	tenants tenantGetter
+
+	mu        sync.Mutex
+	providers map[string]*tenantProvider
+}
+
+// newTenantProviders creates a resolver that looks up each tenant's credentials
+func newTenantProviders(tenants tenantGetter) *tenantProviders {
+	return &tenantProviders{
+		tenants:   tenants,
+		providers: make(map[string]*tenantProvider),
+	}
+}
+
+// Provider returns the tenant's own provider, or nil if it has no credentials,
+// and whether it opted into failing over to the server's providers. The client
+// is rebuilt when the tenant's credentials change.
+func (p *tenantProviders) Provider(ctx context.Context, tenantID string) (*river.Provider, bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cached, ok := p.providers[tenantID]
+	if ok && time.Since(cached.loadedAt) < tenantProviderTTL {
+		return cached.provider, cached.fallback, nil
+	}
+
+	tenant, err := p.tenants.GetTenant(ctx, tenantID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if ok && cached.name == tenant.Provider && sameCredentials(cached.credentials, tenant.Credentials) {
+		cached.fallback = tenant.ProviderFallback
+		cached.loadedAt = time.Now()
+		return cached.provider, cached.fallback, nil
+	}
+
+	var provider *river.Provider
+	if tenant.Credentials != nil {
+		if provider, err = newTenantProvider(tenant); err != nil {
+			return nil, false, err
+		}
+	}
+
+	// Sends already using the old client finish on the connections they hold
+	if ok && cached.provider != nil {
+		closeProvider(cached.provider)
+	}
+	p.providers[tenantID] = &tenantProvider{
+		name:        tenant.Provider,
+		credentials: tenant.Credentials,
+		provider:    provider,
+		fallback:    tenant.ProviderFallback,
+		loadedAt:    time.Now(),
+	}
+
+	return provider, tenant.ProviderFallback, nil
+}
+
+// Close releases the connections held by every tenant's client
+func (p *tenantProviders) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for tenantID, cached := range p.providers {
+		if cached.provider != nil {
+			closeProvider(cached.provider)
+		}
+		delete(p.providers, tenantID)
+	}
+
+	return nil
+}
+
+// sameCredentials reports whether two sets of credentials are equal
+func sameCredentials(a, b *email.ProviderCredentials) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -29,7 +29,22 @@ func (c *Client) Send(ctx context.Context, args email.JobArgs) error {
 	fmt.Fprintf(&b, "Sent: %s\n", time.Now().Format(time.RFC3339))
 	fmt.Fprintf(&b, "From: %s <%s>\n", args.FromName, args.From)
 	fmt.Fprintf(&b, "To: %s\n", args.To)
+	if len(args.CC) > 0 {
+		fmt.Fprintf(&b, "Cc: %s\n", args.CC)
+	}
+	if len(args.BCC) > 0 {
+		fmt.Fprintf(&b, "Bcc: %s\n", args.BCC)
+	}
+	if args.ReplyTo != nil {
+		fmt.Fprintf(&b, "Reply-To: %s\n", args.ReplyTo)
+	}
 	fmt.Fprintf(&b, "Subject: %s\n", args.Subject)
+	if len(args.Attachments) > 0 {
+		b.WriteString("Attachments:\n")
+		for _, a := range args.Attachments {
+			fmt.Fprintf(&b, "  - %s\n", describeAttachment(a))
+		}
+	}
 	b.WriteString("----------------------------------------\n")
 	if args.HTMLBody != "" {
 		b.WriteString("HTML Body:\n")
@@ -49,3 +64,12 @@ func (c *Client) Send(ctx context.Context, args email.JobArgs) error {
 
 	return nil
 }
+
+// describeAttachment summarizes an attachment on one line instead of dumping its bytes
+func describeAttachment(a email.Attachment) string {
+	desc := fmt.Sprintf("%s (%s, %d bytes, %s", a.Filename, a.ContentType, len(a.Content), a.Disposition)
+	if a.ContentID != "" {
+		desc += ", cid:" + a.ContentID
+	}
+	return desc + ")"
+}
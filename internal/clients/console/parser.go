@@ -10,7 +10,9 @@ import (
 type EmailLog struct {
 	To        string            `json:"to"`        // Recipient email address
 	Template  string            `json:"template"`  // Template name used
-	Variables map[string]string `json:"variables"` // Template variables (includes URLs with tokens)
+	Locale    string            `json:"locale"`    // Template variant used; empty for the default
+	Variables map[string]string `json:"-"`         // Template variables (includes URLs with tokens); structured values are JSON-encoded
+	Data      map[string]any    `json:"variables"` // Template variables as sent, including structured values
 	Subject   string            `json:"subject"`   // Rendered subject line
 }
 
@@ -72,6 +74,18 @@ func parseEmailJSON(recipient string, jsonLines []string, emails *[]EmailLog) er
 	}
 
 	log.To = recipient
+	log.Variables = make(map[string]string, len(log.Data))
+	for key, value := range log.Data {
+		if str, ok := value.(string); ok {
+			log.Variables[key] = str
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to encode variable %s: %w", key, err)
+		}
+		log.Variables[key] = string(encoded)
+	}
 	*emails = append(*emails, log)
 
 	return nil
@@ -0,0 +1,227 @@
+package sendgrid
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sendgrid/sendgrid-go/helpers/eventwebhook"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// ErrInvalidSignature is returned for webhook requests SendGrid did not sign
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// ErrInvalidPayload is returned for signed webhook requests whose events can't be decoded
+var ErrInvalidPayload = errors.New("invalid webhook payload")
+
+// ErrWebhookNotConfigured is returned for events posted for a tenant without a
+// verification key of its own
+var ErrWebhookNotConfigured = errors.New("webhook not configured")
+
+// provider identifies SendGrid as the source of delivery events and suppressions
+const provider = "sendgrid"
+
+// maxSignatureAge bounds how far a request's signed timestamp may be from now,
+// so a captured batch can't be replayed later. SendGrid signs each delivery,
+// including retries, when it is posted.
+const maxSignatureAge = 5 * time.Minute
+
+// Webhook verifies and parses SendGrid's signed Event Webhook requests
+type Webhook struct {
+	publicKey *ecdsa.PublicKey
+}
+
+// NewWebhook creates a webhook parser from the base64 verification key shown in
+// SendGrid's Signed Event Webhook settings
+func NewWebhook(publicKey string) (*Webhook, error) {
+	der, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode webhook public key: %w", err)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse webhook public key: %w", err)
+	}
+
+	ecdsaKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("webhook public key is %T, not ECDSA", key)
+	}
+
+	return &Webhook{publicKey: ecdsaKey}, nil
+}
+
+// event is the subset of a SendGrid event we record. Custom args set when the
+// email was sent appear as top-level fields.
+type event struct {
+	Email     string `json:"email"`
+	Timestamp int64  `json:"timestamp"`
+	Event     string `json:"event"`
+	EventID   string `json:"sg_event_id"`
+	Type      string `json:"type"` // "bounce" or "blocked" for bounce events
+	Reason    string `json:"reason"`
+	Response  string `json:"response"`
+	MessageID string `json:"mailman_message_id"`
+	Tenant    string `json:"mailman_tenant"`
+}
+
+// ParseEvents verifies the request signature, and that it was signed recently,
+// and converts the event batch to delivery events. Event types we don't track, and events without an ID to
+// deduplicate them by, are skipped.
+func (w *Webhook) ParseEvents(header http.Header, payload []byte) ([]*email.DeliveryEvent, error) {
+	signature := header.Get(eventwebhook.VerificationHTTPHeader)
+	timestamp := header.Get(eventwebhook.TimestampHTTPHeader)
+	if signature == "" || timestamp == "" {
+		return nil, ErrInvalidSignature
+	}
+
+	ok, err := eventwebhook.VerifySignature(w.publicKey, payload, signature, timestamp)
+	if err != nil || !ok {
+		return nil, ErrInvalidSignature
+	}
+
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	if age := time.Since(time.Unix(signedAt, 0)); age > maxSignatureAge || age < -maxSignatureAge {
+		return nil, fmt.Errorf("%w: signed %s ago", ErrInvalidSignature, age.Round(time.Second))
+	}
+
+	var sgEvents []event
+	if err := json.Unmarshal(payload, &sgEvents); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+	}
+
+	events := make([]*email.DeliveryEvent, 0, len(sgEvents))
+	for _, e := range sgEvents {
+		eventType, ok := convertEventType(e)
+		if !ok || e.EventID == "" {
+			continue
+		}
+
+		reason := e.Reason
+		if reason == "" {
+			reason = e.Response
+		}
+
+		events = append(events, &email.DeliveryEvent{
+			Provider:        provider,
+			ProviderEventID: e.EventID,
+			MessageID:       e.MessageID,
+			Tenant:          e.Tenant,
+			Type:            eventType,
+			Recipient:       e.Email,
+			Reason:          reason,
+			OccurredAt:      time.Unix(e.Timestamp, 0),
+		})
+	}
+
+	return events, nil
+}
+
+type tenantGetter interface {
+	GetTenant(ctx context.Context, id string) (*email.Tenant, error)
+}
+
+// TenantWebhooks verifies event webhooks from tenants' own SendGrid accounts,
+// each signed with that account's verification key
+type TenantWebhooks struct {
+	tenants tenantGetter
+
+	mu       sync.Mutex
+	webhooks map[string]*Webhook // Keyed by verification key
+}
+
+// NewTenantWebhooks creates a parser that looks up each tenant's verification key
+func NewTenantWebhooks(tenants tenantGetter) *TenantWebhooks {
+	return &TenantWebhooks{
+		tenants:  tenants,
+		webhooks: make(map[string]*Webhook),
+	}
+}
+
+// ParseEvents verifies the request with the tenant's own key and converts the
+// event batch to delivery events. Every event belongs to the tenant, since only
+// its account could have signed them.
+func (w *TenantWebhooks) ParseEvents(ctx context.Context, tenantID string, header http.Header, payload []byte) ([]*email.DeliveryEvent, error) {
+	tenant, err := w.tenants.GetTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if tenant.Credentials == nil || tenant.Credentials.SendGridWebhookKey == "" {
+		return nil, fmt.Errorf("%w: tenant %s has no SendGrid webhook key", ErrWebhookNotConfigured, tenantID)
+	}
+
+	webhook, err := w.webhook(tenant.Credentials.SendGridWebhookKey)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := webhook.ParseEvents(header, payload)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		event.Tenant = tenantID
+	}
+
+	return events, nil
+}
+
+// webhook returns the parser for a verification key, creating it on first use
+func (w *TenantWebhooks) webhook(publicKey string) (*Webhook, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if webhook, ok := w.webhooks[publicKey]; ok {
+		return webhook, nil
+	}
+
+	webhook, err := NewWebhook(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	w.webhooks[publicKey] = webhook
+
+	return webhook, nil
+}
+
+// convertEventType maps a SendGrid event to a domain event type
+func convertEventType(e event) (string, bool) {
+	switch e.Event {
+	case "processed":
+		return email.EventProcessed, true
+	case "delivered":
+		return email.EventDelivered, true
+	case "deferred":
+		return email.EventDeferred, true
+	case "dropped":
+		return email.EventDropped, true
+	case "bounce":
+		if e.Type == "blocked" {
+			return email.EventBlocked, true
+		}
+		return email.EventBounced, true
+	case "open":
+		return email.EventOpened, true
+	case "click":
+		return email.EventClicked, true
+	case "spamreport":
+		return email.EventSpamReport, true
+	case "unsubscribe":
+		return email.EventUnsubscribed, true
+	default:
+		return "", false
+	}
+}
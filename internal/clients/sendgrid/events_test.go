@@ -0,0 +1,199 @@
+package sendgrid_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sendgrid/sendgrid-go/helpers/eventwebhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/internal/clients/sendgrid"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// newSigner returns a webhook for a fresh key and a function that signs payloads
+// the way SendGrid does.
+func newSigner(t *testing.T) (*sendgrid.Webhook, func(payload []byte) http.Header) {
+	t.Helper()
+
+	publicKey, signAt := newKey(t)
+	webhook, err := sendgrid.NewWebhook(publicKey)
+	require.NoError(t, err)
+
+	return webhook, func(payload []byte) http.Header { return signAt(payload, time.Now()) }
+}
+
+// newKey returns the base64 verification key for a fresh key and a function that
+// signs payloads with it at a given time the way SendGrid does.
+func newKey(t *testing.T) (string, func(payload []byte, at time.Time) http.Header) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	signAt := func(payload []byte, at time.Time) http.Header {
+		timestamp := strconv.FormatInt(at.Unix(), 10)
+		digest := sha256.Sum256(append([]byte(timestamp), payload...))
+		signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		require.NoError(t, err)
+
+		header := http.Header{}
+		header.Set(eventwebhook.VerificationHTTPHeader, base64.StdEncoding.EncodeToString(signature))
+		header.Set(eventwebhook.TimestampHTTPHeader, timestamp)
+		return header
+	}
+
+	return base64.StdEncoding.EncodeToString(der), signAt
+}
+
+func TestWebhook_ParseEvents(t *testing.T) {
+	t.Parallel()
+
+	webhook, sign := newSigner(t)
+
+	payload := []byte(`[
+		{"email": "alice@example.com", "timestamp": 1700000000, "event": "delivered", "sg_event_id": "ev1", "response": "250 OK", "mailman_message_id": "42"},
+		{"email": "bob@example.com", "timestamp": 1700000001, "event": "bounce", "type": "bounce", "sg_event_id": "ev2", "reason": "550 No such user", "mailman_message_id": "42", "mailman_tenant": "payroll"},
+		{"email": "carol@example.com", "timestamp": 1700000002, "event": "bounce", "type": "blocked", "sg_event_id": "ev3", "reason": "421 Try later"},
+		{"email": "dave@example.com", "timestamp": 1700000003, "event": "group_resubscribe", "sg_event_id": "ev4"}
+	]`)
+
+	events, err := webhook.ParseEvents(sign(payload), payload)
+	require.NoError(t, err)
+	require.Len(t, events, 3, "untracked event types are skipped")
+
+	assert.Equal(t, &email.DeliveryEvent{
+		Provider:        "sendgrid",
+		ProviderEventID: "ev1",
+		MessageID:       "42",
+		Type:            email.EventDelivered,
+		Recipient:       "alice@example.com",
+		Reason:          "250 OK",
+		OccurredAt:      time.Unix(1700000000, 0),
+	}, events[0])
+	assert.Equal(t, email.EventBounced, events[1].Type)
+	assert.Equal(t, "550 No such user", events[1].Reason)
+	assert.Equal(t, "payroll", events[1].Tenant)
+	assert.Equal(t, email.EventBlocked, events[2].Type)
+	assert.Empty(t, events[2].MessageID)
+}
+
+func TestWebhook_ParseEvents_InvalidSignature(t *testing.T) {
+	t.Parallel()
+
+	publicKey, signAt := newKey(t)
+	webhook, err := sendgrid.NewWebhook(publicKey)
+	require.NoError(t, err)
+	sign := func(payload []byte) http.Header { return signAt(payload, time.Now()) }
+
+	payload := []byte(`[{"email": "alice@example.com", "event": "delivered", "sg_event_id": "ev1"}]`)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		body   []byte
+	}{
+		{
+			name:   "unsigned",
+			header: http.Header{},
+			body:   payload,
+		},
+		{
+			name:   "tampered payload",
+			header: sign(payload),
+			body:   []byte(`[{"email": "mallory@example.com", "event": "spamreport", "sg_event_id": "ev1"}]`),
+		},
+		{
+			name:   "signed too long ago",
+			header: signAt(payload, time.Now().Add(-time.Hour)),
+			body:   payload,
+		},
+		{
+			name: "malformed signature",
+			header: http.Header{
+				eventwebhook.VerificationHTTPHeader: {"not-base64!"},
+				eventwebhook.TimestampHTTPHeader:    {"1700000000"},
+			},
+			body: payload,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := webhook.ParseEvents(tt.header, tt.body)
+			require.ErrorIs(t, err, sendgrid.ErrInvalidSignature)
+		})
+	}
+}
+
+// fakeTenants is an in-memory tenant store keyed by ID
+type fakeTenants map[string]*email.Tenant
+
+func (f fakeTenants) GetTenant(_ context.Context, id string) (*email.Tenant, error) {
+	tenant, ok := f[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", email.ErrTenantNotFound, id)
+	}
+	return tenant, nil
+}
+
+func TestTenantWebhooks_ParseEvents(t *testing.T) {
+	t.Parallel()
+
+	payrollKey, signPayrollAt := newKey(t)
+	_, signOtherAt := newKey(t)
+	signPayroll := func(payload []byte) http.Header { return signPayrollAt(payload, time.Now()) }
+	signOther := func(payload []byte) http.Header { return signOtherAt(payload, time.Now()) }
+	webhooks := sendgrid.NewTenantWebhooks(fakeTenants{
+		"payroll": {ID: "payroll", Provider: "sendgrid", Credentials: &email.ProviderCredentials{SendGridWebhookKey: payrollKey}},
+		"hr":      {ID: "hr"},
+	})
+
+	payload := []byte(`[{"email": "alice@example.com", "event": "bounce", "type": "bounce", "sg_event_id": "ev1", "mailman_tenant": "hr"}]`)
+
+	// Events signed by the tenant's own account belong to that tenant
+	events, err := webhooks.ParseEvents(context.Background(), "payroll", signPayroll(payload), payload)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "payroll", events[0].Tenant)
+
+	// Another account's signature doesn't verify
+	_, err = webhooks.ParseEvents(context.Background(), "payroll", signOther(payload), payload)
+	require.ErrorIs(t, err, sendgrid.ErrInvalidSignature)
+
+	// Tenants without a key of their own, and unknown tenants, have no webhook
+	_, err = webhooks.ParseEvents(context.Background(), "hr", signPayroll(payload), payload)
+	require.ErrorIs(t, err, sendgrid.ErrWebhookNotConfigured)
+	_, err = webhooks.ParseEvents(context.Background(), "billing", signPayroll(payload), payload)
+	require.ErrorIs(t, err, email.ErrTenantNotFound)
+}
+
+func TestNewWebhook_RejectsNonECDSAKey(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	_, err = sendgrid.NewWebhook(base64.StdEncoding.EncodeToString(der))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not ECDSA")
+}
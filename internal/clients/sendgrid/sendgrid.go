@@ -2,13 +2,21 @@ package sendgrid
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"net/http"
 
 	"github.com/sendgrid/sendgrid-go"
 	"github.com/sendgrid/sendgrid-go/helpers/mail"
 	"github.com/travisbale/mailman/internal/email"
 )
 
+// Custom args carrying our message ID and tenant on SendGrid events
+const (
+	MessageIDCustomArg = "mailman_message_id"
+	TenantCustomArg    = "mailman_tenant"
+)
+
 // Client implements email delivery using SendGrid's API
 type Client struct {
 	apiKey string
@@ -23,10 +31,40 @@ func New(apiKey string) *Client {
 
 // Send delivers a pre-rendered email via SendGrid
 func (c *Client) Send(ctx context.Context, args email.JobArgs) error {
-	fromEmail := mail.NewEmail(args.FromName, args.From)
-	toEmail := mail.NewEmail("", args.To)
+	message := mail.NewV3Mail()
+	message.SetFrom(mail.NewEmail(args.FromName, args.From))
+	message.Subject = args.Subject
+
+	// A single personalization delivers one message that every recipient sees
+	personalization := mail.NewPersonalization()
+	personalization.AddTos(convertAddresses(args.To)...)
+	personalization.AddCCs(convertAddresses(args.CC)...)
+	personalization.AddBCCs(convertAddresses(args.BCC)...)
+	if args.MessageID != "" {
+		// Echoed back on webhook events so they can be matched to the email
+		personalization.SetCustomArg(MessageIDCustomArg, args.MessageID)
+	}
+	if args.Tenant != "" {
+		// Lets suppressions from the email's events land on the tenant that sent it
+		personalization.SetCustomArg(TenantCustomArg, args.Tenant)
+	}
+	message.AddPersonalizations(personalization)
 
-	message := mail.NewSingleEmail(fromEmail, args.Subject, toEmail, args.TextBody, args.HTMLBody)
+	if args.ReplyTo != nil {
+		message.SetReplyTo(mail.NewEmail(args.ReplyTo.Name, args.ReplyTo.Email))
+	}
+
+	// SendGrid requires text/plain to precede text/html
+	if args.TextBody != "" {
+		message.AddContent(mail.NewContent("text/plain", args.TextBody))
+	}
+	if args.HTMLBody != "" {
+		message.AddContent(mail.NewContent("text/html", args.HTMLBody))
+	}
+
+	for _, a := range args.Attachments {
+		message.AddAttachment(convertAttachment(a))
+	}
 
 	client := sendgrid.NewSendClient(c.apiKey)
 	response, err := client.Send(message)
@@ -34,9 +72,35 @@ func (c *Client) Send(ctx context.Context, args email.JobArgs) error {
 		return fmt.Errorf("failed to send email via SendGrid: %w", err)
 	}
 
-	if response.StatusCode >= 400 {
+	switch {
+	case response.StatusCode == http.StatusBadRequest, response.StatusCode == http.StatusRequestEntityTooLarge:
+		// The message itself is invalid; auth, rate limit and server errors are not
+		return fmt.Errorf("%w: SendGrid returned status %d: %s", email.ErrRejected, response.StatusCode, response.Body)
+	case response.StatusCode >= 400:
 		return fmt.Errorf("SendGrid returned error status %d: %s", response.StatusCode, response.Body)
 	}
 
 	return nil
 }
+
+// convertAddresses maps domain addresses to SendGrid emails
+func convertAddresses(addresses email.AddressList) []*mail.Email {
+	emails := make([]*mail.Email, len(addresses))
+	for i, a := range addresses {
+		emails[i] = mail.NewEmail(a.Name, a.Email)
+	}
+	return emails
+}
+
+// convertAttachment maps a domain attachment to SendGrid's base64 representation
+func convertAttachment(a email.Attachment) *mail.Attachment {
+	attachment := mail.NewAttachment()
+	attachment.SetContent(base64.StdEncoding.EncodeToString(a.Content))
+	attachment.SetType(a.ContentType)
+	attachment.SetFilename(a.Filename)
+	attachment.SetDisposition(a.Disposition)
+	if a.ContentID != "" {
+		attachment.SetContentID(a.ContentID)
+	}
+	return attachment
+}
@@ -0,0 +1,225 @@
+package smtp
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// buildMessage renders the email as an RFC 5322 message. When both bodies are
+// present they are sent as multipart/alternative so clients pick the richest
+// version they can display. Inline attachments wrap the bodies in
+// multipart/related and regular attachments wrap everything in multipart/mixed.
+func buildMessage(args email.JobArgs) ([]byte, error) {
+	var buf bytes.Buffer
+
+	from := mail.Address{Name: args.FromName, Address: args.From}
+
+	messageID, err := newMessageID(args.From)
+	if err != nil {
+		return nil, err
+	}
+
+	writeHeader(&buf, "From", from.String())
+	writeHeader(&buf, "To", encodeAddresses(args.To))
+	if len(args.CC) > 0 {
+		writeHeader(&buf, "Cc", encodeAddresses(args.CC))
+	}
+	if args.ReplyTo != nil {
+		writeHeader(&buf, "Reply-To", encodeAddresses(email.AddressList{*args.ReplyTo}))
+	}
+	writeHeader(&buf, "Subject", mime.QEncoding.Encode("utf-8", args.Subject))
+	writeHeader(&buf, "Date", time.Now().Format(time.RFC1123Z))
+	writeHeader(&buf, "Message-ID", messageID)
+	writeHeader(&buf, "MIME-Version", "1.0")
+
+	body := bodyEntity(args)
+	for _, key := range []string{"Content-Type", "Content-Disposition", "Content-ID", "Content-Transfer-Encoding"} {
+		if value := body.header.Get(key); value != "" {
+			writeHeader(&buf, key, value)
+		}
+	}
+	buf.WriteString("\r\n")
+
+	if err := body.write(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// entity is a MIME entity whose headers are known before its body is written
+type entity struct {
+	header textproto.MIMEHeader
+	write  func(w io.Writer) error
+}
+
+// bodyEntity assembles the MIME tree for the message bodies and attachments
+func bodyEntity(args email.JobArgs) entity {
+	var body entity
+	switch {
+	case args.HTMLBody != "" && args.TextBody != "":
+		// Parts are ordered from least to most preferred per RFC 2046
+		body = multipartEntity("alternative", textEntity("text/plain", args.TextBody), textEntity("text/html", args.HTMLBody))
+	case args.HTMLBody != "":
+		body = textEntity("text/html", args.HTMLBody)
+	default:
+		body = textEntity("text/plain", args.TextBody)
+	}
+
+	var inline, attached []entity
+	for _, a := range args.Attachments {
+		if a.Disposition == email.DispositionInline {
+			inline = append(inline, attachmentEntity(a))
+		} else {
+			attached = append(attached, attachmentEntity(a))
+		}
+	}
+
+	if len(inline) > 0 {
+		body = multipartEntity("related", append([]entity{body}, inline...)...)
+	}
+	if len(attached) > 0 {
+		body = multipartEntity("mixed", append([]entity{body}, attached...)...)
+	}
+
+	return body
+}
+
+// textEntity is a quoted-printable text body
+func textEntity(contentType, body string) entity {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType+"; charset=utf-8")
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+
+	return entity{
+		header: header,
+		write: func(w io.Writer) error {
+			return writeQuotedPrintable(w, body)
+		},
+	}
+}
+
+// attachmentEntity is a base64 encoded file part
+func attachmentEntity(a email.Attachment) entity {
+	disposition := a.Disposition
+	if disposition == "" {
+		disposition = email.DispositionAttachment
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", mime.FormatMediaType(a.ContentType, map[string]string{"name": a.Filename}))
+	header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": a.Filename}))
+	header.Set("Content-Transfer-Encoding", "base64")
+	if a.ContentID != "" {
+		header.Set("Content-ID", "<"+a.ContentID+">")
+	}
+
+	return entity{
+		header: header,
+		write: func(w io.Writer) error {
+			return writeBase64(w, a.Content)
+		},
+	}
+}
+
+// multipartEntity nests parts under a multipart/<subtype> container
+func multipartEntity(subtype string, parts ...entity) entity {
+	// The boundary must appear in the parent's headers before the body is written
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", mime.FormatMediaType("multipart/"+subtype, map[string]string{"boundary": boundary}))
+
+	return entity{
+		header: header,
+		write: func(w io.Writer) error {
+			mw := multipart.NewWriter(w)
+			if err := mw.SetBoundary(boundary); err != nil {
+				return fmt.Errorf("failed to set multipart boundary: %w", err)
+			}
+
+			for _, p := range parts {
+				part, err := mw.CreatePart(p.header)
+				if err != nil {
+					return fmt.Errorf("failed to create %s part: %w", p.header.Get("Content-Type"), err)
+				}
+				if err := p.write(part); err != nil {
+					return err
+				}
+			}
+
+			if err := mw.Close(); err != nil {
+				return fmt.Errorf("failed to close multipart body: %w", err)
+			}
+			return nil
+		},
+	}
+}
+
+// encodeAddresses formats an address list header, encoding non-ASCII display names
+func encodeAddresses(addresses email.AddressList) string {
+	encoded := make([]string, len(addresses))
+	for i, a := range addresses {
+		encoded[i] = (&mail.Address{Name: a.Name, Address: a.Email}).String()
+	}
+	return strings.Join(encoded, ", ")
+}
+
+// writeHeader writes a single header line
+func writeHeader(buf *bytes.Buffer, key, value string) {
+	fmt.Fprintf(buf, "%s: %s\r\n", key, value)
+}
+
+// writeBase64 encodes content in 76 character lines per RFC 2045
+func writeBase64(w io.Writer, content []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > 0 {
+		n := min(76, len(encoded))
+		if _, err := io.WriteString(w, encoded[:n]+"\r\n"); err != nil {
+			return fmt.Errorf("failed to encode attachment: %w", err)
+		}
+		encoded = encoded[n:]
+	}
+	return nil
+}
+
+// writeQuotedPrintable encodes body with CRLF line endings as SMTP requires
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	if _, err := qp.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n"))); err != nil {
+		return fmt.Errorf("failed to encode body: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return fmt.Errorf("failed to encode body: %w", err)
+	}
+	return nil
+}
+
+// newMessageID generates a globally unique Message-ID using the sender's domain
+func newMessageID(from string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate message ID: %w", err)
+	}
+
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 && at < len(from)-1 {
+		domain = from[at+1:]
+	}
+
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain), nil
+}
@@ -0,0 +1,315 @@
+package smtp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// TLSMode controls how the connection to the SMTP server is secured
+type TLSMode string
+
+const (
+	// TLSModeStartTLS upgrades a plaintext connection with STARTTLS (typically port 587)
+	TLSModeStartTLS TLSMode = "starttls"
+	// TLSModeImplicit negotiates TLS before speaking SMTP (typically port 465)
+	TLSModeImplicit TLSMode = "implicit"
+	// TLSModeNone sends mail over an unencrypted connection (local relays only)
+	TLSModeNone TLSMode = "none"
+)
+
+// AuthMechanism selects the SASL mechanism used to authenticate with the server
+type AuthMechanism string
+
+const (
+	AuthPlain AuthMechanism = "plain"
+	AuthLogin AuthMechanism = "login"
+)
+
+// Config holds SMTP connection settings
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	TLSMode  TLSMode
+	Auth     AuthMechanism
+
+	// PoolSize is the maximum number of idle connections kept open for reuse
+	PoolSize int
+
+	// TLSConfig overrides the TLS settings used for STARTTLS and implicit TLS
+	TLSConfig *tls.Config
+
+	// Timeout bounds each SMTP transaction when the context has no deadline
+	Timeout time.Duration
+}
+
+// Client implements email delivery over SMTP. Connections are pooled and
+// shared across River workers so each email doesn't pay for a new handshake.
+type Client struct {
+	config Config
+	idle   chan *conn
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// conn pairs an SMTP session with its underlying network connection so
+// deadlines can be applied per transaction
+type conn struct {
+	client  *smtp.Client
+	netConn net.Conn
+}
+
+// New creates a new SMTP email client
+func New(config Config) (*Client, error) {
+	if config.Host == "" {
+		return nil, errors.New("smtp host is required")
+	}
+
+	switch config.TLSMode {
+	case "":
+		config.TLSMode = TLSModeStartTLS
+	case TLSModeStartTLS, TLSModeImplicit, TLSModeNone:
+	default:
+		return nil, fmt.Errorf("unsupported smtp tls mode: %s", config.TLSMode)
+	}
+
+	switch config.Auth {
+	case "":
+		config.Auth = AuthPlain
+	case AuthPlain, AuthLogin:
+	default:
+		return nil, fmt.Errorf("unsupported smtp auth mechanism: %s", config.Auth)
+	}
+
+	if config.Port == 0 {
+		if config.TLSMode == TLSModeImplicit {
+			config.Port = 465
+		} else {
+			config.Port = 587
+		}
+	}
+	if config.PoolSize <= 0 {
+		config.PoolSize = 5
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	if config.TLSConfig == nil {
+		config.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if config.TLSConfig.ServerName == "" {
+		config.TLSConfig = config.TLSConfig.Clone()
+		config.TLSConfig.ServerName = config.Host
+	}
+
+	return &Client{
+		config: config,
+		idle:   make(chan *conn, config.PoolSize),
+	}, nil
+}
+
+// Send delivers a pre-rendered email via the configured SMTP server
+func (c *Client) Send(ctx context.Context, args email.JobArgs) error {
+	message, err := buildMessage(args)
+	if err != nil {
+		return fmt.Errorf("failed to build message: %w", err)
+	}
+
+	cn, err := c.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.deliver(ctx, cn, args, message); err != nil {
+		// The session state is unknown after a failure, so don't reuse it
+		cn.close()
+		return err
+	}
+
+	c.release(cn)
+	return nil
+}
+
+// Close closes all idle pooled connections
+func (c *Client) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+
+	for {
+		select {
+		case cn := <-c.idle:
+			_ = cn.client.Quit()
+			cn.close()
+		default:
+			return nil
+		}
+	}
+}
+
+// deliver runs a single SMTP mail transaction on an established connection
+func (c *Client) deliver(ctx context.Context, cn *conn, args email.JobArgs, message []byte) error {
+	if err := cn.netConn.SetDeadline(c.deadline(ctx)); err != nil {
+		return fmt.Errorf("failed to set smtp deadline: %w", err)
+	}
+
+	if err := cn.client.Mail(args.From); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+
+	// BCC recipients are only named in the envelope, never in the headers
+	recipients := slices.Concat(args.To.Emails(), args.CC.Emails(), args.BCC.Emails())
+	for _, rcpt := range recipients {
+		if err := cn.client.Rcpt(rcpt); err != nil {
+			if isPermanent(err) {
+				return fmt.Errorf("%w: smtp RCPT TO %s failed: %w", email.ErrRejected, rcpt, err)
+			}
+			return fmt.Errorf("smtp RCPT TO %s failed: %w", rcpt, err)
+		}
+	}
+
+	w, err := cn.client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+
+	if _, err := w.Write(message); err != nil {
+		return fmt.Errorf("failed to write smtp message: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		if isPermanent(err) {
+			return fmt.Errorf("%w: smtp server rejected message: %w", email.ErrRejected, err)
+		}
+		return fmt.Errorf("smtp server rejected message: %w", err)
+	}
+
+	return nil
+}
+
+// isPermanent reports whether the server replied with a 5xx permanent failure
+func isPermanent(err error) bool {
+	var protoErr *textproto.Error
+	return errors.As(err, &protoErr) && protoErr.Code >= 500
+}
+
+// acquire returns a healthy pooled connection or dials a new one
+func (c *Client) acquire(ctx context.Context) (*conn, error) {
+	for {
+		select {
+		case cn := <-c.idle:
+			// Idle connections may have been dropped by the server
+			if err := cn.netConn.SetDeadline(c.deadline(ctx)); err == nil {
+				if err := cn.client.Reset(); err == nil {
+					return cn, nil
+				}
+			}
+			cn.close()
+		default:
+			return c.dial(ctx)
+		}
+	}
+}
+
+// release returns a connection to the pool, or closes it if the pool is full
+func (c *Client) release(cn *conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.closed {
+		select {
+		case c.idle <- cn:
+			return
+		default:
+		}
+	}
+
+	_ = cn.client.Quit()
+	cn.close()
+}
+
+// dial opens and authenticates a new SMTP session
+func (c *Client) dial(ctx context.Context) (*conn, error) {
+	address := net.JoinHostPort(c.config.Host, strconv.Itoa(c.config.Port))
+
+	dialer := &net.Dialer{}
+	netConn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to smtp server %s: %w", address, err)
+	}
+
+	if err := netConn.SetDeadline(c.deadline(ctx)); err != nil {
+		_ = netConn.Close()
+		return nil, fmt.Errorf("failed to set smtp deadline: %w", err)
+	}
+
+	if c.config.TLSMode == TLSModeImplicit {
+		netConn = tls.Client(netConn, c.config.TLSConfig)
+	}
+
+	client, err := smtp.NewClient(netConn, c.config.Host)
+	if err != nil {
+		_ = netConn.Close()
+		return nil, fmt.Errorf("failed to start smtp session: %w", err)
+	}
+
+	cn := &conn{client: client, netConn: netConn}
+
+	if c.config.TLSMode == TLSModeStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			cn.close()
+			return nil, errors.New("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(c.config.TLSConfig); err != nil {
+			cn.close()
+			return nil, fmt.Errorf("smtp STARTTLS failed: %w", err)
+		}
+	}
+
+	if c.config.Username != "" {
+		if err := client.Auth(c.auth()); err != nil {
+			cn.close()
+			return nil, fmt.Errorf("smtp authentication failed: %w", err)
+		}
+	}
+
+	return cn, nil
+}
+
+// auth returns the configured SASL mechanism
+func (c *Client) auth() smtp.Auth {
+	if c.config.Auth == AuthLogin {
+		return &loginAuth{
+			host:     c.config.Host,
+			username: c.config.Username,
+			password: c.config.Password,
+		}
+	}
+	return smtp.PlainAuth("", c.config.Username, c.config.Password, c.config.Host)
+}
+
+// deadline returns the context deadline, falling back to the configured timeout
+func (c *Client) deadline(ctx context.Context) time.Time {
+	if deadline, ok := ctx.Deadline(); ok {
+		return deadline
+	}
+	return time.Now().Add(c.config.Timeout)
+}
+
+// close tears down the connection without attempting a graceful QUIT
+func (cn *conn) close() {
+	_ = cn.client.Close()
+}
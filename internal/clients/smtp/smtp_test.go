@@ -0,0 +1,582 @@
+package smtp_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/internal/clients/smtp"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// stubMessage is a mail transaction captured by the stub server.
+type stubMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+// stubServer is a minimal in-process ESMTP server for exercising the client.
+type stubServer struct {
+	listener  net.Listener
+	tlsConfig *tls.Config
+	implicit  bool
+	username  string
+	password  string
+
+	mu          sync.Mutex
+	messages    []stubMessage
+	connections int
+	authMethod  string
+}
+
+func newStubServer(t *testing.T, tlsConfig *tls.Config, implicit bool) *stubServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &stubServer{
+		listener:  listener,
+		tlsConfig: tlsConfig,
+		implicit:  implicit,
+		username:  "mailer",
+		password:  "secret",
+	}
+
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+
+	return s
+}
+
+func (s *stubServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *stubServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.connections++
+		s.mu.Unlock()
+
+		if s.implicit {
+			conn = tls.Server(conn, s.tlsConfig)
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *stubServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+	readLine := func() (string, bool) {
+		line, err := r.ReadString('\n')
+		return strings.TrimRight(line, "\r\n"), err == nil
+	}
+
+	reply("220 stub ESMTP")
+
+	secure := s.implicit
+	var current stubMessage
+
+	for {
+		line, ok := readLine()
+		if !ok {
+			return
+		}
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch verb {
+		case "EHLO", "HELO":
+			reply("250-stub")
+			if s.tlsConfig != nil && !secure {
+				reply("250-STARTTLS")
+			}
+			reply("250 AUTH PLAIN LOGIN")
+		case "STARTTLS":
+			reply("220 ready")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			conn = tlsConn
+			r = bufio.NewReader(conn)
+			secure = true
+		case "AUTH":
+			s.handleAuth(line, reply, readLine)
+		case "MAIL":
+			current = stubMessage{from: extractPath(line)}
+			reply("250 ok")
+		case "RCPT":
+			rcpt := extractPath(line)
+			if strings.HasPrefix(rcpt, "unknown@") {
+				reply("550 no such user")
+				continue
+			}
+			current.to = append(current.to, rcpt)
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, ok := readLine()
+				if !ok {
+					return
+				}
+				if l == "." {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, ".") + "\r\n")
+			}
+			current.data = data.String()
+			s.mu.Lock()
+			s.messages = append(s.messages, current)
+			s.mu.Unlock()
+			reply("250 queued")
+		case "RSET", "NOOP":
+			reply("250 ok")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 unrecognized")
+		}
+	}
+}
+
+func (s *stubServer) handleAuth(line string, reply func(string), readLine func() (string, bool)) {
+	fields := strings.Fields(line)
+	mechanism := strings.ToUpper(fields[1])
+
+	var username, password string
+	switch mechanism {
+	case "PLAIN":
+		decoded, _ := base64.StdEncoding.DecodeString(fields[2])
+		parts := strings.Split(string(decoded), "\x00")
+		username, password = parts[1], parts[2]
+	case "LOGIN":
+		reply("334 " + base64.StdEncoding.EncodeToString([]byte("Username:")))
+		l, _ := readLine()
+		decoded, _ := base64.StdEncoding.DecodeString(l)
+		username = string(decoded)
+		reply("334 " + base64.StdEncoding.EncodeToString([]byte("Password:")))
+		l, _ = readLine()
+		decoded, _ = base64.StdEncoding.DecodeString(l)
+		password = string(decoded)
+	}
+
+	if username != s.username || password != s.password {
+		reply("535 authentication failed")
+		return
+	}
+
+	s.mu.Lock()
+	s.authMethod = mechanism
+	s.mu.Unlock()
+	reply("235 authenticated")
+}
+
+func (s *stubServer) snapshot() ([]stubMessage, int, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]stubMessage(nil), s.messages...), s.connections, s.authMethod
+}
+
+func extractPath(line string) string {
+	start := strings.Index(line, "<")
+	end := strings.Index(line, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	return line[start+1 : end]
+}
+
+// selfSignedTLS returns matching server and client TLS configs for 127.0.0.1.
+func selfSignedTLS(t *testing.T) (*tls.Config, *tls.Config) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	serverConfig := &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	}
+	clientConfig := &tls.Config{RootCAs: pool}
+
+	return serverConfig, clientConfig
+}
+
+func testArgs() email.JobArgs {
+	return email.JobArgs{
+		To:       email.AddressList{{Email: "user@example.com"}},
+		From:     "no-reply@example.com",
+		FromName: "Example",
+		Subject:  "Hello, Wörld",
+		HTMLBody: "<p>Hello</p>",
+		TextBody: "Hello",
+	}
+}
+
+func TestClient_StartTLSWithPlainAuth(t *testing.T) {
+	t.Parallel()
+
+	serverTLS, clientTLS := selfSignedTLS(t)
+	server := newStubServer(t, serverTLS, false)
+
+	client, err := smtp.New(smtp.Config{
+		Host:      "127.0.0.1",
+		Port:      server.port(),
+		Username:  "mailer",
+		Password:  "secret",
+		TLSMode:   smtp.TLSModeStartTLS,
+		TLSConfig: clientTLS,
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Send(context.Background(), testArgs()))
+
+	messages, _, authMethod := server.snapshot()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "PLAIN", authMethod)
+	assert.Equal(t, "no-reply@example.com", messages[0].from)
+	assert.Equal(t, []string{"user@example.com"}, messages[0].to)
+}
+
+func TestClient_ImplicitTLSWithLoginAuth(t *testing.T) {
+	t.Parallel()
+
+	serverTLS, clientTLS := selfSignedTLS(t)
+	server := newStubServer(t, serverTLS, true)
+
+	client, err := smtp.New(smtp.Config{
+		Host:      "127.0.0.1",
+		Port:      server.port(),
+		Username:  "mailer",
+		Password:  "secret",
+		TLSMode:   smtp.TLSModeImplicit,
+		Auth:      smtp.AuthLogin,
+		TLSConfig: clientTLS,
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Send(context.Background(), testArgs()))
+
+	messages, _, authMethod := server.snapshot()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "LOGIN", authMethod)
+}
+
+func TestClient_AuthFailure(t *testing.T) {
+	t.Parallel()
+
+	server := newStubServer(t, nil, false)
+
+	client, err := smtp.New(smtp.Config{
+		Host:     "127.0.0.1",
+		Port:     server.port(),
+		Username: "mailer",
+		Password: "wrong",
+		TLSMode:  smtp.TLSModeNone,
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	err = client.Send(context.Background(), testArgs())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "authentication failed")
+}
+
+func TestClient_RejectedRecipient(t *testing.T) {
+	t.Parallel()
+
+	server := newStubServer(t, nil, false)
+
+	client, err := smtp.New(smtp.Config{
+		Host:    "127.0.0.1",
+		Port:    server.port(),
+		TLSMode: smtp.TLSModeNone,
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	args := testArgs()
+	args.To = email.AddressList{{Email: "unknown@example.com"}}
+
+	err = client.Send(context.Background(), args)
+	require.ErrorIs(t, err, email.ErrRejected)
+	assert.Contains(t, err.Error(), "no such user")
+}
+
+func TestClient_StartTLSUnsupported(t *testing.T) {
+	t.Parallel()
+
+	server := newStubServer(t, nil, false)
+
+	client, err := smtp.New(smtp.Config{
+		Host:    "127.0.0.1",
+		Port:    server.port(),
+		TLSMode: smtp.TLSModeStartTLS,
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	err = client.Send(context.Background(), testArgs())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "STARTTLS")
+}
+
+func TestClient_ReusesPooledConnections(t *testing.T) {
+	t.Parallel()
+
+	server := newStubServer(t, nil, false)
+
+	client, err := smtp.New(smtp.Config{
+		Host:    "127.0.0.1",
+		Port:    server.port(),
+		TLSMode: smtp.TLSModeNone,
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	for i := range 3 {
+		args := testArgs()
+		args.To = email.AddressList{{Email: "user" + strconv.Itoa(i) + "@example.com"}}
+		require.NoError(t, client.Send(context.Background(), args))
+	}
+
+	messages, connections, _ := server.snapshot()
+	assert.Len(t, messages, 3)
+	assert.Equal(t, 1, connections)
+}
+
+func TestClient_MultipartAlternativeBody(t *testing.T) {
+	t.Parallel()
+
+	server := newStubServer(t, nil, false)
+
+	client, err := smtp.New(smtp.Config{
+		Host:    "127.0.0.1",
+		Port:    server.port(),
+		TLSMode: smtp.TLSModeNone,
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Send(context.Background(), testArgs()))
+
+	messages, _, _ := server.snapshot()
+	require.Len(t, messages, 1)
+
+	msg, err := mail.ReadMessage(strings.NewReader(messages[0].data))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, Wörld", subject)
+	assert.Equal(t, `"Example" <no-reply@example.com>`, msg.Header.Get("From"))
+	assert.NotEmpty(t, msg.Header.Get("Message-ID"))
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	var parts []string
+	var bodies []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(part)
+		require.NoError(t, err)
+
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts = append(parts, partType)
+		bodies = append(bodies, string(body))
+	}
+
+	assert.Equal(t, []string{"text/plain", "text/html"}, parts)
+	assert.Equal(t, []string{"Hello", "<p>Hello</p>"}, bodies)
+}
+
+func TestClient_MultipleRecipients(t *testing.T) {
+	t.Parallel()
+
+	server := newStubServer(t, nil, false)
+
+	client, err := smtp.New(smtp.Config{
+		Host:    "127.0.0.1",
+		Port:    server.port(),
+		TLSMode: smtp.TLSModeNone,
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	args := testArgs()
+	args.To = email.AddressList{{Name: "Jane Doe", Email: "jane@example.com"}, {Email: "bob@example.com"}}
+	args.CC = email.AddressList{{Name: "Support", Email: "support@example.com"}}
+	args.BCC = email.AddressList{{Email: "audit@example.com"}}
+	args.ReplyTo = &email.Address{Name: "Ticket 42", Email: "tickets@example.com"}
+	require.NoError(t, client.Send(context.Background(), args))
+
+	messages, _, _ := server.snapshot()
+	require.Len(t, messages, 1)
+	assert.Equal(t, []string{"jane@example.com", "bob@example.com", "support@example.com", "audit@example.com"}, messages[0].to)
+
+	msg, err := mail.ReadMessage(strings.NewReader(messages[0].data))
+	require.NoError(t, err)
+
+	to, err := msg.Header.AddressList("To")
+	require.NoError(t, err)
+	assert.Equal(t, []*mail.Address{{Name: "Jane Doe", Address: "jane@example.com"}, {Address: "bob@example.com"}}, to)
+	assert.Equal(t, `"Support" <support@example.com>`, msg.Header.Get("Cc"))
+	assert.Equal(t, `"Ticket 42" <tickets@example.com>`, msg.Header.Get("Reply-To"))
+
+	// BCC recipients must not leak into the headers
+	assert.Empty(t, msg.Header.Get("Bcc"))
+	assert.NotContains(t, messages[0].data, "audit@example.com")
+}
+
+func TestClient_Attachments(t *testing.T) {
+	t.Parallel()
+
+	server := newStubServer(t, nil, false)
+
+	client, err := smtp.New(smtp.Config{
+		Host:    "127.0.0.1",
+		Port:    server.port(),
+		TLSMode: smtp.TLSModeNone,
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	args := testArgs()
+	args.Attachments = []email.Attachment{
+		{Filename: "logo.png", ContentType: "image/png", Content: []byte("png-bytes"), Disposition: email.DispositionInline, ContentID: "logo"},
+		{Filename: "invoice.pdf", ContentType: "application/pdf", Content: bytes.Repeat([]byte("%PDF"), 100), Disposition: email.DispositionAttachment},
+	}
+	require.NoError(t, client.Send(context.Background(), args))
+
+	messages, _, _ := server.snapshot()
+	require.Len(t, messages, 1)
+
+	msg, err := mail.ReadMessage(strings.NewReader(messages[0].data))
+	require.NoError(t, err)
+
+	// multipart/mixed wraps multipart/related, which wraps the bodies and inline image
+	mixed := readParts(t, msg.Header.Get("Content-Type"), msg.Body)
+	require.Len(t, mixed, 2)
+	assert.Equal(t, "multipart/related", mixed[0].mediaType)
+	assert.Equal(t, "application/pdf", mixed[1].mediaType)
+	assert.Equal(t, `attachment; filename=invoice.pdf`, mixed[1].header.Get("Content-Disposition"))
+	assert.Equal(t, args.Attachments[1].Content, mixed[1].decoded(t))
+
+	related := readParts(t, mixed[0].header.Get("Content-Type"), bytes.NewReader(mixed[0].body))
+	require.Len(t, related, 2)
+	assert.Equal(t, "multipart/alternative", related[0].mediaType)
+	assert.Equal(t, "image/png", related[1].mediaType)
+	assert.Equal(t, "<logo>", related[1].header.Get("Content-ID"))
+	assert.Equal(t, []byte("png-bytes"), related[1].decoded(t))
+}
+
+type mimePart struct {
+	mediaType string
+	header    textproto.MIMEHeader
+	body      []byte
+}
+
+// decoded returns the base64-decoded body of an attachment part
+func (p mimePart) decoded(t *testing.T) []byte {
+	t.Helper()
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(p.body), "\r\n", ""))
+	require.NoError(t, err)
+	return data
+}
+
+// readParts splits a multipart body into its immediate children
+func readParts(t *testing.T, contentType string, body io.Reader) []mimePart {
+	t.Helper()
+
+	_, params, err := mime.ParseMediaType(contentType)
+	require.NoError(t, err)
+
+	reader := multipart.NewReader(body, params["boundary"])
+	var parts []mimePart
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		data, err := io.ReadAll(part)
+		require.NoError(t, err)
+
+		mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts = append(parts, mimePart{mediaType: mediaType, header: part.Header, body: data})
+	}
+	return parts
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	_, err := smtp.New(smtp.Config{})
+	require.Error(t, err)
+
+	_, err = smtp.New(smtp.Config{Host: "localhost", TLSMode: "ssl"})
+	require.Error(t, err)
+
+	_, err = smtp.New(smtp.Config{Host: "localhost", Auth: "cram-md5"})
+	require.Error(t, err)
+}
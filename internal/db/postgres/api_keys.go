@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/travisbale/mailman/internal/db/postgres/internal/sqlc"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// APIKeysDB handles database operations for API keys
+type APIKeysDB struct {
+	db *DB
+}
+
+// NewAPIKeysDB creates a new API keys database adapter
+func NewAPIKeysDB(db *DB) *APIKeysDB {
+	return &APIKeysDB{db: db}
+}
+
+// GetAPIKey retrieves a key by ID, whether or not it has been revoked
+func (r *APIKeysDB) GetAPIKey(ctx context.Context, id string) (*email.APIKey, error) {
+	var key *email.APIKey
+
+	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		dbKey, err := q.GetAPIKey(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: %s", email.ErrAPIKeyNotFound, id)
+			}
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		key = convertAPIKeyToDomain(dbKey)
+		return nil
+	})
+
+	return key, err
+}
+
+// GetAPIKeyByClientIdentity retrieves the unrevoked key bound to a client
+// certificate identity
+func (r *APIKeysDB) GetAPIKeyByClientIdentity(ctx context.Context, identity string) (*email.APIKey, error) {
+	var key *email.APIKey
+
+	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		dbKey, err := q.GetAPIKeyByClientIdentity(ctx, &identity)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: client identity %s", email.ErrAPIKeyNotFound, identity)
+			}
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		key = convertAPIKeyToDomain(dbKey)
+		return nil
+	})
+
+	return key, err
+}
+
+// ListAPIKeys retrieves every key, including revoked ones
+func (r *APIKeysDB) ListAPIKeys(ctx context.Context) ([]*email.APIKey, error) {
+	var keys []*email.APIKey
+
+	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		dbKeys, err := q.ListAPIKeys(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list API keys: %w", err)
+		}
+
+		keys = make([]*email.APIKey, len(dbKeys))
+		for i := range dbKeys {
+			keys[i] = convertAPIKeyToDomain(dbKeys[i])
+		}
+
+		return nil
+	})
+
+	return keys, err
+}
+
+// CreateAPIKey stores a new key
+func (r *APIKeysDB) CreateAPIKey(ctx context.Context, key *email.APIKey) (*email.APIKey, error) {
+	var created *email.APIKey
+
+	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		dbKey, err := q.CreateAPIKey(ctx, sqlc.CreateAPIKeyParams{
+			ID:             key.ID,
+			Name:           key.Name,
+			SecretHash:     key.SecretHash,
+			Scopes:         key.Scopes,
+			TenantID:       key.TenantID,
+			ClientIdentity: key.ClientIdentity,
+		})
+		if err != nil {
+			if isForeignKeyViolation(err) {
+				return fmt.Errorf("%w: %s", email.ErrTenantNotFound, key.TenantID)
+			}
+			if isUniqueViolation(err) {
+				return fmt.Errorf("%w: %s", email.ErrClientIdentityInUse, *key.ClientIdentity)
+			}
+			return fmt.Errorf("failed to create API key: %w", err)
+		}
+
+		created = convertAPIKeyToDomain(dbKey)
+		return nil
+	})
+
+	return created, err
+}
+
+// RevokeAPIKey marks a key as revoked. Revoking a key that is already revoked is
+// reported as not found.
+func (r *APIKeysDB) RevokeAPIKey(ctx context.Context, id string) error {
+	return r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		rows, err := q.RevokeAPIKey(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to revoke API key: %w", err)
+		}
+
+		if rows == 0 {
+			return fmt.Errorf("%w: %s", email.ErrAPIKeyNotFound, id)
+		}
+
+		return nil
+	})
+}
+
+// convertAPIKeyToDomain converts a sqlc ApiKey to a domain APIKey
+func convertAPIKeyToDomain(dbKey sqlc.ApiKey) *email.APIKey {
+	return &email.APIKey{
+		ID:             dbKey.ID,
+		TenantID:       dbKey.TenantID,
+		Name:           dbKey.Name,
+		SecretHash:     dbKey.SecretHash,
+		Scopes:         dbKey.Scopes,
+		CreatedAt:      dbKey.CreatedAt,
+		RevokedAt:      dbKey.RevokedAt,
+		ClientIdentity: dbKey.ClientIdentity,
+	}
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/travisbale/mailman/internal/db/postgres/internal/sqlc"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// DeliveryEventsDB handles database operations for provider delivery events
+type DeliveryEventsDB struct {
+	db *DB
+}
+
+// NewDeliveryEventsDB creates a new delivery events database adapter
+func NewDeliveryEventsDB(db *DB) *DeliveryEventsDB {
+	return &DeliveryEventsDB{db: db}
+}
+
+// RecordEvents stores a batch of events in a single transaction, skipping any
+// that were already recorded. Each newly stored event also adds the suppression
+// suppressionFor returns for it, for the tenant that sent the email.
+func (r *DeliveryEventsDB) RecordEvents(ctx context.Context, events []*email.DeliveryEvent, suppressionFor func(*email.DeliveryEvent) *email.Suppression) error {
+	return r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		for _, event := range events {
+			inserted, err := q.InsertDeliveryEvent(ctx, sqlc.InsertDeliveryEventParams{
+				Provider:        event.Provider,
+				ProviderEventID: event.ProviderEventID,
+				MessageID:       event.MessageID,
+				EventType:       event.Type,
+				Recipient:       event.Recipient,
+				Reason:          event.Reason,
+				OccurredAt:      event.OccurredAt,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to insert delivery event: %w", err)
+			}
+			if inserted == 0 {
+				continue
+			}
+
+			suppression := suppressionFor(event)
+			if suppression == nil {
+				continue
+			}
+			_, err = q.UpsertSuppression(ctx, sqlc.UpsertSuppressionParams{
+				TenantID:  email.TenantFromContext(email.WithTenant(ctx, event.Tenant)),
+				Address:   suppression.Address,
+				Reason:    suppression.Reason,
+				Source:    suppression.Source,
+				ExpiresAt: suppression.ExpiresAt,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to suppress %s: %w", suppression.Address, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// ListEvents retrieves the events recorded for an email, oldest first
+func (r *DeliveryEventsDB) ListEvents(ctx context.Context, messageID string) ([]*email.DeliveryEvent, error) {
+	var events []*email.DeliveryEvent
+
+	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		dbEvents, err := q.ListDeliveryEvents(ctx, messageID)
+		if err != nil {
+			return fmt.Errorf("failed to list delivery events: %w", err)
+		}
+
+		events = make([]*email.DeliveryEvent, len(dbEvents))
+		for i := range dbEvents {
+			events[i] = convertDeliveryEventToDomain(dbEvents[i])
+		}
+
+		return nil
+	})
+
+	return events, err
+}
+
+// convertDeliveryEventToDomain converts a sqlc DeliveryEvent to a domain DeliveryEvent
+func convertDeliveryEventToDomain(dbEvent sqlc.DeliveryEvent) *email.DeliveryEvent {
+	return &email.DeliveryEvent{
+		Provider:        dbEvent.Provider,
+		ProviderEventID: dbEvent.ProviderEventID,
+		MessageID:       dbEvent.MessageID,
+		Type:            dbEvent.EventType,
+		Recipient:       dbEvent.Recipient,
+		Reason:          dbEvent.Reason,
+		OccurredAt:      dbEvent.OccurredAt,
+	}
+}
@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Postgres error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+func isUniqueViolation(err error) bool {
+	return hasErrorCode(err, uniqueViolation)
+}
+
+func isForeignKeyViolation(err error) bool {
+	return hasErrorCode(err, foreignKeyViolation)
+}
+
+func hasErrorCode(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
+
+// violatedConstraint returns the name of the constraint a Postgres error reports
+func violatedConstraint(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.ConstraintName
+	}
+	return ""
+}
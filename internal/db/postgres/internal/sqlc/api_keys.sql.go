@@ -0,0 +1,141 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: api_keys.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const createAPIKey = `-- name: CreateAPIKey :one
+INSERT INTO api_keys (id, name, secret_hash, scopes, tenant_id, client_identity)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, name, secret_hash, scopes, created_at, revoked_at, tenant_id, client_identity
+`
+
+type CreateAPIKeyParams struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	SecretHash     []byte   `json:"secret_hash"`
+	Scopes         []string `json:"scopes"`
+	TenantID       string   `json:"tenant_id"`
+	ClientIdentity *string  `json:"client_identity"`
+}
+
+func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, createAPIKey,
+		arg.ID,
+		arg.Name,
+		arg.SecretHash,
+		arg.Scopes,
+		arg.TenantID,
+		arg.ClientIdentity,
+	)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.SecretHash,
+		&i.Scopes,
+		&i.CreatedAt,
+		&i.RevokedAt,
+		&i.TenantID,
+		&i.ClientIdentity,
+	)
+	return i, err
+}
+
+const getAPIKey = `-- name: GetAPIKey :one
+SELECT id, name, secret_hash, scopes, created_at, revoked_at, tenant_id, client_identity
+FROM api_keys
+WHERE id = $1
+`
+
+func (q *Queries) GetAPIKey(ctx context.Context, id string) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, getAPIKey, id)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.SecretHash,
+		&i.Scopes,
+		&i.CreatedAt,
+		&i.RevokedAt,
+		&i.TenantID,
+		&i.ClientIdentity,
+	)
+	return i, err
+}
+
+const getAPIKeyByClientIdentity = `-- name: GetAPIKeyByClientIdentity :one
+SELECT id, name, secret_hash, scopes, created_at, revoked_at, tenant_id, client_identity
+FROM api_keys
+WHERE client_identity = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) GetAPIKeyByClientIdentity(ctx context.Context, clientIdentity *string) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, getAPIKeyByClientIdentity, clientIdentity)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.SecretHash,
+		&i.Scopes,
+		&i.CreatedAt,
+		&i.RevokedAt,
+		&i.TenantID,
+		&i.ClientIdentity,
+	)
+	return i, err
+}
+
+const listAPIKeys = `-- name: ListAPIKeys :many
+SELECT id, name, secret_hash, scopes, created_at, revoked_at, tenant_id, client_identity
+FROM api_keys
+ORDER BY tenant_id, created_at, id
+`
+
+func (q *Queries) ListAPIKeys(ctx context.Context) ([]ApiKey, error) {
+	rows, err := q.db.Query(ctx, listAPIKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ApiKey{}
+	for rows.Next() {
+		var i ApiKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.SecretHash,
+			&i.Scopes,
+			&i.CreatedAt,
+			&i.RevokedAt,
+			&i.TenantID,
+			&i.ClientIdentity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeAPIKey = `-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked_at = now()
+WHERE id = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeAPIKey(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeAPIKey, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
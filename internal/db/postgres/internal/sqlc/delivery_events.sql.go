@@ -0,0 +1,80 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: delivery_events.sql
+
+package sqlc
+
+import (
+	"context"
+	"time"
+)
+
+const insertDeliveryEvent = `-- name: InsertDeliveryEvent :execrows
+INSERT INTO delivery_events (provider, provider_event_id, message_id, event_type, recipient, reason, occurred_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (provider, provider_event_id) DO NOTHING
+`
+
+type InsertDeliveryEventParams struct {
+	Provider        string    `json:"provider"`
+	ProviderEventID string    `json:"provider_event_id"`
+	MessageID       string    `json:"message_id"`
+	EventType       string    `json:"event_type"`
+	Recipient       string    `json:"recipient"`
+	Reason          string    `json:"reason"`
+	OccurredAt      time.Time `json:"occurred_at"`
+}
+
+func (q *Queries) InsertDeliveryEvent(ctx context.Context, arg InsertDeliveryEventParams) (int64, error) {
+	result, err := q.db.Exec(ctx, insertDeliveryEvent,
+		arg.Provider,
+		arg.ProviderEventID,
+		arg.MessageID,
+		arg.EventType,
+		arg.Recipient,
+		arg.Reason,
+		arg.OccurredAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const listDeliveryEvents = `-- name: ListDeliveryEvents :many
+SELECT id, provider, provider_event_id, message_id, event_type, recipient, reason, occurred_at, received_at
+FROM delivery_events
+WHERE message_id = $1
+ORDER BY occurred_at, id
+`
+
+func (q *Queries) ListDeliveryEvents(ctx context.Context, messageID string) ([]DeliveryEvent, error) {
+	rows, err := q.db.Query(ctx, listDeliveryEvents, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []DeliveryEvent{}
+	for rows.Next() {
+		var i DeliveryEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.Provider,
+			&i.ProviderEventID,
+			&i.MessageID,
+			&i.EventType,
+			&i.Recipient,
+			&i.Reason,
+			&i.OccurredAt,
+			&i.ReceivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
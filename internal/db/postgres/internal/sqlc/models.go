@@ -8,14 +8,99 @@ import (
 	"time"
 )
 
+type ApiKey struct {
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	SecretHash     []byte     `json:"secret_hash"`
+	Scopes         []string   `json:"scopes"`
+	CreatedAt      time.Time  `json:"created_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	TenantID       string     `json:"tenant_id"`
+	ClientIdentity *string    `json:"client_identity"`
+}
+
+type DeliveryEvent struct {
+	ID              int64     `json:"id"`
+	Provider        string    `json:"provider"`
+	ProviderEventID string    `json:"provider_event_id"`
+	MessageID       string    `json:"message_id"`
+	EventType       string    `json:"event_type"`
+	Recipient       string    `json:"recipient"`
+	Reason          string    `json:"reason"`
+	OccurredAt      time.Time `json:"occurred_at"`
+	ReceivedAt      time.Time `json:"received_at"`
+}
+
 type EmailTemplate struct {
-	Name             string    `json:"name"`
-	Subject          string    `json:"subject"`
-	HtmlBody         string    `json:"html_body"`
-	TextBody         *string   `json:"text_body"`
-	BaseTemplateName *string   `json:"base_template_name"`
-	Variables        []string  `json:"variables"`
-	Version          int32     `json:"version"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	Name               string    `json:"name"`
+	Subject            string    `json:"subject"`
+	HtmlBody           string    `json:"html_body"`
+	TextBody           *string   `json:"text_body"`
+	BaseTemplateName   *string   `json:"base_template_name"`
+	Variables          []string  `json:"variables"`
+	Version            int32     `json:"version"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+	DefaultSender      *string   `json:"default_sender"`
+	SuppressionPolicy  string    `json:"suppression_policy"`
+	Locale             string    `json:"locale"`
+	BaseTemplateLocale string    `json:"base_template_locale"`
+	TenantID           string    `json:"tenant_id"`
+}
+
+type IdempotencyKey struct {
+	TenantID  string    `json:"tenant_id"`
+	Key       string    `json:"key"`
+	JobID     *int64    `json:"job_id"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type RateLimitBucket struct {
+	Key       string    `json:"key"`
+	Tokens    float64   `json:"tokens"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Sender struct {
+	Address   string    `json:"address"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	TenantID  string    `json:"tenant_id"`
+}
+
+type Suppression struct {
+	Address   string     `json:"address"`
+	Reason    string     `json:"reason"`
+	Source    string     `json:"source"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	TenantID  string     `json:"tenant_id"`
+}
+
+type TemplateVersion struct {
+	TemplateName        string    `json:"template_name"`
+	Version             int32     `json:"version"`
+	Subject             string    `json:"subject"`
+	HtmlBody            string    `json:"html_body"`
+	TextBody            *string   `json:"text_body"`
+	BaseTemplateName    *string   `json:"base_template_name"`
+	Variables           []string  `json:"variables"`
+	CreatedAt           time.Time `json:"created_at"`
+	DefaultSender       *string   `json:"default_sender"`
+	SuppressionPolicy   string    `json:"suppression_policy"`
+	Locale              string    `json:"locale"`
+	TenantID            string    `json:"tenant_id"`
+	BaseTemplateVersion *int32    `json:"base_template_version"`
+}
+
+type Tenant struct {
+	ID                  string    `json:"id"`
+	Name                string    `json:"name"`
+	FromAddress         string    `json:"from_address"`
+	FromName            string    `json:"from_name"`
+	Provider            string    `json:"provider"`
+	CreatedAt           time.Time `json:"created_at"`
+	ProviderCredentials []byte    `json:"provider_credentials"`
+	ProviderFallback    bool      `json:"provider_fallback"`
 }
@@ -0,0 +1,45 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: rate_limits.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const refillRateLimitBucket = `-- name: RefillRateLimitBucket :one
+INSERT INTO rate_limit_buckets AS b (key, tokens, updated_at)
+VALUES ($1, $2::float8, now())
+ON CONFLICT (key) DO UPDATE
+SET tokens = LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * $3::float8),
+    updated_at = now()
+RETURNING tokens
+`
+
+type RefillRateLimitBucketParams struct {
+	Key      string  `json:"key"`
+	Capacity float64 `json:"capacity"`
+	Rate     float64 `json:"rate"`
+}
+
+// Creates the bucket full, or tops it up for the time since it was last refilled,
+// and locks it until the transaction ends
+func (q *Queries) RefillRateLimitBucket(ctx context.Context, arg RefillRateLimitBucketParams) (float64, error) {
+	row := q.db.QueryRow(ctx, refillRateLimitBucket, arg.Key, arg.Capacity, arg.Rate)
+	var tokens float64
+	err := row.Scan(&tokens)
+	return tokens, err
+}
+
+const takeRateLimitTokens = `-- name: TakeRateLimitTokens :exec
+UPDATE rate_limit_buckets
+SET tokens = tokens - 1
+WHERE key = ANY($1::text[])
+`
+
+func (q *Queries) TakeRateLimitTokens(ctx context.Context, keys []string) error {
+	_, err := q.db.Exec(ctx, takeRateLimitTokens, keys)
+	return err
+}
@@ -0,0 +1,107 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: senders.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const createSender = `-- name: CreateSender :one
+INSERT INTO senders (tenant_id, address, name)
+VALUES ($1, $2, $3)
+RETURNING address, name, created_at, tenant_id
+`
+
+type CreateSenderParams struct {
+	TenantID string `json:"tenant_id"`
+	Address  string `json:"address"`
+	Name     string `json:"name"`
+}
+
+func (q *Queries) CreateSender(ctx context.Context, arg CreateSenderParams) (Sender, error) {
+	row := q.db.QueryRow(ctx, createSender, arg.TenantID, arg.Address, arg.Name)
+	var i Sender
+	err := row.Scan(
+		&i.Address,
+		&i.Name,
+		&i.CreatedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const deleteSender = `-- name: DeleteSender :execrows
+DELETE FROM senders
+WHERE tenant_id = $1 AND address = $2
+`
+
+type DeleteSenderParams struct {
+	TenantID string `json:"tenant_id"`
+	Address  string `json:"address"`
+}
+
+func (q *Queries) DeleteSender(ctx context.Context, arg DeleteSenderParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSender, arg.TenantID, arg.Address)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getSender = `-- name: GetSender :one
+SELECT address, name, created_at, tenant_id
+FROM senders
+WHERE tenant_id = $1 AND address = $2
+`
+
+type GetSenderParams struct {
+	TenantID string `json:"tenant_id"`
+	Address  string `json:"address"`
+}
+
+func (q *Queries) GetSender(ctx context.Context, arg GetSenderParams) (Sender, error) {
+	row := q.db.QueryRow(ctx, getSender, arg.TenantID, arg.Address)
+	var i Sender
+	err := row.Scan(
+		&i.Address,
+		&i.Name,
+		&i.CreatedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const listSenders = `-- name: ListSenders :many
+SELECT address, name, created_at, tenant_id
+FROM senders
+WHERE tenant_id = $1
+ORDER BY address
+`
+
+func (q *Queries) ListSenders(ctx context.Context, tenantID string) ([]Sender, error) {
+	rows, err := q.db.Query(ctx, listSenders, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Sender{}
+	for rows.Next() {
+		var i Sender
+		if err := rows.Scan(
+			&i.Address,
+			&i.Name,
+			&i.CreatedAt,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,140 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: suppressions.sql
+
+package sqlc
+
+import (
+	"context"
+	"time"
+)
+
+const deleteSuppression = `-- name: DeleteSuppression :execrows
+DELETE FROM suppressions
+WHERE tenant_id = $1 AND address = $2
+`
+
+type DeleteSuppressionParams struct {
+	TenantID string `json:"tenant_id"`
+	Address  string `json:"address"`
+}
+
+func (q *Queries) DeleteSuppression(ctx context.Context, arg DeleteSuppressionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSuppression, arg.TenantID, arg.Address)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const listActiveSuppressions = `-- name: ListActiveSuppressions :many
+SELECT address, reason, source, expires_at, created_at, tenant_id
+FROM suppressions
+WHERE tenant_id = $1
+  AND address = ANY($2::text[])
+  AND (expires_at IS NULL OR expires_at > now())
+ORDER BY address
+`
+
+type ListActiveSuppressionsParams struct {
+	TenantID  string   `json:"tenant_id"`
+	Addresses []string `json:"addresses"`
+}
+
+func (q *Queries) ListActiveSuppressions(ctx context.Context, arg ListActiveSuppressionsParams) ([]Suppression, error) {
+	rows, err := q.db.Query(ctx, listActiveSuppressions, arg.TenantID, arg.Addresses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Suppression{}
+	for rows.Next() {
+		var i Suppression
+		if err := rows.Scan(
+			&i.Address,
+			&i.Reason,
+			&i.Source,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSuppressions = `-- name: ListSuppressions :many
+SELECT address, reason, source, expires_at, created_at, tenant_id
+FROM suppressions
+WHERE tenant_id = $1
+ORDER BY address
+`
+
+func (q *Queries) ListSuppressions(ctx context.Context, tenantID string) ([]Suppression, error) {
+	rows, err := q.db.Query(ctx, listSuppressions, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Suppression{}
+	for rows.Next() {
+		var i Suppression
+		if err := rows.Scan(
+			&i.Address,
+			&i.Reason,
+			&i.Source,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertSuppression = `-- name: UpsertSuppression :one
+INSERT INTO suppressions (tenant_id, address, reason, source, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (tenant_id, address) DO UPDATE
+SET reason = EXCLUDED.reason, source = EXCLUDED.source, expires_at = EXCLUDED.expires_at, created_at = now()
+RETURNING address, reason, source, expires_at, created_at, tenant_id
+`
+
+type UpsertSuppressionParams struct {
+	TenantID  string     `json:"tenant_id"`
+	Address   string     `json:"address"`
+	Reason    string     `json:"reason"`
+	Source    string     `json:"source"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (q *Queries) UpsertSuppression(ctx context.Context, arg UpsertSuppressionParams) (Suppression, error) {
+	row := q.db.QueryRow(ctx, upsertSuppression,
+		arg.TenantID,
+		arg.Address,
+		arg.Reason,
+		arg.Source,
+		arg.ExpiresAt,
+	)
+	var i Suppression
+	err := row.Scan(
+		&i.Address,
+		&i.Reason,
+		&i.Source,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
@@ -7,33 +7,46 @@ package sqlc
 
 import (
 	"context"
+	"time"
 )
 
 const createTemplate = `-- name: CreateTemplate :one
-INSERT INTO email_templates (name, subject, html_body, text_body, base_template_name, variables, version)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING name, subject, html_body, text_body, base_template_name, variables, version, created_at, updated_at
+INSERT INTO email_templates (tenant_id, name, locale, subject, html_body, text_body, base_template_name, variables, default_sender, suppression_policy, version)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, (
+    SELECT COALESCE(max(v.version), 0) + 1
+    FROM template_versions v
+    WHERE v.tenant_id = $1 AND v.template_name = $2 AND v.locale = $3
+))
+RETURNING name, subject, html_body, text_body, base_template_name, variables, version, created_at, updated_at, default_sender, suppression_policy, locale, base_template_locale, tenant_id
 `
 
 type CreateTemplateParams struct {
-	Name             string   `json:"name"`
-	Subject          string   `json:"subject"`
-	HtmlBody         string   `json:"html_body"`
-	TextBody         *string  `json:"text_body"`
-	BaseTemplateName *string  `json:"base_template_name"`
-	Variables        []string `json:"variables"`
-	Version          int32    `json:"version"`
+	TenantID          string   `json:"tenant_id"`
+	Name              string   `json:"name"`
+	Locale            string   `json:"locale"`
+	Subject           string   `json:"subject"`
+	HtmlBody          string   `json:"html_body"`
+	TextBody          *string  `json:"text_body"`
+	BaseTemplateName  *string  `json:"base_template_name"`
+	Variables         []string `json:"variables"`
+	DefaultSender     *string  `json:"default_sender"`
+	SuppressionPolicy string   `json:"suppression_policy"`
 }
 
+// Continues the version numbers of a deleted template of the same name, whose
+// history is kept
 func (q *Queries) CreateTemplate(ctx context.Context, arg CreateTemplateParams) (EmailTemplate, error) {
 	row := q.db.QueryRow(ctx, createTemplate,
+		arg.TenantID,
 		arg.Name,
+		arg.Locale,
 		arg.Subject,
 		arg.HtmlBody,
 		arg.TextBody,
 		arg.BaseTemplateName,
 		arg.Variables,
-		arg.Version,
+		arg.DefaultSender,
+		arg.SuppressionPolicy,
 	)
 	var i EmailTemplate
 	err := row.Scan(
@@ -46,18 +59,54 @@ func (q *Queries) CreateTemplate(ctx context.Context, arg CreateTemplateParams)
 		&i.Version,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DefaultSender,
+		&i.SuppressionPolicy,
+		&i.Locale,
+		&i.BaseTemplateLocale,
+		&i.TenantID,
 	)
 	return i, err
 }
 
+const deleteTemplate = `-- name: DeleteTemplate :execrows
+DELETE FROM email_templates
+WHERE tenant_id = $1 AND name = $2 AND locale = $3 AND version = $4
+`
+
+type DeleteTemplateParams struct {
+	TenantID string `json:"tenant_id"`
+	Name     string `json:"name"`
+	Locale   string `json:"locale"`
+	Version  int32  `json:"version"`
+}
+
+func (q *Queries) DeleteTemplate(ctx context.Context, arg DeleteTemplateParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteTemplate,
+		arg.TenantID,
+		arg.Name,
+		arg.Locale,
+		arg.Version,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getTemplate = `-- name: GetTemplate :one
-SELECT name, subject, html_body, text_body, base_template_name, variables, version, created_at, updated_at
+SELECT name, subject, html_body, text_body, base_template_name, variables, version, created_at, updated_at, default_sender, suppression_policy, locale, base_template_locale, tenant_id
 FROM email_templates
-WHERE name = $1
+WHERE tenant_id = $1 AND name = $2 AND locale = $3
 `
 
-func (q *Queries) GetTemplate(ctx context.Context, name string) (EmailTemplate, error) {
-	row := q.db.QueryRow(ctx, getTemplate, name)
+type GetTemplateParams struct {
+	TenantID string `json:"tenant_id"`
+	Name     string `json:"name"`
+	Locale   string `json:"locale"`
+}
+
+func (q *Queries) GetTemplate(ctx context.Context, arg GetTemplateParams) (EmailTemplate, error) {
+	row := q.db.QueryRow(ctx, getTemplate, arg.TenantID, arg.Name, arg.Locale)
 	var i EmailTemplate
 	err := row.Scan(
 		&i.Name,
@@ -69,18 +118,142 @@ func (q *Queries) GetTemplate(ctx context.Context, name string) (EmailTemplate,
 		&i.Version,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DefaultSender,
+		&i.SuppressionPolicy,
+		&i.Locale,
+		&i.BaseTemplateLocale,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const getTemplateVersion = `-- name: GetTemplateVersion :one
+SELECT template_name, version, subject, html_body, text_body, base_template_name, variables, created_at, default_sender, suppression_policy, locale, tenant_id, base_template_version
+FROM template_versions
+WHERE tenant_id = $1 AND template_name = $2 AND locale = $3 AND version = $4
+`
+
+type GetTemplateVersionParams struct {
+	TenantID     string `json:"tenant_id"`
+	TemplateName string `json:"template_name"`
+	Locale       string `json:"locale"`
+	Version      int32  `json:"version"`
+}
+
+func (q *Queries) GetTemplateVersion(ctx context.Context, arg GetTemplateVersionParams) (TemplateVersion, error) {
+	row := q.db.QueryRow(ctx, getTemplateVersion,
+		arg.TenantID,
+		arg.TemplateName,
+		arg.Locale,
+		arg.Version,
+	)
+	var i TemplateVersion
+	err := row.Scan(
+		&i.TemplateName,
+		&i.Version,
+		&i.Subject,
+		&i.HtmlBody,
+		&i.TextBody,
+		&i.BaseTemplateName,
+		&i.Variables,
+		&i.CreatedAt,
+		&i.DefaultSender,
+		&i.SuppressionPolicy,
+		&i.Locale,
+		&i.TenantID,
+		&i.BaseTemplateVersion,
 	)
 	return i, err
 }
 
+const listDependentTemplates = `-- name: ListDependentTemplates :many
+SELECT DISTINCT name
+FROM email_templates
+WHERE tenant_id = $1 AND base_template_name = $2
+ORDER BY name
+`
+
+type ListDependentTemplatesParams struct {
+	TenantID         string  `json:"tenant_id"`
+	BaseTemplateName *string `json:"base_template_name"`
+}
+
+func (q *Queries) ListDependentTemplates(ctx context.Context, arg ListDependentTemplatesParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, listDependentTemplates, arg.TenantID, arg.BaseTemplateName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTemplateVersions = `-- name: ListTemplateVersions :many
+SELECT template_name, version, subject, html_body, text_body, base_template_name, variables, created_at, default_sender, suppression_policy, locale, tenant_id, base_template_version
+FROM template_versions
+WHERE tenant_id = $1 AND template_name = $2 AND locale = $3
+ORDER BY version DESC
+`
+
+type ListTemplateVersionsParams struct {
+	TenantID     string `json:"tenant_id"`
+	TemplateName string `json:"template_name"`
+	Locale       string `json:"locale"`
+}
+
+func (q *Queries) ListTemplateVersions(ctx context.Context, arg ListTemplateVersionsParams) ([]TemplateVersion, error) {
+	rows, err := q.db.Query(ctx, listTemplateVersions, arg.TenantID, arg.TemplateName, arg.Locale)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TemplateVersion{}
+	for rows.Next() {
+		var i TemplateVersion
+		if err := rows.Scan(
+			&i.TemplateName,
+			&i.Version,
+			&i.Subject,
+			&i.HtmlBody,
+			&i.TextBody,
+			&i.BaseTemplateName,
+			&i.Variables,
+			&i.CreatedAt,
+			&i.DefaultSender,
+			&i.SuppressionPolicy,
+			&i.Locale,
+			&i.TenantID,
+			&i.BaseTemplateVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTemplates = `-- name: ListTemplates :many
-SELECT name, subject, html_body, text_body, base_template_name, variables, version, created_at, updated_at
+SELECT name, subject, html_body, text_body, base_template_name, variables, version, created_at, updated_at, default_sender, suppression_policy, locale, base_template_locale, tenant_id
 FROM email_templates
-ORDER BY name, version DESC
+WHERE tenant_id = $1
+ORDER BY name, locale
 `
 
-func (q *Queries) ListTemplates(ctx context.Context) ([]EmailTemplate, error) {
-	rows, err := q.db.Query(ctx, listTemplates)
+func (q *Queries) ListTemplates(ctx context.Context, tenantID string) ([]EmailTemplate, error) {
+	rows, err := q.db.Query(ctx, listTemplates, tenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -98,6 +271,11 @@ func (q *Queries) ListTemplates(ctx context.Context) ([]EmailTemplate, error) {
 			&i.Version,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DefaultSender,
+			&i.SuppressionPolicy,
+			&i.Locale,
+			&i.BaseTemplateLocale,
+			&i.TenantID,
 		); err != nil {
 			return nil, err
 		}
@@ -108,3 +286,183 @@ func (q *Queries) ListTemplates(ctx context.Context) ([]EmailTemplate, error) {
 	}
 	return items, nil
 }
+
+const resolveTemplate = `-- name: ResolveTemplate :one
+SELECT name, subject, html_body, text_body, base_template_name, variables, version, created_at, updated_at, default_sender, suppression_policy, locale, base_template_locale, tenant_id
+FROM email_templates
+WHERE tenant_id = $1 AND name = $2 AND locale = ANY($3::text[])
+ORDER BY array_position($3::text[], locale)
+LIMIT 1
+`
+
+type ResolveTemplateParams struct {
+	TenantID string   `json:"tenant_id"`
+	Name     string   `json:"name"`
+	Locales  []string `json:"locales"`
+}
+
+// Returns the first variant found in locales, which lists the preferred locale first
+func (q *Queries) ResolveTemplate(ctx context.Context, arg ResolveTemplateParams) (EmailTemplate, error) {
+	row := q.db.QueryRow(ctx, resolveTemplate, arg.TenantID, arg.Name, arg.Locales)
+	var i EmailTemplate
+	err := row.Scan(
+		&i.Name,
+		&i.Subject,
+		&i.HtmlBody,
+		&i.TextBody,
+		&i.BaseTemplateName,
+		&i.Variables,
+		&i.Version,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DefaultSender,
+		&i.SuppressionPolicy,
+		&i.Locale,
+		&i.BaseTemplateLocale,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const resolveTemplateChain = `-- name: ResolveTemplateChain :many
+WITH RECURSIVE chain AS (
+    (
+        SELECT t.name, t.subject, t.html_body, t.text_body, t.base_template_name, t.variables, t.version, t.created_at, t.updated_at, t.default_sender, t.suppression_policy, t.locale, t.base_template_locale, t.tenant_id, ARRAY[t.name] AS path, 0 AS depth
+        FROM email_templates t
+        WHERE t.tenant_id = $1 AND t.name = $2 AND t.locale = ANY($3::text[])
+        ORDER BY array_position($3::text[], t.locale)
+        LIMIT 1
+    )
+    UNION ALL
+    SELECT b.name, b.subject, b.html_body, b.text_body, b.base_template_name, b.variables, b.version, b.created_at, b.updated_at, b.default_sender, b.suppression_policy, b.locale, b.base_template_locale, b.tenant_id, chain.path || b.name, chain.depth + 1
+    FROM chain
+    CROSS JOIN LATERAL (
+        SELECT name, subject, html_body, text_body, base_template_name, variables, version, created_at, updated_at, default_sender, suppression_policy, locale, base_template_locale, tenant_id
+        FROM email_templates t
+        WHERE t.tenant_id = chain.tenant_id AND t.name = chain.base_template_name AND t.locale = ANY($3::text[])
+        ORDER BY array_position($3::text[], t.locale)
+        LIMIT 1
+    ) b
+    WHERE b.name <> ALL(chain.path)
+)
+SELECT name, subject, html_body, text_body, base_template_name, variables, version, created_at, updated_at, default_sender, suppression_policy, locale, base_template_locale, tenant_id
+FROM chain
+ORDER BY depth
+`
+
+type ResolveTemplateChainParams struct {
+	TenantID string   `json:"tenant_id"`
+	Name     string   `json:"name"`
+	Locales  []string `json:"locales"`
+}
+
+type ResolveTemplateChainRow struct {
+	Name               string    `json:"name"`
+	Subject            string    `json:"subject"`
+	HtmlBody           string    `json:"html_body"`
+	TextBody           *string   `json:"text_body"`
+	BaseTemplateName   *string   `json:"base_template_name"`
+	Variables          []string  `json:"variables"`
+	Version            int32     `json:"version"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+	DefaultSender      *string   `json:"default_sender"`
+	SuppressionPolicy  string    `json:"suppression_policy"`
+	Locale             string    `json:"locale"`
+	BaseTemplateLocale string    `json:"base_template_locale"`
+	TenantID           string    `json:"tenant_id"`
+}
+
+// Returns a template followed by each base it inherits from, choosing every
+// variant by locale preference as ResolveTemplate does. The walk stops rather
+// than loop if the templates form a cycle.
+func (q *Queries) ResolveTemplateChain(ctx context.Context, arg ResolveTemplateChainParams) ([]ResolveTemplateChainRow, error) {
+	rows, err := q.db.Query(ctx, resolveTemplateChain, arg.TenantID, arg.Name, arg.Locales)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ResolveTemplateChainRow{}
+	for rows.Next() {
+		var i ResolveTemplateChainRow
+		if err := rows.Scan(
+			&i.Name,
+			&i.Subject,
+			&i.HtmlBody,
+			&i.TextBody,
+			&i.BaseTemplateName,
+			&i.Variables,
+			&i.Version,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DefaultSender,
+			&i.SuppressionPolicy,
+			&i.Locale,
+			&i.BaseTemplateLocale,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateTemplate = `-- name: UpdateTemplate :one
+UPDATE email_templates
+SET subject = $4, html_body = $5, text_body = $6, base_template_name = $7, variables = $8,
+    default_sender = $9, suppression_policy = $10, version = version + 1, updated_at = now()
+WHERE tenant_id = $1 AND name = $2 AND locale = $3 AND version = $11
+RETURNING name, subject, html_body, text_body, base_template_name, variables, version, created_at, updated_at, default_sender, suppression_policy, locale, base_template_locale, tenant_id
+`
+
+type UpdateTemplateParams struct {
+	TenantID          string   `json:"tenant_id"`
+	Name              string   `json:"name"`
+	Locale            string   `json:"locale"`
+	Subject           string   `json:"subject"`
+	HtmlBody          string   `json:"html_body"`
+	TextBody          *string  `json:"text_body"`
+	BaseTemplateName  *string  `json:"base_template_name"`
+	Variables         []string `json:"variables"`
+	DefaultSender     *string  `json:"default_sender"`
+	SuppressionPolicy string   `json:"suppression_policy"`
+	Version           int32    `json:"version"`
+}
+
+func (q *Queries) UpdateTemplate(ctx context.Context, arg UpdateTemplateParams) (EmailTemplate, error) {
+	row := q.db.QueryRow(ctx, updateTemplate,
+		arg.TenantID,
+		arg.Name,
+		arg.Locale,
+		arg.Subject,
+		arg.HtmlBody,
+		arg.TextBody,
+		arg.BaseTemplateName,
+		arg.Variables,
+		arg.DefaultSender,
+		arg.SuppressionPolicy,
+		arg.Version,
+	)
+	var i EmailTemplate
+	err := row.Scan(
+		&i.Name,
+		&i.Subject,
+		&i.HtmlBody,
+		&i.TextBody,
+		&i.BaseTemplateName,
+		&i.Variables,
+		&i.Version,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DefaultSender,
+		&i.SuppressionPolicy,
+		&i.Locale,
+		&i.BaseTemplateLocale,
+		&i.TenantID,
+	)
+	return i, err
+}
@@ -0,0 +1,148 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: tenants.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const createTenant = `-- name: CreateTenant :one
+INSERT INTO tenants (id, name, from_address, from_name, provider, provider_credentials, provider_fallback)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, name, from_address, from_name, provider, created_at, provider_credentials, provider_fallback
+`
+
+type CreateTenantParams struct {
+	ID                  string `json:"id"`
+	Name                string `json:"name"`
+	FromAddress         string `json:"from_address"`
+	FromName            string `json:"from_name"`
+	Provider            string `json:"provider"`
+	ProviderCredentials []byte `json:"provider_credentials"`
+	ProviderFallback    bool   `json:"provider_fallback"`
+}
+
+func (q *Queries) CreateTenant(ctx context.Context, arg CreateTenantParams) (Tenant, error) {
+	row := q.db.QueryRow(ctx, createTenant,
+		arg.ID,
+		arg.Name,
+		arg.FromAddress,
+		arg.FromName,
+		arg.Provider,
+		arg.ProviderCredentials,
+		arg.ProviderFallback,
+	)
+	var i Tenant
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.FromAddress,
+		&i.FromName,
+		&i.Provider,
+		&i.CreatedAt,
+		&i.ProviderCredentials,
+		&i.ProviderFallback,
+	)
+	return i, err
+}
+
+const getTenant = `-- name: GetTenant :one
+SELECT id, name, from_address, from_name, provider, created_at, provider_credentials, provider_fallback
+FROM tenants
+WHERE id = $1
+`
+
+func (q *Queries) GetTenant(ctx context.Context, id string) (Tenant, error) {
+	row := q.db.QueryRow(ctx, getTenant, id)
+	var i Tenant
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.FromAddress,
+		&i.FromName,
+		&i.Provider,
+		&i.CreatedAt,
+		&i.ProviderCredentials,
+		&i.ProviderFallback,
+	)
+	return i, err
+}
+
+const listTenants = `-- name: ListTenants :many
+SELECT id, name, from_address, from_name, provider, created_at, provider_credentials, provider_fallback
+FROM tenants
+ORDER BY id
+`
+
+func (q *Queries) ListTenants(ctx context.Context) ([]Tenant, error) {
+	rows, err := q.db.Query(ctx, listTenants)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Tenant{}
+	for rows.Next() {
+		var i Tenant
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.FromAddress,
+			&i.FromName,
+			&i.Provider,
+			&i.CreatedAt,
+			&i.ProviderCredentials,
+			&i.ProviderFallback,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateTenant = `-- name: UpdateTenant :one
+UPDATE tenants
+SET name = $2, from_address = $3, from_name = $4, provider = $5, provider_credentials = $6, provider_fallback = $7
+WHERE id = $1
+RETURNING id, name, from_address, from_name, provider, created_at, provider_credentials, provider_fallback
+`
+
+type UpdateTenantParams struct {
+	ID                  string `json:"id"`
+	Name                string `json:"name"`
+	FromAddress         string `json:"from_address"`
+	FromName            string `json:"from_name"`
+	Provider            string `json:"provider"`
+	ProviderCredentials []byte `json:"provider_credentials"`
+	ProviderFallback    bool   `json:"provider_fallback"`
+}
+
+func (q *Queries) UpdateTenant(ctx context.Context, arg UpdateTenantParams) (Tenant, error) {
+	row := q.db.QueryRow(ctx, updateTenant,
+		arg.ID,
+		arg.Name,
+		arg.FromAddress,
+		arg.FromName,
+		arg.Provider,
+		arg.ProviderCredentials,
+		arg.ProviderFallback,
+	)
+	var i Tenant
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.FromAddress,
+		&i.FromName,
+		&i.Provider,
+		&i.CreatedAt,
+		&i.ProviderCredentials,
+		&i.ProviderFallback,
+	)
+	return i, err
+}
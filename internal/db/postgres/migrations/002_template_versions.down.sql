@@ -0,0 +1,4 @@
+-- Drop template version history
+DROP TRIGGER IF EXISTS email_templates_record_version ON email_templates;
+DROP FUNCTION IF EXISTS record_template_version();
+DROP TABLE IF EXISTS template_versions;
@@ -0,0 +1,33 @@
+-- Template version history
+-- Every published revision of a template is kept so sends can pin a version
+-- and edits can be rolled back. Rows are written by trigger and never updated.
+CREATE TABLE template_versions (
+    template_name TEXT NOT NULL REFERENCES email_templates(name) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    subject TEXT NOT NULL,
+    html_body TEXT NOT NULL,
+    text_body TEXT,
+    base_template_name TEXT,
+    variables TEXT[] DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (template_name, version)
+);
+
+-- Record each new revision, whether written by mailman or by hand. Rewriting
+-- an existing version fails on the primary key, which keeps history immutable.
+CREATE FUNCTION record_template_version() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO template_versions (template_name, version, subject, html_body, text_body, base_template_name, variables)
+    VALUES (NEW.name, NEW.version, NEW.subject, NEW.html_body, NEW.text_body, NEW.base_template_name, NEW.variables);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER email_templates_record_version
+AFTER INSERT OR UPDATE OF subject, html_body, text_body, base_template_name, variables, version ON email_templates
+FOR EACH ROW EXECUTE FUNCTION record_template_version();
+
+-- Backfill the current revision of existing templates
+INSERT INTO template_versions (template_name, version, subject, html_body, text_body, base_template_name, variables, created_at)
+SELECT name, version, subject, html_body, text_body, base_template_name, variables, updated_at
+FROM email_templates;
@@ -0,0 +1,17 @@
+-- Restore the version trigger without default senders
+DROP TRIGGER email_templates_record_version ON email_templates;
+CREATE TRIGGER email_templates_record_version
+AFTER INSERT OR UPDATE OF subject, html_body, text_body, base_template_name, variables, version ON email_templates
+FOR EACH ROW EXECUTE FUNCTION record_template_version();
+
+CREATE OR REPLACE FUNCTION record_template_version() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO template_versions (template_name, version, subject, html_body, text_body, base_template_name, variables)
+    VALUES (NEW.name, NEW.version, NEW.subject, NEW.html_body, NEW.text_body, NEW.base_template_name, NEW.variables);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE template_versions DROP COLUMN IF EXISTS default_sender;
+ALTER TABLE email_templates DROP COLUMN IF EXISTS default_sender;
+DROP TABLE IF EXISTS senders;
@@ -0,0 +1,25 @@
+-- Sender identities
+-- Emails may only be sent from addresses registered here (or the server's
+-- configured default), so a compromised client cannot spoof arbitrary senders.
+CREATE TABLE senders (
+    address TEXT PRIMARY KEY,
+    name TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Templates may name a default sender, e.g. billing mail from billing@
+ALTER TABLE email_templates ADD COLUMN default_sender TEXT REFERENCES senders(address);
+ALTER TABLE template_versions ADD COLUMN default_sender TEXT;
+
+CREATE OR REPLACE FUNCTION record_template_version() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO template_versions (template_name, version, subject, html_body, text_body, base_template_name, variables, default_sender)
+    VALUES (NEW.name, NEW.version, NEW.subject, NEW.html_body, NEW.text_body, NEW.base_template_name, NEW.variables, NEW.default_sender);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER email_templates_record_version ON email_templates;
+CREATE TRIGGER email_templates_record_version
+AFTER INSERT OR UPDATE OF subject, html_body, text_body, base_template_name, variables, default_sender, version ON email_templates
+FOR EACH ROW EXECUTE FUNCTION record_template_version();
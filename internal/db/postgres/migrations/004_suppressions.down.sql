@@ -0,0 +1,17 @@
+-- Restore the version trigger without suppression policies
+DROP TRIGGER email_templates_record_version ON email_templates;
+CREATE TRIGGER email_templates_record_version
+AFTER INSERT OR UPDATE OF subject, html_body, text_body, base_template_name, variables, default_sender, version ON email_templates
+FOR EACH ROW EXECUTE FUNCTION record_template_version();
+
+CREATE OR REPLACE FUNCTION record_template_version() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO template_versions (template_name, version, subject, html_body, text_body, base_template_name, variables, default_sender)
+    VALUES (NEW.name, NEW.version, NEW.subject, NEW.html_body, NEW.text_body, NEW.base_template_name, NEW.variables, NEW.default_sender);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE template_versions DROP COLUMN IF EXISTS suppression_policy;
+ALTER TABLE email_templates DROP COLUMN IF EXISTS suppression_policy;
+DROP TABLE IF EXISTS suppressions;
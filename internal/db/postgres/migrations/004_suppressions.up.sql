@@ -0,0 +1,28 @@
+-- Suppressed recipients
+-- Addresses that hard-bounced, complained or unsubscribed are not emailed again,
+-- protecting the sender reputation. A suppression without an expiry is permanent.
+CREATE TABLE suppressions (
+    address TEXT PRIMARY KEY,
+    reason TEXT NOT NULL CHECK (reason IN ('bounce', 'complaint', 'unsubscribe', 'manual')),
+    source TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Templates choose whether a suppressed recipient rejects the send or is dropped from it
+ALTER TABLE email_templates ADD COLUMN suppression_policy TEXT NOT NULL DEFAULT 'reject'
+    CHECK (suppression_policy IN ('reject', 'drop'));
+ALTER TABLE template_versions ADD COLUMN suppression_policy TEXT NOT NULL DEFAULT 'reject';
+
+CREATE OR REPLACE FUNCTION record_template_version() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO template_versions (template_name, version, subject, html_body, text_body, base_template_name, variables, default_sender, suppression_policy)
+    VALUES (NEW.name, NEW.version, NEW.subject, NEW.html_body, NEW.text_body, NEW.base_template_name, NEW.variables, NEW.default_sender, NEW.suppression_policy);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER email_templates_record_version ON email_templates;
+CREATE TRIGGER email_templates_record_version
+AFTER INSERT OR UPDATE OF subject, html_body, text_body, base_template_name, variables, default_sender, suppression_policy, version ON email_templates
+FOR EACH ROW EXECUTE FUNCTION record_template_version();
@@ -0,0 +1,16 @@
+-- Delivery events reported by email providers (delivered, bounced, opened, ...)
+-- Providers retry webhooks, so events are deduplicated on the provider's event ID.
+CREATE TABLE delivery_events (
+    id BIGSERIAL PRIMARY KEY,
+    provider TEXT NOT NULL,
+    provider_event_id TEXT NOT NULL,
+    message_id TEXT NOT NULL DEFAULT '', -- Empty when the event cannot be correlated to an email
+    event_type TEXT NOT NULL,
+    recipient TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    occurred_at TIMESTAMPTZ NOT NULL,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (provider, provider_event_id)
+);
+
+CREATE INDEX delivery_events_message_id_idx ON delivery_events (message_id, occurred_at);
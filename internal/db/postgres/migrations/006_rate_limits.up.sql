@@ -0,0 +1,7 @@
+-- Token buckets for send rate limits, shared by every worker replica.
+-- Tokens are refilled lazily from updated_at whenever a bucket is taken from.
+CREATE TABLE rate_limit_buckets (
+    key TEXT PRIMARY KEY, -- e.g. provider:sendgrid or domain:example.com
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,26 @@
+-- Drop localized variants and restore name-keyed templates
+CREATE OR REPLACE FUNCTION record_template_version() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO template_versions (template_name, version, subject, html_body, text_body, base_template_name, variables, default_sender, suppression_policy)
+    VALUES (NEW.name, NEW.version, NEW.subject, NEW.html_body, NEW.text_body, NEW.base_template_name, NEW.variables, NEW.default_sender, NEW.suppression_policy);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DELETE FROM email_templates WHERE locale <> '';
+
+ALTER TABLE email_templates DROP CONSTRAINT IF EXISTS email_templates_base_template_fkey;
+ALTER TABLE email_templates DROP COLUMN IF EXISTS base_template_locale;
+ALTER TABLE template_versions DROP CONSTRAINT IF EXISTS template_versions_template_fkey;
+ALTER TABLE template_versions DROP CONSTRAINT IF EXISTS template_versions_pkey;
+ALTER TABLE email_templates DROP CONSTRAINT IF EXISTS email_templates_pkey;
+
+ALTER TABLE email_templates ADD PRIMARY KEY (name);
+ALTER TABLE email_templates ADD CONSTRAINT email_templates_base_template_name_fkey
+    FOREIGN KEY (base_template_name) REFERENCES email_templates(name);
+ALTER TABLE template_versions ADD PRIMARY KEY (template_name, version);
+ALTER TABLE template_versions ADD CONSTRAINT template_versions_template_name_fkey
+    FOREIGN KEY (template_name) REFERENCES email_templates(name) ON DELETE CASCADE;
+
+ALTER TABLE template_versions DROP COLUMN IF EXISTS locale;
+ALTER TABLE email_templates DROP COLUMN IF EXISTS locale;
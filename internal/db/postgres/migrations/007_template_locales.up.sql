@@ -0,0 +1,31 @@
+-- Localized templates
+-- A template may have a variant per locale alongside its default, which has an
+-- empty locale. Sends fall back from e.g. fr-CA to fr to the default.
+ALTER TABLE email_templates ADD COLUMN locale TEXT NOT NULL DEFAULT '';
+ALTER TABLE template_versions ADD COLUMN locale TEXT NOT NULL DEFAULT '';
+
+-- Names are no longer unique, so rebuild the keys that assumed they were
+ALTER TABLE template_versions DROP CONSTRAINT template_versions_template_name_fkey;
+ALTER TABLE template_versions DROP CONSTRAINT template_versions_pkey;
+ALTER TABLE email_templates DROP CONSTRAINT email_templates_base_template_name_fkey;
+ALTER TABLE email_templates DROP CONSTRAINT email_templates_pkey;
+
+ALTER TABLE email_templates ADD PRIMARY KEY (name, locale);
+ALTER TABLE template_versions ADD PRIMARY KEY (template_name, locale, version);
+ALTER TABLE template_versions ADD CONSTRAINT template_versions_template_fkey
+    FOREIGN KEY (template_name, locale) REFERENCES email_templates(name, locale) ON DELETE CASCADE;
+
+-- Base templates are resolved in the sender's locale when rendering, but every
+-- base must have a default variant to fall back to
+ALTER TABLE email_templates ADD COLUMN base_template_locale TEXT NOT NULL DEFAULT ''
+    CHECK (base_template_locale = '');
+ALTER TABLE email_templates ADD CONSTRAINT email_templates_base_template_fkey
+    FOREIGN KEY (base_template_name, base_template_locale) REFERENCES email_templates(name, locale);
+
+CREATE OR REPLACE FUNCTION record_template_version() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO template_versions (template_name, locale, version, subject, html_body, text_body, base_template_name, variables, default_sender, suppression_policy)
+    VALUES (NEW.name, NEW.locale, NEW.version, NEW.subject, NEW.html_body, NEW.text_body, NEW.base_template_name, NEW.variables, NEW.default_sender, NEW.suppression_policy);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,3 @@
+-- Stop notifying replicas of template changes
+DROP TRIGGER IF EXISTS email_templates_notify ON email_templates;
+DROP FUNCTION IF EXISTS notify_templates_changed();
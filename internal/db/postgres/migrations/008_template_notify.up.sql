@@ -0,0 +1,13 @@
+-- Template change notifications
+-- Replicas cache templates in memory and drop the cache when notified, so any
+-- write to a template, including one made by hand, reaches every replica.
+CREATE FUNCTION notify_templates_changed() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('templates_changed', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER email_templates_notify
+AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON email_templates
+FOR EACH STATEMENT EXECUTE FUNCTION notify_templates_changed();
@@ -0,0 +1,12 @@
+-- API keys
+-- Services authenticate to the gRPC API with a key whose scopes limit what it may
+-- do. Only a SHA-256 hash of each key's secret is stored; revoked keys are kept so
+-- their history remains visible.
+CREATE TABLE api_keys (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    secret_hash BYTEA NOT NULL,
+    scopes TEXT[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    revoked_at TIMESTAMPTZ
+);
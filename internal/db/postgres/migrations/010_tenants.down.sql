@@ -0,0 +1,29 @@
+-- Drop tenants and return every template to a single namespace
+CREATE OR REPLACE FUNCTION record_template_version() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO template_versions (template_name, locale, version, subject, html_body, text_body, base_template_name, variables, default_sender, suppression_policy)
+    VALUES (NEW.name, NEW.locale, NEW.version, NEW.subject, NEW.html_body, NEW.text_body, NEW.base_template_name, NEW.variables, NEW.default_sender, NEW.suppression_policy);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DELETE FROM email_templates WHERE tenant_id <> 'default';
+DELETE FROM api_keys WHERE tenant_id <> 'default';
+
+ALTER TABLE email_templates DROP CONSTRAINT IF EXISTS email_templates_base_template_fkey;
+ALTER TABLE template_versions DROP CONSTRAINT IF EXISTS template_versions_template_fkey;
+ALTER TABLE template_versions DROP CONSTRAINT IF EXISTS template_versions_pkey;
+ALTER TABLE email_templates DROP CONSTRAINT IF EXISTS email_templates_pkey;
+
+ALTER TABLE email_templates ADD PRIMARY KEY (name, locale);
+ALTER TABLE template_versions ADD PRIMARY KEY (template_name, locale, version);
+ALTER TABLE template_versions ADD CONSTRAINT template_versions_template_fkey
+    FOREIGN KEY (template_name, locale) REFERENCES email_templates(name, locale) ON DELETE CASCADE;
+ALTER TABLE email_templates ADD CONSTRAINT email_templates_base_template_fkey
+    FOREIGN KEY (base_template_name, base_template_locale) REFERENCES email_templates(name, locale);
+
+ALTER TABLE template_versions DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE email_templates DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS tenant_id;
+
+DROP TABLE IF EXISTS tenants;
@@ -0,0 +1,42 @@
+-- Tenants
+-- Each product line sharing the server is a tenant with its own templates, API
+-- keys and sending defaults. Everything that predates tenants belongs to the
+-- default tenant.
+CREATE TABLE tenants (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL DEFAULT '',
+    from_address TEXT NOT NULL DEFAULT '', -- Replaces the server's default sender when set
+    from_name TEXT NOT NULL DEFAULT '',
+    provider TEXT NOT NULL DEFAULT '', -- Tried before the server's other providers when set
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO tenants (id, name) VALUES ('default', 'Default');
+
+ALTER TABLE api_keys ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+
+-- Template names are unique within a tenant, so the tenant leads every key
+ALTER TABLE email_templates ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+ALTER TABLE template_versions ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+
+ALTER TABLE template_versions DROP CONSTRAINT template_versions_template_fkey;
+ALTER TABLE template_versions DROP CONSTRAINT template_versions_pkey;
+ALTER TABLE email_templates DROP CONSTRAINT email_templates_base_template_fkey;
+ALTER TABLE email_templates DROP CONSTRAINT email_templates_pkey;
+
+ALTER TABLE email_templates ADD PRIMARY KEY (tenant_id, name, locale);
+ALTER TABLE template_versions ADD PRIMARY KEY (tenant_id, template_name, locale, version);
+ALTER TABLE template_versions ADD CONSTRAINT template_versions_template_fkey
+    FOREIGN KEY (tenant_id, template_name, locale) REFERENCES email_templates(tenant_id, name, locale) ON DELETE CASCADE;
+
+-- Templates may only inherit from templates of their own tenant
+ALTER TABLE email_templates ADD CONSTRAINT email_templates_base_template_fkey
+    FOREIGN KEY (tenant_id, base_template_name, base_template_locale) REFERENCES email_templates(tenant_id, name, locale);
+
+CREATE OR REPLACE FUNCTION record_template_version() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO template_versions (tenant_id, template_name, locale, version, subject, html_body, text_body, base_template_name, variables, default_sender, suppression_policy)
+    VALUES (NEW.tenant_id, NEW.name, NEW.locale, NEW.version, NEW.subject, NEW.html_body, NEW.text_body, NEW.base_template_name, NEW.variables, NEW.default_sender, NEW.suppression_policy);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
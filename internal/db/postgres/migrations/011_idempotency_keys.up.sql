@@ -0,0 +1,14 @@
+-- Idempotency keys
+-- Each key a client sends with an email records the job it enqueued, so a retried
+-- request returns the original message ID instead of sending the email again.
+-- Keys are forgotten once they expire.
+CREATE TABLE idempotency_keys (
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    key TEXT NOT NULL,
+    job_id BIGINT, -- Null only while the job is being enqueued
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (tenant_id, key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -0,0 +1,18 @@
+-- Share sender identities and suppressions between tenants again
+ALTER TABLE email_templates DROP CONSTRAINT IF EXISTS email_templates_default_sender_fkey;
+ALTER TABLE suppressions DROP CONSTRAINT IF EXISTS suppressions_pkey;
+ALTER TABLE senders DROP CONSTRAINT IF EXISTS senders_pkey;
+
+DELETE FROM suppressions WHERE tenant_id <> 'default';
+DELETE FROM senders WHERE tenant_id <> 'default';
+
+ALTER TABLE senders ADD PRIMARY KEY (address);
+ALTER TABLE suppressions ADD PRIMARY KEY (address);
+
+-- Other tenants' templates may still name their deleted senders until those
+-- templates are dropped with the tenants themselves
+ALTER TABLE email_templates ADD CONSTRAINT email_templates_default_sender_fkey
+    FOREIGN KEY (default_sender) REFERENCES senders(address) NOT VALID;
+
+ALTER TABLE suppressions DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE senders DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,17 @@
+-- Tenant sender identities and suppressions
+-- Each tenant registers its own senders and keeps its own suppression list, so
+-- one tenant can neither send as another's senders nor see or lift another's
+-- suppressions. Existing rows belong to the default tenant.
+ALTER TABLE senders ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+ALTER TABLE suppressions ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+
+ALTER TABLE email_templates DROP CONSTRAINT email_templates_default_sender_fkey;
+ALTER TABLE senders DROP CONSTRAINT senders_pkey;
+ALTER TABLE suppressions DROP CONSTRAINT suppressions_pkey;
+
+ALTER TABLE senders ADD PRIMARY KEY (tenant_id, address);
+ALTER TABLE suppressions ADD PRIMARY KEY (tenant_id, address);
+
+-- Templates may only default to a sender of their own tenant
+ALTER TABLE email_templates ADD CONSTRAINT email_templates_default_sender_fkey
+    FOREIGN KEY (tenant_id, default_sender) REFERENCES senders(tenant_id, address);
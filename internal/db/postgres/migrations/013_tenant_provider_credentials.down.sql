@@ -0,0 +1,2 @@
+-- Send every tenant's email through the server's provider accounts again
+ALTER TABLE tenants DROP COLUMN IF EXISTS provider_credentials;
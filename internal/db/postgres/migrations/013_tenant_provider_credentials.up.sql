@@ -0,0 +1,5 @@
+-- Tenant provider credentials
+-- A tenant may send through its own account with its provider rather than the
+-- server's. The credentials are stored as given, since they must be presented to
+-- the provider. Null sends through the server's account.
+ALTER TABLE tenants ADD COLUMN provider_credentials JSONB;
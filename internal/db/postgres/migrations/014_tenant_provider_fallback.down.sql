@@ -0,0 +1,2 @@
+-- Keep every tenant on its own account with no fallback setting
+ALTER TABLE tenants DROP COLUMN IF EXISTS provider_fallback;
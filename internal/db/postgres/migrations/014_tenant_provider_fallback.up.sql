@@ -0,0 +1,4 @@
+-- Tenant provider fallback
+-- A tenant sending through its own account only fails over to the server's
+-- providers when it opts in, since they send under the operator's reputation
+ALTER TABLE tenants ADD COLUMN provider_fallback BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,3 @@
+-- Require a bearer token from every client again
+DROP INDEX IF EXISTS api_keys_client_identity;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS client_identity;
@@ -0,0 +1,7 @@
+-- API key client identities
+-- A service calling over mutual TLS can authenticate with its client
+-- certificate instead of a bearer token. The certificate's identity is bound to
+-- at most one unrevoked key, whose tenant and scopes the calls then get.
+ALTER TABLE api_keys ADD COLUMN client_identity TEXT;
+
+CREATE UNIQUE INDEX api_keys_client_identity ON api_keys (client_identity) WHERE revoked_at IS NULL;
@@ -0,0 +1,19 @@
+-- Delete the history of deleted templates along with them again
+CREATE OR REPLACE FUNCTION record_template_version() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO template_versions (tenant_id, template_name, locale, version, subject, html_body, text_body, base_template_name, variables, default_sender, suppression_policy)
+    VALUES (NEW.tenant_id, NEW.name, NEW.locale, NEW.version, NEW.subject, NEW.html_body, NEW.text_body, NEW.base_template_name, NEW.variables, NEW.default_sender, NEW.suppression_policy);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE template_versions DROP COLUMN IF EXISTS base_template_version;
+
+DELETE FROM template_versions v
+WHERE NOT EXISTS (
+    SELECT 1 FROM email_templates t
+    WHERE t.tenant_id = v.tenant_id AND t.name = v.template_name AND t.locale = v.locale
+);
+
+ALTER TABLE template_versions ADD CONSTRAINT template_versions_template_fkey
+    FOREIGN KEY (tenant_id, template_name, locale) REFERENCES email_templates(tenant_id, name, locale) ON DELETE CASCADE;
@@ -0,0 +1,21 @@
+-- Template history
+-- Revisions outlive their template, so deleting a template no longer erases
+-- its history. A template created again under a deleted one's name continues
+-- its version numbers, so a version number always means the same content.
+ALTER TABLE template_versions DROP CONSTRAINT template_versions_template_fkey;
+
+-- Each revision records the version of its base's default variant it was
+-- published with, so a pinned version renders with the base as it was then.
+-- Revisions recorded before this are left NULL and use the current base.
+ALTER TABLE template_versions ADD COLUMN base_template_version INTEGER;
+
+CREATE OR REPLACE FUNCTION record_template_version() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO template_versions (tenant_id, template_name, locale, version, subject, html_body, text_body, base_template_name, base_template_version, variables, default_sender, suppression_policy)
+    VALUES (NEW.tenant_id, NEW.name, NEW.locale, NEW.version, NEW.subject, NEW.html_body, NEW.text_body, NEW.base_template_name,
+        (SELECT base.version FROM email_templates base
+         WHERE base.tenant_id = NEW.tenant_id AND base.name = NEW.base_template_name AND base.locale = NEW.base_template_locale),
+        NEW.variables, NEW.default_sender, NEW.suppression_policy);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,24 @@
+-- name: GetAPIKey :one
+SELECT id, name, secret_hash, scopes, created_at, revoked_at, tenant_id, client_identity
+FROM api_keys
+WHERE id = $1;
+
+-- name: GetAPIKeyByClientIdentity :one
+SELECT id, name, secret_hash, scopes, created_at, revoked_at, tenant_id, client_identity
+FROM api_keys
+WHERE client_identity = $1 AND revoked_at IS NULL;
+
+-- name: ListAPIKeys :many
+SELECT id, name, secret_hash, scopes, created_at, revoked_at, tenant_id, client_identity
+FROM api_keys
+ORDER BY tenant_id, created_at, id;
+
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (id, name, secret_hash, scopes, tenant_id, client_identity)
+VALUES ($1, $2, $3, $4, $5, sqlc.narg('client_identity'))
+RETURNING id, name, secret_hash, scopes, created_at, revoked_at, tenant_id, client_identity;
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked_at = now()
+WHERE id = $1 AND revoked_at IS NULL;
@@ -0,0 +1,10 @@
+-- name: InsertDeliveryEvent :execrows
+INSERT INTO delivery_events (provider, provider_event_id, message_id, event_type, recipient, reason, occurred_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (provider, provider_event_id) DO NOTHING;
+
+-- name: ListDeliveryEvents :many
+SELECT id, provider, provider_event_id, message_id, event_type, recipient, reason, occurred_at, received_at
+FROM delivery_events
+WHERE message_id = $1
+ORDER BY occurred_at, id;
@@ -0,0 +1,14 @@
+-- name: RefillRateLimitBucket :one
+-- Creates the bucket full, or tops it up for the time since it was last refilled,
+-- and locks it until the transaction ends
+INSERT INTO rate_limit_buckets AS b (key, tokens, updated_at)
+VALUES (@key, @capacity::float8, now())
+ON CONFLICT (key) DO UPDATE
+SET tokens = LEAST(@capacity::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * @rate::float8),
+    updated_at = now()
+RETURNING tokens;
+
+-- name: TakeRateLimitTokens :exec
+UPDATE rate_limit_buckets
+SET tokens = tokens - 1
+WHERE key = ANY(@keys::text[]);
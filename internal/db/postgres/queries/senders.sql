@@ -0,0 +1,19 @@
+-- name: GetSender :one
+SELECT address, name, created_at, tenant_id
+FROM senders
+WHERE tenant_id = $1 AND address = $2;
+
+-- name: ListSenders :many
+SELECT address, name, created_at, tenant_id
+FROM senders
+WHERE tenant_id = $1
+ORDER BY address;
+
+-- name: CreateSender :one
+INSERT INTO senders (tenant_id, address, name)
+VALUES ($1, $2, $3)
+RETURNING address, name, created_at, tenant_id;
+
+-- name: DeleteSender :execrows
+DELETE FROM senders
+WHERE tenant_id = $1 AND address = $2;
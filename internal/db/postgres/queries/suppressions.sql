@@ -0,0 +1,24 @@
+-- name: ListActiveSuppressions :many
+SELECT address, reason, source, expires_at, created_at, tenant_id
+FROM suppressions
+WHERE tenant_id = @tenant_id
+  AND address = ANY(@addresses::text[])
+  AND (expires_at IS NULL OR expires_at > now())
+ORDER BY address;
+
+-- name: ListSuppressions :many
+SELECT address, reason, source, expires_at, created_at, tenant_id
+FROM suppressions
+WHERE tenant_id = $1
+ORDER BY address;
+
+-- name: UpsertSuppression :one
+INSERT INTO suppressions (tenant_id, address, reason, source, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (tenant_id, address) DO UPDATE
+SET reason = EXCLUDED.reason, source = EXCLUDED.source, expires_at = EXCLUDED.expires_at, created_at = now()
+RETURNING address, reason, source, expires_at, created_at, tenant_id;
+
+-- name: DeleteSuppression :execrows
+DELETE FROM suppressions
+WHERE tenant_id = $1 AND address = $2;
@@ -1,14 +1,85 @@
 -- name: GetTemplate :one
-SELECT name, subject, html_body, text_body, base_template_name, variables, version, created_at, updated_at
+SELECT name, subject, html_body, text_body, base_template_name, variables, version, created_at, updated_at, default_sender, suppression_policy, locale, base_template_locale, tenant_id
 FROM email_templates
-WHERE name = $1;
+WHERE tenant_id = $1 AND name = $2 AND locale = $3;
+
+-- name: ResolveTemplate :one
+-- Returns the first variant found in locales, which lists the preferred locale first
+SELECT name, subject, html_body, text_body, base_template_name, variables, version, created_at, updated_at, default_sender, suppression_policy, locale, base_template_locale, tenant_id
+FROM email_templates
+WHERE tenant_id = @tenant_id AND name = @name AND locale = ANY(@locales::text[])
+ORDER BY array_position(@locales::text[], locale)
+LIMIT 1;
+
+-- name: ResolveTemplateChain :many
+-- Returns a template followed by each base it inherits from, choosing every
+-- variant by locale preference as ResolveTemplate does. The walk stops rather
+-- than loop if the templates form a cycle.
+WITH RECURSIVE chain AS (
+    (
+        SELECT t.name, t.subject, t.html_body, t.text_body, t.base_template_name, t.variables, t.version, t.created_at, t.updated_at, t.default_sender, t.suppression_policy, t.locale, t.base_template_locale, t.tenant_id, ARRAY[t.name] AS path, 0 AS depth
+        FROM email_templates t
+        WHERE t.tenant_id = @tenant_id AND t.name = @name AND t.locale = ANY(@locales::text[])
+        ORDER BY array_position(@locales::text[], t.locale)
+        LIMIT 1
+    )
+    UNION ALL
+    SELECT b.name, b.subject, b.html_body, b.text_body, b.base_template_name, b.variables, b.version, b.created_at, b.updated_at, b.default_sender, b.suppression_policy, b.locale, b.base_template_locale, b.tenant_id, chain.path || b.name, chain.depth + 1
+    FROM chain
+    CROSS JOIN LATERAL (
+        SELECT *
+        FROM email_templates t
+        WHERE t.tenant_id = chain.tenant_id AND t.name = chain.base_template_name AND t.locale = ANY(@locales::text[])
+        ORDER BY array_position(@locales::text[], t.locale)
+        LIMIT 1
+    ) b
+    WHERE b.name <> ALL(chain.path)
+)
+SELECT name, subject, html_body, text_body, base_template_name, variables, version, created_at, updated_at, default_sender, suppression_policy, locale, base_template_locale, tenant_id
+FROM chain
+ORDER BY depth;
 
 -- name: ListTemplates :many
-SELECT name, subject, html_body, text_body, base_template_name, variables, version, created_at, updated_at
+SELECT name, subject, html_body, text_body, base_template_name, variables, version, created_at, updated_at, default_sender, suppression_policy, locale, base_template_locale, tenant_id
 FROM email_templates
-ORDER BY name, version DESC;
+WHERE tenant_id = $1
+ORDER BY name, locale;
 
 -- name: CreateTemplate :one
-INSERT INTO email_templates (name, subject, html_body, text_body, base_template_name, variables, version)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING name, subject, html_body, text_body, base_template_name, variables, version, created_at, updated_at;
+-- Continues the version numbers of a deleted template of the same name, whose
+-- history is kept
+INSERT INTO email_templates (tenant_id, name, locale, subject, html_body, text_body, base_template_name, variables, default_sender, suppression_policy, version)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, (
+    SELECT COALESCE(max(v.version), 0) + 1
+    FROM template_versions v
+    WHERE v.tenant_id = $1 AND v.template_name = $2 AND v.locale = $3
+))
+RETURNING name, subject, html_body, text_body, base_template_name, variables, version, created_at, updated_at, default_sender, suppression_policy, locale, base_template_locale, tenant_id;
+
+-- name: UpdateTemplate :one
+UPDATE email_templates
+SET subject = $4, html_body = $5, text_body = $6, base_template_name = $7, variables = $8,
+    default_sender = $9, suppression_policy = $10, version = version + 1, updated_at = now()
+WHERE tenant_id = $1 AND name = $2 AND locale = $3 AND version = $11
+RETURNING name, subject, html_body, text_body, base_template_name, variables, version, created_at, updated_at, default_sender, suppression_policy, locale, base_template_locale, tenant_id;
+
+-- name: DeleteTemplate :execrows
+DELETE FROM email_templates
+WHERE tenant_id = $1 AND name = $2 AND locale = $3 AND version = $4;
+
+-- name: ListDependentTemplates :many
+SELECT DISTINCT name
+FROM email_templates
+WHERE tenant_id = $1 AND base_template_name = $2
+ORDER BY name;
+
+-- name: GetTemplateVersion :one
+SELECT template_name, version, subject, html_body, text_body, base_template_name, variables, created_at, default_sender, suppression_policy, locale, tenant_id, base_template_version
+FROM template_versions
+WHERE tenant_id = $1 AND template_name = $2 AND locale = $3 AND version = $4;
+
+-- name: ListTemplateVersions :many
+SELECT template_name, version, subject, html_body, text_body, base_template_name, variables, created_at, default_sender, suppression_policy, locale, tenant_id, base_template_version
+FROM template_versions
+WHERE tenant_id = $1 AND template_name = $2 AND locale = $3
+ORDER BY version DESC;
@@ -0,0 +1,20 @@
+-- name: GetTenant :one
+SELECT id, name, from_address, from_name, provider, created_at, provider_credentials, provider_fallback
+FROM tenants
+WHERE id = $1;
+
+-- name: ListTenants :many
+SELECT id, name, from_address, from_name, provider, created_at, provider_credentials, provider_fallback
+FROM tenants
+ORDER BY id;
+
+-- name: CreateTenant :one
+INSERT INTO tenants (id, name, from_address, from_name, provider, provider_credentials, provider_fallback)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, name, from_address, from_name, provider, created_at, provider_credentials, provider_fallback;
+
+-- name: UpdateTenant :one
+UPDATE tenants
+SET name = $2, from_address = $3, from_name = $4, provider = $5, provider_credentials = $6, provider_fallback = $7
+WHERE id = $1
+RETURNING id, name, from_address, from_name, provider, created_at, provider_credentials, provider_fallback;
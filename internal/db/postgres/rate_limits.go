@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/travisbale/mailman/internal/db/postgres/internal/sqlc"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// RateLimitsDB stores send rate limit token buckets
+type RateLimitsDB struct {
+	db *DB
+}
+
+// NewRateLimitsDB creates a new rate limits database adapter
+func NewRateLimitsDB(db *DB) *RateLimitsDB {
+	return &RateLimitsDB{db: db}
+}
+
+// TakeTokens takes one token from every bucket if all of them have one. Otherwise
+// nothing is taken and the time until the emptiest bucket refills is returned.
+// Buckets stay locked until the transaction ends, so callers should pass them in
+// a consistent order to avoid deadlocks.
+func (r *RateLimitsDB) TakeTokens(ctx context.Context, buckets []email.RateLimitBucket) (time.Duration, error) {
+	var wait time.Duration
+
+	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		keys := make([]string, len(buckets))
+		for i, bucket := range buckets {
+			keys[i] = bucket.Key
+
+			tokens, err := q.RefillRateLimitBucket(ctx, sqlc.RefillRateLimitBucketParams{
+				Key:      bucket.Key,
+				Capacity: float64(bucket.Limit.Count),
+				Rate:     bucket.Limit.Rate(),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to refill rate limit bucket: %w", err)
+			}
+
+			if tokens < 1 {
+				refill := time.Duration((1 - tokens) / bucket.Limit.Rate() * float64(time.Second))
+				wait = max(wait, refill)
+			}
+		}
+
+		if wait > 0 {
+			return nil
+		}
+
+		if err := q.TakeRateLimitTokens(ctx, keys); err != nil {
+			return fmt.Errorf("failed to take rate limit tokens: %w", err)
+		}
+		return nil
+	})
+
+	return wait, err
+}
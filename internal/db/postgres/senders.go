@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/travisbale/mailman/internal/db/postgres/internal/sqlc"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// SendersDB handles database operations for sender identities. Every operation is
+// scoped to the tenant of its context, see email.WithTenant.
+type SendersDB struct {
+	db *DB
+}
+
+// NewSendersDB creates a new senders database adapter
+func NewSendersDB(db *DB) *SendersDB {
+	return &SendersDB{db: db}
+}
+
+// GetSender retrieves a registered sender by address
+func (r *SendersDB) GetSender(ctx context.Context, address string) (*email.Sender, error) {
+	var sender *email.Sender
+
+	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		dbSender, err := q.GetSender(ctx, sqlc.GetSenderParams{
+			TenantID: email.TenantFromContext(ctx),
+			Address:  address,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: %s", email.ErrSenderNotFound, address)
+			}
+			return fmt.Errorf("failed to get sender: %w", err)
+		}
+
+		sender = convertSenderToDomain(dbSender)
+		return nil
+	})
+
+	return sender, err
+}
+
+// ListSenders retrieves every registered sender
+func (r *SendersDB) ListSenders(ctx context.Context) ([]*email.Sender, error) {
+	var senders []*email.Sender
+
+	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		dbSenders, err := q.ListSenders(ctx, email.TenantFromContext(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to list senders: %w", err)
+		}
+
+		senders = make([]*email.Sender, len(dbSenders))
+		for i := range dbSenders {
+			senders[i] = convertSenderToDomain(dbSenders[i])
+		}
+
+		return nil
+	})
+
+	return senders, err
+}
+
+// CreateSender registers a new sender
+func (r *SendersDB) CreateSender(ctx context.Context, sender *email.Sender) (*email.Sender, error) {
+	var created *email.Sender
+
+	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		dbSender, err := q.CreateSender(ctx, sqlc.CreateSenderParams{
+			TenantID: email.TenantFromContext(ctx),
+			Address:  sender.Address,
+			Name:     sender.Name,
+		})
+		if err != nil {
+			if isUniqueViolation(err) {
+				return fmt.Errorf("%w: %s", email.ErrSenderExists, sender.Address)
+			}
+			return fmt.Errorf("failed to create sender: %w", err)
+		}
+
+		created = convertSenderToDomain(dbSender)
+		return nil
+	})
+
+	return created, err
+}
+
+// DeleteSender unregisters a sender, refusing if a template uses it as its default
+func (r *SendersDB) DeleteSender(ctx context.Context, address string) error {
+	return r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		rows, err := q.DeleteSender(ctx, sqlc.DeleteSenderParams{
+			TenantID: email.TenantFromContext(ctx),
+			Address:  address,
+		})
+		if err != nil {
+			if isForeignKeyViolation(err) {
+				return fmt.Errorf("%w: %s is the default sender of a template", email.ErrSenderInUse, address)
+			}
+			return fmt.Errorf("failed to delete sender: %w", err)
+		}
+
+		if rows == 0 {
+			return fmt.Errorf("%w: %s", email.ErrSenderNotFound, address)
+		}
+
+		return nil
+	})
+}
+
+// convertSenderToDomain converts a sqlc Sender to a domain Sender
+func convertSenderToDomain(dbSender sqlc.Sender) *email.Sender {
+	return &email.Sender{
+		Address:   dbSender.Address,
+		Name:      dbSender.Name,
+		CreatedAt: dbSender.CreatedAt,
+	}
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/travisbale/mailman/internal/db/postgres/internal/sqlc"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// SuppressionsDB handles database operations for the suppression list. Every
+// operation is scoped to the tenant of its context, see email.WithTenant.
+type SuppressionsDB struct {
+	db *DB
+}
+
+// NewSuppressionsDB creates a new suppressions database adapter
+func NewSuppressionsDB(db *DB) *SuppressionsDB {
+	return &SuppressionsDB{db: db}
+}
+
+// ListActiveSuppressions returns the unexpired suppressions for the given addresses
+func (r *SuppressionsDB) ListActiveSuppressions(ctx context.Context, addresses []string) ([]*email.Suppression, error) {
+	var suppressions []*email.Suppression
+
+	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		dbSuppressions, err := q.ListActiveSuppressions(ctx, sqlc.ListActiveSuppressionsParams{
+			TenantID:  email.TenantFromContext(ctx),
+			Addresses: addresses,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list active suppressions: %w", err)
+		}
+
+		suppressions = convertSuppressionsToDomain(dbSuppressions)
+		return nil
+	})
+
+	return suppressions, err
+}
+
+// ListSuppressions retrieves every suppression, including expired ones
+func (r *SuppressionsDB) ListSuppressions(ctx context.Context) ([]*email.Suppression, error) {
+	var suppressions []*email.Suppression
+
+	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		dbSuppressions, err := q.ListSuppressions(ctx, email.TenantFromContext(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to list suppressions: %w", err)
+		}
+
+		suppressions = convertSuppressionsToDomain(dbSuppressions)
+		return nil
+	})
+
+	return suppressions, err
+}
+
+// UpsertSuppression suppresses an address, replacing any existing suppression for it
+func (r *SuppressionsDB) UpsertSuppression(ctx context.Context, suppression *email.Suppression) (*email.Suppression, error) {
+	var upserted *email.Suppression
+
+	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		dbSuppression, err := q.UpsertSuppression(ctx, sqlc.UpsertSuppressionParams{
+			TenantID:  email.TenantFromContext(ctx),
+			Address:   suppression.Address,
+			Reason:    suppression.Reason,
+			Source:    suppression.Source,
+			ExpiresAt: suppression.ExpiresAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to upsert suppression: %w", err)
+		}
+
+		upserted = convertSuppressionToDomain(dbSuppression)
+		return nil
+	})
+
+	return upserted, err
+}
+
+// DeleteSuppression removes an address from the suppression list
+func (r *SuppressionsDB) DeleteSuppression(ctx context.Context, address string) error {
+	return r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		rows, err := q.DeleteSuppression(ctx, sqlc.DeleteSuppressionParams{
+			TenantID: email.TenantFromContext(ctx),
+			Address:  address,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete suppression: %w", err)
+		}
+
+		if rows == 0 {
+			return fmt.Errorf("%w: %s", email.ErrSuppressionNotFound, address)
+		}
+
+		return nil
+	})
+}
+
+// convertSuppressionsToDomain converts sqlc Suppressions to domain Suppressions
+func convertSuppressionsToDomain(dbSuppressions []sqlc.Suppression) []*email.Suppression {
+	suppressions := make([]*email.Suppression, len(dbSuppressions))
+	for i := range dbSuppressions {
+		suppressions[i] = convertSuppressionToDomain(dbSuppressions[i])
+	}
+	return suppressions
+}
+
+// convertSuppressionToDomain converts a sqlc Suppression to a domain Suppression
+func convertSuppressionToDomain(dbSuppression sqlc.Suppression) *email.Suppression {
+	return &email.Suppression{
+		Address:   dbSuppression.Address,
+		Reason:    dbSuppression.Reason,
+		Source:    dbSuppression.Source,
+		ExpiresAt: dbSuppression.ExpiresAt,
+		CreatedAt: dbSuppression.CreatedAt,
+	}
+}
@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// templatesChannel is notified by a trigger whenever email_templates changes
+const templatesChannel = "templates_changed"
+
+// listenRetryDelay is how long Listen waits before reconnecting after an error
+const listenRetryDelay = time.Second
+
+// templateCacheSize caps how many resolved templates are kept. Every pinned
+// version sent is cached under its own key, so without a cap the cache would
+// grow with each published revision.
+const templateCacheSize = 1000
+
+// TemplateCache keeps resolved templates in memory so sends don't query the database
+// for templates that haven't changed, evicting the least recently used once full.
+// Templates it returns are shared between callers and must not be modified.
+type TemplateCache struct {
+	*TemplatesDB
+
+	mu          sync.Mutex
+	templates   map[templateCacheKey]*list.Element
+	recent      *list.List // Most recently used first, holding *templateCacheEntry
+	capacity    int
+	generation  uint64 // Incremented on invalidation so lookups in flight aren't cached
+	subscribers []func()
+}
+
+type templateCacheKey struct {
+	tenant  string
+	name    string
+	locale  string
+	version int32 // 0 for the current version
+}
+
+type templateCacheEntry struct {
+	key      templateCacheKey
+	template *email.Template
+}
+
+// NewTemplateCache wraps a templates database adapter with an in-memory cache
+func NewTemplateCache(templates *TemplatesDB) *TemplateCache {
+	return &TemplateCache{
+		TemplatesDB: templates,
+		templates:   make(map[templateCacheKey]*list.Element),
+		recent:      list.New(),
+		capacity:    templateCacheSize,
+	}
+}
+
+// GetTemplate retrieves a template and its bases from the cache, loading them from
+// the database on a miss
+func (c *TemplateCache) GetTemplate(ctx context.Context, name, locale string) (*email.Template, error) {
+	return c.get(templateCacheKey{tenant: email.TenantFromContext(ctx), name: name, locale: locale}, func() (*email.Template, error) {
+		return c.TemplatesDB.GetTemplate(ctx, name, locale)
+	})
+}
+
+// GetTemplateVersion retrieves a published revision of a template from the cache,
+// loading it from the database on a miss
+func (c *TemplateCache) GetTemplateVersion(ctx context.Context, name, locale string, version int32) (*email.Template, error) {
+	return c.get(templateCacheKey{tenant: email.TenantFromContext(ctx), name: name, locale: locale, version: version}, func() (*email.Template, error) {
+		return c.TemplatesDB.GetTemplateVersion(ctx, name, locale, version)
+	})
+}
+
+// Create stores a new template and invalidates the cache
+func (c *TemplateCache) Create(ctx context.Context, template *email.Template) (*email.Template, error) {
+	defer c.Invalidate()
+	return c.TemplatesDB.Create(ctx, template)
+}
+
+// Update modifies a template and invalidates the cache
+func (c *TemplateCache) Update(ctx context.Context, template *email.Template) (*email.Template, error) {
+	defer c.Invalidate()
+	return c.TemplatesDB.Update(ctx, template)
+}
+
+// Delete removes a template and invalidates the cache
+func (c *TemplateCache) Delete(ctx context.Context, name, locale string, version int32) error {
+	defer c.Invalidate()
+	return c.TemplatesDB.Delete(ctx, name, locale, version)
+}
+
+// Subscribe registers a function to call whenever the cache is invalidated, so
+// anything derived from cached templates can be discarded along with them
+func (c *TemplateCache) Subscribe(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.subscribers = append(c.subscribers, fn)
+}
+
+// Invalidate discards every cached template
+func (c *TemplateCache) Invalidate() {
+	c.mu.Lock()
+	c.templates = make(map[templateCacheKey]*list.Element)
+	c.recent.Init()
+	c.generation++
+	subscribers := c.subscribers
+	c.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn()
+	}
+}
+
+// Listen invalidates the cache whenever any replica changes a template, until the
+// context is cancelled. Notifications can be missed while disconnected, so the
+// cache is also invalidated each time the listener connects.
+func (c *TemplateCache) Listen(ctx context.Context) error {
+	for {
+		err := c.listen(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		slog.Warn("Template change listener disconnected", "error", err)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+// listen holds a dedicated connection listening for template changes
+func (c *TemplateCache) listen(ctx context.Context) error {
+	pooled, err := c.db.Pool().Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	// LISTEN is session state, so the connection is taken out of the pool for good
+	conn := pooled.Hijack()
+	defer func() { _ = conn.Close(context.Background()) }()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+templatesChannel); err != nil {
+		return fmt.Errorf("failed to listen for template changes: %w", err)
+	}
+
+	c.Invalidate()
+
+	for {
+		if _, err := conn.WaitForNotification(ctx); err != nil {
+			return fmt.Errorf("failed to wait for template changes: %w", err)
+		}
+		c.Invalidate()
+	}
+}
+
+// get returns a cached template, calling load and caching its result on a miss.
+// Errors are not cached.
+func (c *TemplateCache) get(key templateCacheKey, load func() (*email.Template, error)) (*email.Template, error) {
+	c.mu.Lock()
+	if elem, ok := c.templates[key]; ok {
+		c.recent.MoveToFront(elem)
+		c.mu.Unlock()
+		return elem.Value.(*templateCacheEntry).template, nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	template, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The template may have changed while it was loading, or another lookup may
+	// have cached it first
+	if _, ok := c.templates[key]; ok || c.generation != generation {
+		return template, nil
+	}
+
+	c.templates[key] = c.recent.PushFront(&templateCacheEntry{key: key, template: template})
+	if c.recent.Len() > c.capacity {
+		oldest := c.recent.Remove(c.recent.Back()).(*templateCacheEntry)
+		delete(c.templates, oldest.key)
+	}
+
+	return template, nil
+}
@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+func TestTemplateCache_Eviction(t *testing.T) {
+	t.Parallel()
+
+	cache := NewTemplateCache(nil)
+	cache.capacity = 2
+
+	loads := 0
+	get := func(version int32) *email.Template {
+		t.Helper()
+		template, err := cache.get(templateCacheKey{name: "welcome", version: version}, func() (*email.Template, error) {
+			loads++
+			return &email.Template{Name: "welcome", Version: version}, nil
+		})
+		require.NoError(t, err)
+		return template
+	}
+
+	get(1)
+	get(2)
+	assert.Equal(t, 2, loads)
+
+	// Using version 1 keeps it over version 2 when version 3 needs the space
+	assert.Equal(t, int32(1), get(1).Version)
+	get(3)
+	assert.Equal(t, 3, loads)
+
+	get(1)
+	assert.Equal(t, 3, loads, "recently used version 1 should still be cached")
+	get(2)
+	assert.Equal(t, 4, loads, "least recently used version 2 should have been evicted")
+	assert.Len(t, cache.templates, 2)
+
+	cache.Invalidate()
+	assert.Empty(t, cache.templates)
+	assert.Zero(t, cache.recent.Len())
+}
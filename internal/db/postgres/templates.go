@@ -2,14 +2,20 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/travisbale/mailman/internal/db/postgres/internal/sqlc"
 	"github.com/travisbale/mailman/internal/email"
 )
 
-// TemplatesDB handles database operations for email templates
+// defaultSenderConstraint is Postgres' generated name for the default_sender foreign key
+const defaultSenderConstraint = "email_templates_default_sender_fkey"
+
+// TemplatesDB handles database operations for email templates. Every operation is
+// scoped to the tenant of its context, see email.WithTenant.
 type TemplatesDB struct {
 	db *DB
 }
@@ -19,32 +25,167 @@ func NewTemplatesDB(db *DB) *TemplatesDB {
 	return &TemplatesDB{db: db}
 }
 
-// GetTemplate retrieves a template by its name
-func (r *TemplatesDB) GetTemplate(ctx context.Context, name string) (*email.Template, error) {
+// GetTemplate retrieves the first variant of a template found in the locale's fallback
+// chain, with the bases it inherits from resolved the same way in a single query
+func (r *TemplatesDB) GetTemplate(ctx context.Context, name, locale string) (*email.Template, error) {
 	var template *email.Template
 
 	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
-		dbTemplate, err := q.GetTemplate(ctx, name)
+		var err error
+		template, err = resolveTemplateChain(ctx, q, name, locale)
+		return err
+	})
+
+	return template, err
+}
+
+// GetTemplateVersion retrieves a specific published revision of the variant of a
+// template that GetTemplate would return for the locale. Its bases are the
+// revisions of their default variants it was published with.
+func (r *TemplatesDB) GetTemplateVersion(ctx context.Context, name, locale string, version int32) (*email.Template, error) {
+	var template *email.Template
+
+	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		current, err := q.ResolveTemplate(ctx, sqlc.ResolveTemplateParams{
+			TenantID: email.TenantFromContext(ctx),
+			Name:     name,
+			Locales:  email.LocaleFallbacks(locale),
+		})
 		if err != nil {
-			if err == pgx.ErrNoRows {
+			if errors.Is(err, pgx.ErrNoRows) {
 				return fmt.Errorf("%w: %s", email.ErrTemplateNotFound, name)
 			}
 			return fmt.Errorf("failed to get template: %w", err)
 		}
 
-		template = convertTemplateToDomain(dbTemplate)
-		return nil
+		dbVersion, err := q.GetTemplateVersion(ctx, sqlc.GetTemplateVersionParams{
+			TenantID:     email.TenantFromContext(ctx),
+			TemplateName: name,
+			Locale:       current.Locale,
+			Version:      version,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: %s version %d", email.ErrTemplateNotFound, email.DescribeTemplate(name, current.Locale), version)
+			}
+			return fmt.Errorf("failed to get template version: %w", err)
+		}
+
+		template, err = resolveVersionChain(ctx, q, dbVersion, locale)
+		return err
 	})
 
 	return template, err
 }
 
-// List retrieves all email templates, optionally filtered by name
+// resolveVersionChain loads a published revision and the revisions of the bases
+// it was published with, linked through Template.Base. Revisions recorded before
+// base versions were kept use their bases' current variants for the locale.
+func resolveVersionChain(ctx context.Context, q *sqlc.Queries, dbVersion sqlc.TemplateVersion, locale string) (*email.Template, error) {
+	template := convertTemplateVersionToDomain(dbVersion)
+	seen := map[string]bool{dbVersion.TemplateName: true}
+
+	for child := template; child.BaseTemplateName != nil; child = child.Base {
+		baseName := *child.BaseTemplateName
+
+		if dbVersion.BaseTemplateVersion == nil {
+			base, err := resolveTemplateChain(ctx, q, baseName, locale)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load base template %s: %w", baseName, err)
+			}
+			child.Base = base
+			break
+		}
+
+		// History can't form a cycle, but a corrupted one shouldn't hang the send
+		if seen[baseName] {
+			return nil, fmt.Errorf("%w: %s", email.ErrCircularReference, baseName)
+		}
+		seen[baseName] = true
+
+		// Templates only inherit from the default variant of their base
+		baseVersion := *dbVersion.BaseTemplateVersion
+		var err error
+		dbVersion, err = q.GetTemplateVersion(ctx, sqlc.GetTemplateVersionParams{
+			TenantID:     dbVersion.TenantID,
+			TemplateName: baseName,
+			Locale:       "",
+			Version:      baseVersion,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, fmt.Errorf("%w: base template %s version %d", email.ErrTemplateNotFound, baseName, baseVersion)
+			}
+			return nil, fmt.Errorf("failed to get template version: %w", err)
+		}
+
+		child.Base = convertTemplateVersionToDomain(dbVersion)
+	}
+
+	return template, nil
+}
+
+// resolveTemplateChain loads a template and its bases, linked through Template.Base
+func resolveTemplateChain(ctx context.Context, q *sqlc.Queries, name, locale string) (*email.Template, error) {
+	rows, err := q.ResolveTemplateChain(ctx, sqlc.ResolveTemplateChainParams{
+		TenantID: email.TenantFromContext(ctx),
+		Name:     name,
+		Locales:  email.LocaleFallbacks(locale),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: %s", email.ErrTemplateNotFound, name)
+	}
+
+	// Link from the root base down so each template points at its resolved base
+	var template *email.Template
+	for i := len(rows) - 1; i >= 0; i-- {
+		child := convertTemplateToDomain(sqlc.EmailTemplate(rows[i]))
+		child.Base = template
+		template = child
+	}
+
+	return template, nil
+}
+
+// ListVersions retrieves every published revision of one variant of a template, newest first
+func (r *TemplatesDB) ListVersions(ctx context.Context, name, locale string) ([]*email.Template, error) {
+	var templates []*email.Template
+
+	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		dbVersions, err := q.ListTemplateVersions(ctx, sqlc.ListTemplateVersionsParams{
+			TenantID:     email.TenantFromContext(ctx),
+			TemplateName: name,
+			Locale:       locale,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list template versions: %w", err)
+		}
+
+		if len(dbVersions) == 0 {
+			return fmt.Errorf("%w: %s", email.ErrTemplateNotFound, email.DescribeTemplate(name, locale))
+		}
+
+		templates = make([]*email.Template, len(dbVersions))
+		for i := range dbVersions {
+			templates[i] = convertTemplateVersionToDomain(dbVersions[i])
+		}
+
+		return nil
+	})
+
+	return templates, err
+}
+
+// List retrieves every variant of every email template of the context's tenant
 func (r *TemplatesDB) List(ctx context.Context) ([]*email.Template, error) {
 	var templates []*email.Template
 
 	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
-		dbTemplates, err := q.ListTemplates(ctx)
+		dbTemplates, err := q.ListTemplates(ctx, email.TenantFromContext(ctx))
 		if err != nil {
 			return fmt.Errorf("failed to list templates: %w", err)
 		}
@@ -60,23 +201,36 @@ func (r *TemplatesDB) List(ctx context.Context) ([]*email.Template, error) {
 	return templates, err
 }
 
-// Create inserts a new email template
+// Create inserts a new email template for the context's tenant. Its version
+// follows on from any deleted template of the same name and locale.
 func (r *TemplatesDB) Create(ctx context.Context, template *email.Template) (*email.Template, error) {
+	template.TenantID = email.TenantFromContext(ctx)
+
 	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
 		dbTemplate, err := q.CreateTemplate(ctx, sqlc.CreateTemplateParams{
-			Name:             template.Name,
-			Subject:          template.Subject,
-			HtmlBody:         template.HTMLBody,
-			TextBody:         template.TextBody,
-			BaseTemplateName: template.BaseTemplateName,
-			Variables:        template.Variables,
-			Version:          template.Version,
+			TenantID:          template.TenantID,
+			Name:              template.Name,
+			Locale:            template.Locale,
+			Subject:           template.Subject,
+			HtmlBody:          template.HTMLBody,
+			TextBody:          template.TextBody,
+			BaseTemplateName:  template.BaseTemplateName,
+			Variables:         template.Variables,
+			DefaultSender:     template.DefaultSender,
+			SuppressionPolicy: template.SuppressionPolicy,
 		})
 
 		if err != nil {
+			switch {
+			case isUniqueViolation(err):
+				return fmt.Errorf("%w: %s", email.ErrTemplateExists, email.DescribeTemplate(template.Name, template.Locale))
+			case isForeignKeyViolation(err):
+				return templateReferenceError(err, template)
+			}
 			return fmt.Errorf("failed to create template: %w", err)
 		}
 
+		template.Version = dbTemplate.Version
 		template.CreatedAt = dbTemplate.CreatedAt
 		template.UpdatedAt = dbTemplate.UpdatedAt
 
@@ -86,17 +240,143 @@ func (r *TemplatesDB) Create(ctx context.Context, template *email.Template) (*em
 	return template, err
 }
 
+// Update replaces a template's content if its version still matches template.Version,
+// incrementing the version on success
+func (r *TemplatesDB) Update(ctx context.Context, template *email.Template) (*email.Template, error) {
+	var updated *email.Template
+
+	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		dbTemplate, err := q.UpdateTemplate(ctx, sqlc.UpdateTemplateParams{
+			TenantID:          email.TenantFromContext(ctx),
+			Name:              template.Name,
+			Locale:            template.Locale,
+			Subject:           template.Subject,
+			HtmlBody:          template.HTMLBody,
+			TextBody:          template.TextBody,
+			BaseTemplateName:  template.BaseTemplateName,
+			Variables:         template.Variables,
+			DefaultSender:     template.DefaultSender,
+			SuppressionPolicy: template.SuppressionPolicy,
+			Version:           template.Version,
+		})
+
+		if err != nil {
+			switch {
+			case errors.Is(err, pgx.ErrNoRows):
+				return versionMismatchError(ctx, q, template.Name, template.Locale, template.Version)
+			case isForeignKeyViolation(err):
+				return templateReferenceError(err, template)
+			}
+			return fmt.Errorf("failed to update template: %w", err)
+		}
+
+		updated = convertTemplateToDomain(dbTemplate)
+		return nil
+	})
+
+	return updated, err
+}
+
+// Delete removes one variant of a template at the given version, keeping its
+// published revisions. The default variant cannot be deleted while other
+// templates inherit from it, since bases fall back to it.
+func (r *TemplatesDB) Delete(ctx context.Context, name, locale string, version int32) error {
+	return r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		if locale == "" {
+			dependents, err := q.ListDependentTemplates(ctx, sqlc.ListDependentTemplatesParams{
+				TenantID:         email.TenantFromContext(ctx),
+				BaseTemplateName: &name,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to list dependent templates: %w", err)
+			}
+
+			if len(dependents) > 0 {
+				return fmt.Errorf("%w: %s is the base of %s", email.ErrTemplateInUse, name, strings.Join(dependents, ", "))
+			}
+		}
+
+		rows, err := q.DeleteTemplate(ctx, sqlc.DeleteTemplateParams{
+			TenantID: email.TenantFromContext(ctx),
+			Name:     name,
+			Locale:   locale,
+			Version:  version,
+		})
+		if err != nil {
+			// A dependent created concurrently still trips the foreign key
+			if isForeignKeyViolation(err) {
+				return fmt.Errorf("%w: %s", email.ErrTemplateInUse, name)
+			}
+			return fmt.Errorf("failed to delete template: %w", err)
+		}
+
+		if rows == 0 {
+			return versionMismatchError(ctx, q, name, locale, version)
+		}
+
+		return nil
+	})
+}
+
+// versionMismatchError explains why a versioned write matched no rows
+func versionMismatchError(ctx context.Context, q *sqlc.Queries, name, locale string, expected int32) error {
+	current, err := q.GetTemplate(ctx, sqlc.GetTemplateParams{
+		TenantID: email.TenantFromContext(ctx),
+		Name:     name,
+		Locale:   locale,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %s", email.ErrTemplateNotFound, email.DescribeTemplate(name, locale))
+		}
+		return fmt.Errorf("failed to get template: %w", err)
+	}
+
+	return fmt.Errorf("%w: %s is at version %d, not %d", email.ErrVersionConflict, email.DescribeTemplate(name, locale), current.Version, expected)
+}
+
+// templateReferenceError explains which of a template's references does not exist
+func templateReferenceError(err error, template *email.Template) error {
+	if violatedConstraint(err) == defaultSenderConstraint {
+		return fmt.Errorf("%w: %s", email.ErrSenderNotAllowed, *template.DefaultSender)
+	}
+	return fmt.Errorf("%w: base template %s", email.ErrTemplateNotFound, *template.BaseTemplateName)
+}
+
 // convertTemplateToDomain converts a sqlc Template to a domain Template
 func convertTemplateToDomain(dbTemplate sqlc.EmailTemplate) *email.Template {
 	return &email.Template{
-		Name:             dbTemplate.Name,
-		Subject:          dbTemplate.Subject,
-		HTMLBody:         dbTemplate.HtmlBody,
-		TextBody:         dbTemplate.TextBody,
-		BaseTemplateName: dbTemplate.BaseTemplateName,
-		Variables:        dbTemplate.Variables,
-		Version:          dbTemplate.Version,
-		CreatedAt:        dbTemplate.CreatedAt,
-		UpdatedAt:        dbTemplate.UpdatedAt,
+		TenantID:          dbTemplate.TenantID,
+		Name:              dbTemplate.Name,
+		Locale:            dbTemplate.Locale,
+		Subject:           dbTemplate.Subject,
+		HTMLBody:          dbTemplate.HtmlBody,
+		TextBody:          dbTemplate.TextBody,
+		BaseTemplateName:  dbTemplate.BaseTemplateName,
+		Variables:         dbTemplate.Variables,
+		DefaultSender:     dbTemplate.DefaultSender,
+		SuppressionPolicy: dbTemplate.SuppressionPolicy,
+		Version:           dbTemplate.Version,
+		CreatedAt:         dbTemplate.CreatedAt,
+		UpdatedAt:         dbTemplate.UpdatedAt,
+	}
+}
+
+// convertTemplateVersionToDomain converts a sqlc TemplateVersion to a domain Template
+func convertTemplateVersionToDomain(dbVersion sqlc.TemplateVersion) *email.Template {
+	return &email.Template{
+		TenantID:          dbVersion.TenantID,
+		Name:              dbVersion.TemplateName,
+		Locale:            dbVersion.Locale,
+		Subject:           dbVersion.Subject,
+		HTMLBody:          dbVersion.HtmlBody,
+		TextBody:          dbVersion.TextBody,
+		BaseTemplateName:  dbVersion.BaseTemplateName,
+		Variables:         dbVersion.Variables,
+		DefaultSender:     dbVersion.DefaultSender,
+		SuppressionPolicy: dbVersion.SuppressionPolicy,
+		Version:           dbVersion.Version,
+		CreatedAt:         dbVersion.CreatedAt,
+		UpdatedAt:         dbVersion.CreatedAt, // Revisions are immutable
 	}
 }
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/travisbale/mailman/internal/db/postgres/internal/sqlc"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// TenantsDB handles database operations for tenants
+type TenantsDB struct {
+	db *DB
+}
+
+// NewTenantsDB creates a new tenants database adapter
+func NewTenantsDB(db *DB) *TenantsDB {
+	return &TenantsDB{db: db}
+}
+
+// GetTenant retrieves a tenant by ID
+func (r *TenantsDB) GetTenant(ctx context.Context, id string) (*email.Tenant, error) {
+	var tenant *email.Tenant
+
+	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		dbTenant, err := q.GetTenant(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: %s", email.ErrTenantNotFound, id)
+			}
+			return fmt.Errorf("failed to get tenant: %w", err)
+		}
+
+		tenant, err = convertTenantToDomain(dbTenant)
+		return err
+	})
+
+	return tenant, err
+}
+
+// ListTenants retrieves every tenant
+func (r *TenantsDB) ListTenants(ctx context.Context) ([]*email.Tenant, error) {
+	var tenants []*email.Tenant
+
+	err := r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		dbTenants, err := q.ListTenants(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list tenants: %w", err)
+		}
+
+		tenants = make([]*email.Tenant, len(dbTenants))
+		for i := range dbTenants {
+			if tenants[i], err = convertTenantToDomain(dbTenants[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return tenants, err
+}
+
+// CreateTenant stores a new tenant
+func (r *TenantsDB) CreateTenant(ctx context.Context, tenant *email.Tenant) (*email.Tenant, error) {
+	var created *email.Tenant
+
+	credentials, err := marshalProviderCredentials(tenant.Credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		dbTenant, err := q.CreateTenant(ctx, sqlc.CreateTenantParams{
+			ID:                  tenant.ID,
+			Name:                tenant.Name,
+			FromAddress:         tenant.FromAddress,
+			FromName:            tenant.FromName,
+			Provider:            tenant.Provider,
+			ProviderCredentials: credentials,
+			ProviderFallback:    tenant.ProviderFallback,
+		})
+		if err != nil {
+			if isUniqueViolation(err) {
+				return fmt.Errorf("%w: %s", email.ErrTenantExists, tenant.ID)
+			}
+			return fmt.Errorf("failed to create tenant: %w", err)
+		}
+
+		created, err = convertTenantToDomain(dbTenant)
+		return err
+	})
+
+	return created, err
+}
+
+// UpdateTenant replaces a tenant's name and sending defaults
+func (r *TenantsDB) UpdateTenant(ctx context.Context, tenant *email.Tenant) (*email.Tenant, error) {
+	var updated *email.Tenant
+
+	credentials, err := marshalProviderCredentials(tenant.Credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.WithTransaction(ctx, func(q *sqlc.Queries) error {
+		dbTenant, err := q.UpdateTenant(ctx, sqlc.UpdateTenantParams{
+			ID:                  tenant.ID,
+			Name:                tenant.Name,
+			FromAddress:         tenant.FromAddress,
+			FromName:            tenant.FromName,
+			Provider:            tenant.Provider,
+			ProviderCredentials: credentials,
+			ProviderFallback:    tenant.ProviderFallback,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: %s", email.ErrTenantNotFound, tenant.ID)
+			}
+			return fmt.Errorf("failed to update tenant: %w", err)
+		}
+
+		updated, err = convertTenantToDomain(dbTenant)
+		return err
+	})
+
+	return updated, err
+}
+
+// providerCredentials is how a tenant's provider credentials are stored
+type providerCredentials struct {
+	SendGridAPIKey     string `json:"sendgrid_api_key,omitempty"`
+	SendGridWebhookKey string `json:"sendgrid_webhook_key,omitempty"`
+	SMTPHost           string `json:"smtp_host,omitempty"`
+	SMTPPort           int    `json:"smtp_port,omitempty"`
+	SMTPUsername       string `json:"smtp_username,omitempty"`
+	SMTPPassword       string `json:"smtp_password,omitempty"`
+	SMTPTLSMode        string `json:"smtp_tls_mode,omitempty"`
+	SMTPAuth           string `json:"smtp_auth,omitempty"`
+}
+
+// marshalProviderCredentials encodes a tenant's provider credentials, storing nil
+// as NULL
+func marshalProviderCredentials(credentials *email.ProviderCredentials) ([]byte, error) {
+	if credentials == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(providerCredentials(*credentials))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode provider credentials: %w", err)
+	}
+	return data, nil
+}
+
+// convertTenantToDomain converts a sqlc Tenant to a domain Tenant
+func convertTenantToDomain(dbTenant sqlc.Tenant) (*email.Tenant, error) {
+	tenant := &email.Tenant{
+		ID:               dbTenant.ID,
+		Name:             dbTenant.Name,
+		FromAddress:      dbTenant.FromAddress,
+		FromName:         dbTenant.FromName,
+		Provider:         dbTenant.Provider,
+		ProviderFallback: dbTenant.ProviderFallback,
+		CreatedAt:        dbTenant.CreatedAt,
+	}
+
+	if dbTenant.ProviderCredentials != nil {
+		var credentials providerCredentials
+		if err := json.Unmarshal(dbTenant.ProviderCredentials, &credentials); err != nil {
+			return nil, fmt.Errorf("failed to decode provider credentials for tenant %s: %w", dbTenant.ID, err)
+		}
+		tenant.Credentials = (*email.ProviderCredentials)(&credentials)
+	}
+
+	return tenant, nil
+}
@@ -0,0 +1,96 @@
+package email
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// Address is an email address with an optional display name
+type Address struct {
+	Name  string
+	Email string
+}
+
+// String formats the address for headers, omitting the angle brackets when
+// there is no display name
+func (a Address) String() string {
+	if a.Name == "" {
+		return a.Email
+	}
+	return (&mail.Address{Name: a.Name, Address: a.Email}).String()
+}
+
+// ParseAddress parses an RFC 5322 address such as "Jane Doe <jane@example.com>"
+func ParseAddress(s string) (Address, error) {
+	addr, err := mail.ParseAddress(s)
+	if err != nil {
+		return Address{}, fmt.Errorf("%w %q: %v", ErrInvalidAddress, s, err)
+	}
+	return Address{Name: addr.Name, Email: addr.Address}, nil
+}
+
+// ParseAddresses parses each entry of a recipient list
+func ParseAddresses(list []string) (AddressList, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	addresses := make(AddressList, len(list))
+	for i, s := range list {
+		addr, err := ParseAddress(s)
+		if err != nil {
+			return nil, err
+		}
+		addresses[i] = addr
+	}
+	return addresses, nil
+}
+
+// AddressList is a list of recipients
+type AddressList []Address
+
+// Emails returns the bare addresses, as used for the SMTP envelope
+func (l AddressList) Emails() []string {
+	emails := make([]string, len(l))
+	for i, a := range l {
+		emails[i] = a.Email
+	}
+	return emails
+}
+
+// Strings formats each address as String does
+func (l AddressList) Strings() []string {
+	parts := make([]string, len(l))
+	for i, a := range l {
+		parts[i] = a.String()
+	}
+	return parts
+}
+
+// String formats the list as a header value
+func (l AddressList) String() string {
+	return strings.Join(l.Strings(), ", ")
+}
+
+// UnmarshalJSON also accepts the single address string that jobs enqueued
+// before multiple recipients were supported stored in their args
+func (l *AddressList) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = AddressList{{Email: single}}
+		return nil
+	}
+
+	var list []Address
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
@@ -0,0 +1,50 @@
+package email_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+func TestParseAddresses(t *testing.T) {
+	t.Parallel()
+
+	list, err := email.ParseAddresses([]string{"Jane Doe <jane@example.com>", "bob@example.com"})
+	require.NoError(t, err)
+
+	assert.Equal(t, email.AddressList{
+		{Name: "Jane Doe", Email: "jane@example.com"},
+		{Email: "bob@example.com"},
+	}, list)
+	assert.Equal(t, `"Jane Doe" <jane@example.com>, bob@example.com`, list.String())
+	assert.Equal(t, []string{"jane@example.com", "bob@example.com"}, list.Emails())
+}
+
+func TestParseAddresses_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := email.ParseAddresses([]string{"bob@example.com", "not an address"})
+	require.ErrorIs(t, err, email.ErrInvalidAddress)
+	assert.Contains(t, err.Error(), "not an address")
+}
+
+func TestAddressList_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("list", func(t *testing.T) {
+		t.Parallel()
+		var args email.JobArgs
+		require.NoError(t, json.Unmarshal([]byte(`{"To":[{"Name":"Jane","Email":"jane@example.com"}]}`), &args))
+		assert.Equal(t, email.AddressList{{Name: "Jane", Email: "jane@example.com"}}, args.To)
+	})
+
+	t.Run("legacy single address", func(t *testing.T) {
+		t.Parallel()
+		var args email.JobArgs
+		require.NoError(t, json.Unmarshal([]byte(`{"To":"jane@example.com"}`), &args))
+		assert.Equal(t, email.AddressList{{Email: "jane@example.com"}}, args.To)
+	})
+}
@@ -0,0 +1,168 @@
+package email
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Scopes an API key can be granted
+const (
+	ScopeSend              = "send"               // Send emails and manage them once queued
+	ScopeTemplatesRead     = "templates:read"     // List, fetch and preview templates
+	ScopeTemplatesWrite    = "templates:write"    // Create, update and delete templates
+	ScopeSuppressionsWrite = "suppressions:write" // Add and lift suppressions
+	ScopeJobsRead          = "jobs:read"          // Inspect queued, sent and failed email jobs
+	ScopeJobsWrite         = "jobs:write"         // Retry, cancel and discard email jobs in bulk
+)
+
+// Scopes lists every scope an API key can be granted
+var Scopes = []string{ScopeSend, ScopeTemplatesRead, ScopeTemplatesWrite, ScopeSuppressionsWrite, ScopeJobsRead, ScopeJobsWrite}
+
+// apiKeyPrefix marks mailman keys so they are recognizable, e.g. by secret scanners
+const apiKeyPrefix = "mm_"
+
+type apiKeyDB interface {
+	GetAPIKey(ctx context.Context, id string) (*APIKey, error)
+	GetAPIKeyByClientIdentity(ctx context.Context, identity string) (*APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]*APIKey, error)
+	CreateAPIKey(ctx context.Context, key *APIKey) (*APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+}
+
+// APIKeyService issues, revokes and checks the API keys services authenticate with
+type APIKeyService struct {
+	db apiKeyDB
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(db apiKeyDB) *APIKeyService {
+	return &APIKeyService{
+		db: db,
+	}
+}
+
+// CreateAPIKey issues a key with the given scopes for the context's tenant. The
+// returned token is the only copy of the full key; only a hash of its secret is
+// stored. Calls made with a client certificate issued to clientIdentity, if it
+// is set, also authenticate as the key.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, name, clientIdentity string, scopes []string) (*APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", errors.New("name is required")
+	}
+
+	if len(scopes) == 0 {
+		return nil, "", fmt.Errorf("%w: at least one scope is required", ErrInvalidScope)
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(Scopes, scope) {
+			return nil, "", fmt.Errorf("%w: %q, expected one of %s", ErrInvalidScope, scope, strings.Join(Scopes, ", "))
+		}
+	}
+
+	id, err := randomString(8, hex.EncodeToString)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := &APIKey{
+		ID:         id,
+		TenantID:   TenantFromContext(ctx),
+		Name:       name,
+		SecretHash: hashSecret(secret),
+		Scopes:     slices.Compact(slices.Sorted(slices.Values(scopes))),
+	}
+	if clientIdentity = strings.TrimSpace(clientIdentity); clientIdentity != "" {
+		key.ClientIdentity = &clientIdentity
+	}
+
+	key, err = s.db.CreateAPIKey(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return key, apiKeyPrefix + id + "_" + secret, nil
+}
+
+// ListAPIKeys returns every key, including revoked ones
+func (s *APIKeyService) ListAPIKeys(ctx context.Context) ([]*APIKey, error) {
+	return s.db.ListAPIKeys(ctx)
+}
+
+// RevokeAPIKey permanently stops a key from authenticating
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id string) error {
+	return s.db.RevokeAPIKey(ctx, id)
+}
+
+// Authenticate returns the unrevoked key a token belongs to. Every kind of
+// failure returns ErrInvalidAPIKey so callers can't probe which keys exist.
+func (s *APIKeyService) Authenticate(ctx context.Context, token string) (*APIKey, error) {
+	id, secret, ok := strings.Cut(strings.TrimPrefix(token, apiKeyPrefix), "_")
+	if !ok || !strings.HasPrefix(token, apiKeyPrefix) || id == "" || secret == "" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.db.GetAPIKey(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrAPIKeyNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare(key.SecretHash, hashSecret(secret)) != 1 || key.RevokedAt != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	return key, nil
+}
+
+// AuthenticateClient returns the unrevoked key bound to a verified client
+// certificate's identity, or ErrInvalidAPIKey if there is none
+func (s *APIKeyService) AuthenticateClient(ctx context.Context, identity string) (*APIKey, error) {
+	if identity == "" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.db.GetAPIKeyByClientIdentity(ctx, identity)
+	if err != nil {
+		if errors.Is(err, ErrAPIKeyNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if key.RevokedAt != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	return key, nil
+}
+
+// hashSecret hashes a key's secret for storage. Secrets are long and random, so
+// a fast hash is enough; a password hash would only slow every request down.
+func hashSecret(secret string) []byte {
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
+
+// randomString encodes n cryptographically random bytes
+func randomString(n int, encode func([]byte) string) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	return encode(b), nil
+}
@@ -0,0 +1,134 @@
+package email_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// fakeAPIKeyDB is an in-memory key store keyed by ID.
+type fakeAPIKeyDB struct {
+	keys map[string]*email.APIKey
+}
+
+func (f *fakeAPIKeyDB) GetAPIKey(_ context.Context, id string) (*email.APIKey, error) {
+	key, ok := f.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", email.ErrAPIKeyNotFound, id)
+	}
+	return key, nil
+}
+
+func (f *fakeAPIKeyDB) GetAPIKeyByClientIdentity(_ context.Context, identity string) (*email.APIKey, error) {
+	for _, key := range f.keys {
+		if key.ClientIdentity != nil && *key.ClientIdentity == identity && key.RevokedAt == nil {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: client identity %s", email.ErrAPIKeyNotFound, identity)
+}
+
+func (f *fakeAPIKeyDB) ListAPIKeys(_ context.Context) ([]*email.APIKey, error) {
+	panic("not implemented")
+}
+
+func (f *fakeAPIKeyDB) CreateAPIKey(_ context.Context, key *email.APIKey) (*email.APIKey, error) {
+	f.keys[key.ID] = key
+	return key, nil
+}
+
+func (f *fakeAPIKeyDB) RevokeAPIKey(_ context.Context, id string) error {
+	now := time.Now()
+	f.keys[id].RevokedAt = &now
+	return nil
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	svc := email.NewAPIKeyService(&fakeAPIKeyDB{keys: make(map[string]*email.APIKey)})
+
+	created, token, err := svc.CreateAPIKey(ctx, "billing-service", "", []string{email.ScopeTemplatesRead, email.ScopeSend, email.ScopeSend})
+	require.NoError(t, err)
+	assert.Equal(t, []string{email.ScopeSend, email.ScopeTemplatesRead}, created.Scopes)
+	assert.True(t, strings.HasPrefix(token, "mm_"+created.ID+"_"))
+	assert.NotContains(t, string(created.SecretHash), strings.TrimPrefix(token, "mm_"+created.ID+"_"))
+
+	key, err := svc.Authenticate(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, "billing-service", key.Name)
+	assert.True(t, key.HasScope(email.ScopeSend))
+	assert.False(t, key.HasScope(email.ScopeTemplatesWrite))
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "wrong secret", token: "mm_" + created.ID + "_wrong"},
+		{name: "unknown id", token: "mm_0000000000000000_secret"},
+		{name: "missing prefix", token: strings.TrimPrefix(token, "mm_")},
+		{name: "missing secret", token: "mm_" + created.ID},
+		{name: "empty", token: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Authenticate(ctx, tt.token)
+			assert.ErrorIs(t, err, email.ErrInvalidAPIKey)
+		})
+	}
+
+	require.NoError(t, svc.RevokeAPIKey(ctx, created.ID))
+	_, err = svc.Authenticate(ctx, token)
+	assert.ErrorIs(t, err, email.ErrInvalidAPIKey)
+}
+
+func TestAPIKeyService_CreateAPIKey_InvalidScope(t *testing.T) {
+	t.Parallel()
+
+	svc := email.NewAPIKeyService(&fakeAPIKeyDB{keys: make(map[string]*email.APIKey)})
+
+	_, _, err := svc.CreateAPIKey(context.Background(), "billing-service", "", []string{"admin"})
+	assert.ErrorIs(t, err, email.ErrInvalidScope)
+
+	_, _, err = svc.CreateAPIKey(context.Background(), "billing-service", "", nil)
+	assert.ErrorIs(t, err, email.ErrInvalidScope)
+}
+
+func TestAPIKeyService_AuthenticateClient(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	svc := email.NewAPIKeyService(&fakeAPIKeyDB{keys: make(map[string]*email.APIKey)})
+
+	created, _, err := svc.CreateAPIKey(ctx, "billing-service", " spiffe://example.com/billing ", []string{email.ScopeSend})
+	require.NoError(t, err)
+	require.NotNil(t, created.ClientIdentity)
+	assert.Equal(t, "spiffe://example.com/billing", *created.ClientIdentity)
+
+	key, err := svc.AuthenticateClient(ctx, "spiffe://example.com/billing")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, key.ID)
+
+	_, err = svc.AuthenticateClient(ctx, "spiffe://example.com/payroll")
+	assert.ErrorIs(t, err, email.ErrInvalidAPIKey)
+
+	_, err = svc.AuthenticateClient(ctx, "")
+	assert.ErrorIs(t, err, email.ErrInvalidAPIKey)
+
+	// Keys issued without an identity can only be used with their token
+	unbound, _, err := svc.CreateAPIKey(ctx, "payroll-service", "", []string{email.ScopeSend})
+	require.NoError(t, err)
+	assert.Nil(t, unbound.ClientIdentity)
+
+	require.NoError(t, svc.RevokeAPIKey(ctx, created.ID))
+	_, err = svc.AuthenticateClient(ctx, "spiffe://example.com/billing")
+	assert.ErrorIs(t, err, email.ErrInvalidAPIKey)
+}
@@ -1,8 +1,59 @@
 package email
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
-	ErrTemplateNotFound = errors.New("template not found")
-	ErrMissingVariable  = errors.New("missing variable")
+	ErrTemplateNotFound   = errors.New("template not found")
+	ErrMissingVariable    = errors.New("missing variable")
+	ErrInvalidLocale      = errors.New("invalid locale")
+	ErrInvalidAddress     = errors.New("invalid email address")
+	ErrMessageNotFound    = errors.New("message not found")
+	ErrMessageNotPending  = errors.New("message is no longer pending")
+	ErrMessageNotFailed   = errors.New("message has not failed")
+	ErrMessageNotFinished = errors.New("message has not failed or been cancelled")
+	ErrInvalidPageToken   = errors.New("invalid page token")
+
+	ErrTemplateExists    = errors.New("template already exists")
+	ErrTemplateInUse     = errors.New("template is in use")
+	ErrVersionConflict   = errors.New("template version conflict")
+	ErrCircularReference = errors.New("circular template reference")
+
+	ErrSenderNotAllowed = errors.New("sender not allowed")
+	ErrSenderNotFound   = errors.New("sender not found")
+	ErrSenderExists     = errors.New("sender already exists")
+	ErrSenderInUse      = errors.New("sender is in use")
+
+	ErrRecipientSuppressed      = errors.New("recipient is suppressed")
+	ErrSuppressionNotFound      = errors.New("suppression not found")
+	ErrInvalidSuppressionReason = errors.New("invalid suppression reason")
+
+	ErrTenantNotFound = errors.New("tenant not found")
+	ErrTenantExists   = errors.New("tenant already exists")
+
+	ErrInvalidAPIKey  = errors.New("invalid API key")
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	ErrInvalidScope   = errors.New("invalid scope")
+
+	ErrClientIdentityInUse = errors.New("client identity is bound to another API key")
+
+	// ErrRejected marks emails a provider refused outright, such as invalid
+	// recipients, which no other provider should be expected to accept
+	ErrRejected = errors.New("email rejected by provider")
 )
+
+// BatchError identifies the email that caused a batch to be rejected
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("email at index %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
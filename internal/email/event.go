@@ -0,0 +1,72 @@
+package email
+
+import (
+	"context"
+)
+
+type deliveryEventDB interface {
+	// RecordEvents stores the events that weren't already recorded, along with
+	// the suppression suppressionFor returns for each of them, if any, in one
+	// transaction
+	RecordEvents(ctx context.Context, events []*DeliveryEvent, suppressionFor func(*DeliveryEvent) *Suppression) error
+	ListEvents(ctx context.Context, messageID string) ([]*DeliveryEvent, error)
+}
+
+// EventService records delivery events reported by email providers
+type EventService struct {
+	db deliveryEventDB
+}
+
+// NewEventService creates a new delivery event recorder
+func NewEventService(db deliveryEventDB) *EventService {
+	return &EventService{
+		db: db,
+	}
+}
+
+// Record stores delivery events and suppresses recipients that hard-bounced,
+// complained or unsubscribed for the tenant that emailed them, or the default
+// tenant if the event names none. Only events not already recorded suppress, so
+// a redelivered or replayed event can't undo a suppression that was since lifted
+// or shortened. Events and their suppressions are stored together, so a batch
+// that fails can be retried as a whole.
+func (s *EventService) Record(ctx context.Context, events []*DeliveryEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	for _, event := range events {
+		event.Recipient = normalizeAddress(event.Recipient)
+	}
+
+	return s.db.RecordEvents(ctx, events, suppressionFor)
+}
+
+// suppressionFor returns the suppression an event calls for, or nil if the
+// recipient may still be emailed
+func suppressionFor(event *DeliveryEvent) *Suppression {
+	reason, ok := suppressionReasonFor(event.Type)
+	if !ok || event.Recipient == "" {
+		return nil
+	}
+
+	return &Suppression{
+		Address: event.Recipient,
+		Reason:  reason,
+		Source:  event.Provider,
+	}
+}
+
+// suppressionReasonFor reports whether an event means the recipient should no longer be emailed
+func suppressionReasonFor(eventType string) (string, bool) {
+	switch eventType {
+	case EventBounced:
+		return SuppressionReasonBounce, true
+	case EventSpamReport:
+		return SuppressionReasonComplaint, true
+	case EventUnsubscribed:
+		return SuppressionReasonUnsubscribe, true
+	default:
+		return "", false
+	}
+}
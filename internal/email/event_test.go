@@ -0,0 +1,86 @@
+package email_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// mockEventDB records events by provider event ID, skipping those already
+// recorded, and the suppressions their recording added.
+type mockEventDB struct {
+	recorded     []*email.DeliveryEvent
+	suppressions []*email.Suppression
+	tenants      []string // Tenant of each suppression
+}
+
+func (m *mockEventDB) RecordEvents(_ context.Context, events []*email.DeliveryEvent, suppressionFor func(*email.DeliveryEvent) *email.Suppression) error {
+	for _, event := range events {
+		if slices.ContainsFunc(m.recorded, func(e *email.DeliveryEvent) bool { return e.ProviderEventID == event.ProviderEventID }) {
+			continue
+		}
+		m.recorded = append(m.recorded, event)
+
+		if suppression := suppressionFor(event); suppression != nil {
+			m.suppressions = append(m.suppressions, suppression)
+			m.tenants = append(m.tenants, event.Tenant)
+		}
+	}
+	return nil
+}
+
+func (m *mockEventDB) ListEvents(_ context.Context, _ string) ([]*email.DeliveryEvent, error) {
+	return m.recorded, nil
+}
+
+func TestEventService_Record(t *testing.T) {
+	t.Parallel()
+
+	events := &mockEventDB{}
+	svc := email.NewEventService(events)
+
+	now := time.Now()
+	err := svc.Record(context.Background(), []*email.DeliveryEvent{
+		{Provider: "sendgrid", ProviderEventID: "1", Type: email.EventDelivered, Recipient: "alice@example.com", OccurredAt: now},
+		{Provider: "sendgrid", ProviderEventID: "2", Type: email.EventBounced, Recipient: "Bob@Example.com", OccurredAt: now},
+		{Provider: "sendgrid", ProviderEventID: "3", Type: email.EventBlocked, Recipient: "carol@example.com", OccurredAt: now},
+		{Provider: "sendgrid", ProviderEventID: "4", Type: email.EventSpamReport, Recipient: "dave@example.com", Tenant: "payroll", OccurredAt: now},
+	})
+	require.NoError(t, err)
+
+	assert.Len(t, events.recorded, 4)
+
+	// Only hard bounces and complaints suppress; soft bounces may still deliver
+	require.Len(t, events.suppressions, 2)
+	assert.Equal(t, &email.Suppression{Address: "bob@example.com", Reason: email.SuppressionReasonBounce, Source: "sendgrid"}, events.suppressions[0])
+	assert.Equal(t, &email.Suppression{Address: "dave@example.com", Reason: email.SuppressionReasonComplaint, Source: "sendgrid"}, events.suppressions[1])
+
+	// Suppressions land on the tenant that sent the email
+	assert.Equal(t, []string{"", "payroll"}, events.tenants)
+
+	// A redelivered bounce doesn't suppress again, so it can't undo a lifted suppression
+	err = svc.Record(context.Background(), []*email.DeliveryEvent{
+		{Provider: "sendgrid", ProviderEventID: "2", Type: email.EventBounced, Recipient: "bob@example.com", OccurredAt: now},
+	})
+	require.NoError(t, err)
+	assert.Len(t, events.suppressions, 2)
+}
+
+func TestService_GetStatus_Events(t *testing.T) {
+	t.Parallel()
+
+	delivered := &email.DeliveryEvent{Provider: "sendgrid", MessageID: "42", Type: email.EventDelivered}
+	svc := &email.Service{
+		Queue:  &mockQueue{status: &email.Status{MessageID: "42", State: email.StateSent}},
+		Events: &mockEventDB{recorded: []*email.DeliveryEvent{delivered}},
+	}
+
+	status, err := svc.GetStatus(context.Background(), "42")
+	require.NoError(t, err)
+	assert.Equal(t, []*email.DeliveryEvent{delivered}, status.Events)
+}
@@ -0,0 +1,134 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// jobPageSize is how many jobs bulk changes load at a time
+const jobPageSize = 100
+
+// States each bulk change applies to. Jobs in other states are left alone.
+var (
+	retryableStates   = []State{StateFailed, StateRetrying}
+	cancellableStates = []State{StateScheduled, StateQueued, StateRetrying}
+	discardableStates = []State{StateFailed, StateCancelled}
+)
+
+// SkippedJob is an email selected by message ID that a change was not applied
+// to, because it was in the wrong state or doesn't exist
+type SkippedJob struct {
+	MessageID string
+	Err       error
+}
+
+// ListJobs returns a page of email jobs matching the filter, newest first, and
+// the token for the next page, which is empty on the last page.
+func (s *Service) ListJobs(ctx context.Context, filter JobFilter, pageSize int, pageToken string) ([]*Job, string, error) {
+	filter.Recipient = normalizeAddress(filter.Recipient)
+	return s.Queue.ListEmailJobs(ctx, filter, pageSize, pageToken)
+}
+
+// GetJob returns an email job with the error from every failed attempt
+func (s *Service) GetJob(ctx context.Context, messageID string) (*Job, error) {
+	return s.Queue.GetEmailJob(ctx, messageID)
+}
+
+// RetryJobs retries every failed or retrying email matching the filter, such as
+// those that ran out of attempts during a provider outage, and returns how many
+// were retried
+func (s *Service) RetryJobs(ctx context.Context, filter JobFilter) (int, error) {
+	return s.eachJob(ctx, filter, retryableStates, s.Queue.RetryEmail, ErrMessageNotFailed)
+}
+
+// RetryMessages makes each listed failed email, or one waiting to retry,
+// available for delivery again straight away, skipping the others, and returns
+// how many were retried. Failed emails are given one more attempt.
+func (s *Service) RetryMessages(ctx context.Context, messageIDs []string) (int, []SkippedJob, error) {
+	return eachMessage(ctx, messageIDs, s.Queue.RetryEmail, ErrMessageNotFailed)
+}
+
+// CancelJobs cancels every pending email matching the filter and returns how
+// many were cancelled
+func (s *Service) CancelJobs(ctx context.Context, filter JobFilter) (int, error) {
+	return s.eachJob(ctx, filter, cancellableStates, s.Queue.CancelEmail, ErrMessageNotPending)
+}
+
+// CancelMessages cancels each listed email, skipping those that are no longer
+// pending, and returns how many were cancelled
+func (s *Service) CancelMessages(ctx context.Context, messageIDs []string) (int, []SkippedJob, error) {
+	return eachMessage(ctx, messageIDs, s.Queue.CancelEmail, ErrMessageNotPending)
+}
+
+// DiscardJobs deletes every failed or cancelled email matching the filter and
+// returns how many were deleted
+func (s *Service) DiscardJobs(ctx context.Context, filter JobFilter) (int, error) {
+	return s.eachJob(ctx, filter, discardableStates, s.Queue.DiscardEmail, ErrMessageNotFinished)
+}
+
+// DiscardMessages deletes each listed failed or cancelled email so it no longer
+// appears in the queue, skipping the others, and returns how many were deleted
+func (s *Service) DiscardMessages(ctx context.Context, messageIDs []string) (int, []SkippedJob, error) {
+	return eachMessage(ctx, messageIDs, s.Queue.DiscardEmail, ErrMessageNotFinished)
+}
+
+// eachMessage applies change to each listed email and returns how many were
+// changed. Emails that fail with stateErr or don't exist are skipped and
+// returned; any other error stops the change, and is returned with how many
+// were changed before it.
+func eachMessage(ctx context.Context, messageIDs []string, change func(context.Context, string) error, stateErr error) (int, []SkippedJob, error) {
+	changed := 0
+	var skipped []SkippedJob
+	for _, messageID := range messageIDs {
+		if err := change(ctx, messageID); err != nil {
+			if errors.Is(err, stateErr) || errors.Is(err, ErrMessageNotFound) {
+				skipped = append(skipped, SkippedJob{MessageID: messageID, Err: err})
+				continue
+			}
+			return changed, skipped, err
+		}
+		changed++
+	}
+	return changed, skipped, nil
+}
+
+// eachJob applies change to every job matching the filter in one of the given
+// states, and returns how many were changed. Jobs that left those states after
+// they were listed fail with stateErr and are skipped.
+func (s *Service) eachJob(ctx context.Context, filter JobFilter, states []State, change func(context.Context, string) error, stateErr error) (int, error) {
+	if len(filter.States) == 0 {
+		filter.States = states
+	} else {
+		filter.States = slices.DeleteFunc(slices.Clone(filter.States), func(state State) bool {
+			return !slices.Contains(states, state)
+		})
+		if len(filter.States) == 0 {
+			return 0, nil
+		}
+	}
+
+	changed := 0
+	pageToken := ""
+	for {
+		jobs, next, err := s.ListJobs(ctx, filter, jobPageSize, pageToken)
+		if err != nil {
+			return changed, err
+		}
+
+		for _, job := range jobs {
+			if err := change(ctx, job.MessageID); err != nil {
+				if errors.Is(err, stateErr) || errors.Is(err, ErrMessageNotFound) {
+					continue
+				}
+				return changed, err
+			}
+			changed++
+		}
+
+		if next == "" {
+			return changed, nil
+		}
+		pageToken = next
+	}
+}
@@ -0,0 +1,126 @@
+package email_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// fakeJobQueue serves jobs in pages of pageSize and records the jobs retried.
+// Jobs listed in stale have changed state since they were listed.
+type fakeJobQueue struct {
+	mockQueue
+	jobs     []*email.Job
+	pageSize int
+	stale    map[string]bool
+	filters  []email.JobFilter
+	retried  []string
+	err      error // returned once the first job has been retried
+}
+
+func (f *fakeJobQueue) ListEmailJobs(_ context.Context, filter email.JobFilter, _ int, pageToken string) ([]*email.Job, string, error) {
+	f.filters = append(f.filters, filter)
+
+	var matching []*email.Job
+	for _, job := range f.jobs {
+		if slices.Contains(filter.States, job.State) {
+			matching = append(matching, job)
+		}
+	}
+
+	start := 0
+	if pageToken != "" {
+		start, _ = strconv.Atoi(pageToken)
+	}
+	end := min(start+f.pageSize, len(matching))
+
+	next := ""
+	if end < len(matching) {
+		next = strconv.Itoa(end)
+	}
+	return matching[start:end], next, nil
+}
+
+func (f *fakeJobQueue) RetryEmail(_ context.Context, messageID string) error {
+	if f.stale[messageID] {
+		return fmt.Errorf("%w: %s", email.ErrMessageNotFailed, messageID)
+	}
+	if f.err != nil && len(f.retried) > 0 {
+		return f.err
+	}
+	f.retried = append(f.retried, messageID)
+	return nil
+}
+
+func TestService_RetryJobs(t *testing.T) {
+	t.Parallel()
+
+	queue := &fakeJobQueue{pageSize: 2, stale: map[string]bool{"3": true}}
+	for i, state := range []email.State{email.StateFailed, email.StateSent, email.StateFailed, email.StateRetrying, email.StateFailed, email.StateFailed} {
+		queue.jobs = append(queue.jobs, &email.Job{Status: email.Status{MessageID: strconv.Itoa(i + 1), State: state}})
+	}
+	svc := &email.Service{Queue: queue}
+
+	retried, err := svc.RetryJobs(context.Background(), email.JobFilter{Recipient: "User@Example.com"})
+	require.NoError(t, err)
+
+	// Every page is retried, skipping the sent job and the one that changed state
+	assert.Equal(t, 4, retried)
+	assert.Equal(t, []string{"1", "4", "5", "6"}, queue.retried)
+	require.NotEmpty(t, queue.filters)
+	assert.Equal(t, []email.State{email.StateFailed, email.StateRetrying}, queue.filters[0].States)
+	assert.Equal(t, "user@example.com", queue.filters[0].Recipient)
+}
+
+func TestService_RetryJobs_States(t *testing.T) {
+	t.Parallel()
+
+	queue := &fakeJobQueue{pageSize: 10, jobs: []*email.Job{
+		{Status: email.Status{MessageID: "1", State: email.StateFailed}},
+		{Status: email.Status{MessageID: "2", State: email.StateRetrying}},
+	}}
+	svc := &email.Service{Queue: queue}
+
+	// Requested states are narrowed to those that can be retried
+	retried, err := svc.RetryJobs(context.Background(), email.JobFilter{States: []email.State{email.StateFailed, email.StateSent}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, retried)
+	assert.Equal(t, []string{"1"}, queue.retried)
+
+	// No eligible states means nothing to retry
+	queue.filters = nil
+	retried, err = svc.RetryJobs(context.Background(), email.JobFilter{States: []email.State{email.StateSent}})
+	require.NoError(t, err)
+	assert.Zero(t, retried)
+	assert.Empty(t, queue.filters)
+}
+
+func TestService_RetryMessages(t *testing.T) {
+	t.Parallel()
+
+	queue := &fakeJobQueue{stale: map[string]bool{"2": true}}
+	svc := &email.Service{Queue: queue}
+
+	// Emails that haven't failed are skipped and reported, and the rest retried
+	retried, skipped, err := svc.RetryMessages(context.Background(), []string{"1", "2", "3"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, retried)
+	assert.Equal(t, []string{"1", "3"}, queue.retried)
+	require.Len(t, skipped, 1)
+	assert.Equal(t, "2", skipped[0].MessageID)
+	assert.ErrorIs(t, skipped[0].Err, email.ErrMessageNotFailed)
+
+	// Any other error stops the retry, reporting the emails retried before it
+	queue.retried = nil
+	queue.err = errors.New("connection reset")
+	retried, _, err = svc.RetryMessages(context.Background(), []string{"1", "4", "5"})
+	require.Error(t, err)
+	assert.Equal(t, 1, retried)
+}
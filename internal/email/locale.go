@@ -0,0 +1,46 @@
+package email
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// NormalizeLocale returns the canonical form of a BCP 47 locale such as fr-CA.
+// The empty locale selects a template's default variant and is returned as is.
+func NormalizeLocale(locale string) (string, error) {
+	if locale == "" {
+		return "", nil
+	}
+
+	tag, err := language.Parse(strings.ReplaceAll(locale, "_", "-"))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidLocale, locale)
+	}
+
+	return tag.String(), nil
+}
+
+// LocaleFallbacks lists the template variants to try for a normalized locale,
+// most specific first: fr-CA falls back to fr and then to the default variant.
+func LocaleFallbacks(locale string) []string {
+	var chain []string
+	for locale != "" {
+		chain = append(chain, locale)
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	return append(chain, "")
+}
+
+// DescribeTemplate names a template variant in messages, e.g. "welcome (fr-CA)"
+func DescribeTemplate(name, locale string) string {
+	if locale == "" {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, locale)
+}
@@ -0,0 +1,48 @@
+package email_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "", want: ""},
+		{input: "fr", want: "fr"},
+		{input: "fr-ca", want: "fr-CA"},
+		{input: "fr_CA", want: "fr-CA"},
+		{input: "zh-hant-tw", want: "zh-Hant-TW"},
+		{input: "not a locale", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := email.NormalizeLocale(tt.input)
+			if tt.wantErr {
+				require.ErrorIs(t, err, email.ErrInvalidLocale)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLocaleFallbacks(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"fr-CA", "fr", ""}, email.LocaleFallbacks("fr-CA"))
+	assert.Equal(t, []string{"zh-Hant-TW", "zh-Hant", "zh", ""}, email.LocaleFallbacks("zh-Hant-TW"))
+	assert.Equal(t, []string{""}, email.LocaleFallbacks(""))
+}
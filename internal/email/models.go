@@ -1,44 +1,277 @@
 package email
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // SendRequest represents a request to send an email, before rendering.
 type SendRequest struct {
-	To           string
-	TemplateName string
-	Variables    map[string]string
-	Priority     int32
-	ScheduledAt  *time.Time
+	Sender          string // Registered sender address; empty uses the template or server default
+	To              AddressList
+	CC              AddressList
+	BCC             AddressList
+	ReplyTo         *Address
+	TemplateName    string
+	TemplateVersion int32          // Pins a published revision; zero renders the latest
+	Locale          string         // Preferred template variant, e.g. fr-CA; empty uses the default
+	Variables       map[string]any // Strings, numbers, booleans, lists and nested maps
+	Attachments     []Attachment
+	Priority        int32
+	ScheduledAt     *time.Time
+	CancellationKey string // Groups emails so they can be cancelled or rescheduled together
+	IdempotencyKey  string // Repeating a recent key returns the original message ID instead of sending again
+	DryRun          bool   // Validate and render without enqueueing
+}
+
+// RenderRequest identifies a template and the variables to render it with
+type RenderRequest struct {
+	TemplateName    string
+	TemplateVersion int32  // Pins a published revision; zero renders the latest
+	Locale          string // Preferred template variant, e.g. fr-CA; empty uses the default
+	Variables       map[string]any
+}
+
+// Disposition values for attachments
+const (
+	DispositionAttachment = "attachment"
+	DispositionInline     = "inline"
+)
+
+// Attachment is a file delivered with an email. Inline attachments are
+// referenced from the HTML body via cid:<ContentID>.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+	Disposition string
+	ContentID   string
 }
 
 // Template represents an email template stored in the database
 type Template struct {
-	Name             string
-	Subject          string
-	HTMLBody         string
-	TextBody         *string
-	BaseTemplateName *string
-	Variables        []string
-	Version          int32
-	CreatedAt        time.Time
-	UpdatedAt        time.Time
+	TenantID          string
+	Name              string
+	Locale            string // Variant of the named template; empty is the default variant
+	Subject           string
+	HTMLBody          string
+	TextBody          *string
+	BaseTemplateName  *string
+	Base              *Template // BaseTemplateName resolved for the same locale as this template
+	Variables         []string  // Required variables; dotted paths such as Order.Total reach into nested data
+	DefaultSender     *string   // Sender used when a request does not name one
+	SuppressionPolicy string    // How sends to suppressed recipients are handled
+	Version           int32
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// Suppression policies decide what happens when a template is sent to a suppressed recipient
+const (
+	SuppressionPolicyReject = "reject" // Fail the send
+	SuppressionPolicyDrop   = "drop"   // Remove the recipient; skip the email if no To recipients remain
+)
+
+// Reasons an address is suppressed
+const (
+	SuppressionReasonBounce      = "bounce"
+	SuppressionReasonComplaint   = "complaint"
+	SuppressionReasonUnsubscribe = "unsubscribe"
+	SuppressionReasonManual      = "manual"
+)
+
+// Suppression stops emails from being sent to an address, permanently or until it expires
+type Suppression struct {
+	Address   string
+	Reason    string
+	Source    string     // Where the suppression came from, e.g. "sendgrid" or "cli"
+	ExpiresAt *time.Time // Nil suppresses the address permanently
+	CreatedAt time.Time
+}
+
+// Sender is an identity that emails are allowed to be sent from
+type Sender struct {
+	Address   string
+	Name      string
+	CreatedAt time.Time
+}
+
+// Tenant is one of the product lines sharing the server. Each has its own templates
+// and API keys, and may override the server's default sender and provider.
+type Tenant struct {
+	ID          string
+	Name        string
+	FromAddress string // Replaces the server's default sender when set
+	FromName    string
+	Provider    string               // Tried before the server's other providers when set
+	Credentials *ProviderCredentials // The tenant's own account with Provider; nil sends through the server's
+
+	// ProviderFallback lets email fail over from the tenant's own account to the
+	// server's providers, which send under the operator's reputation and billing
+	ProviderFallback bool
+
+	CreatedAt time.Time
+}
+
+// ProviderCredentials are a tenant's own account with its provider. Only the
+// fields for that provider are set.
+type ProviderCredentials struct {
+	SendGridAPIKey     string
+	SendGridWebhookKey string // Verifies event webhooks signed by the tenant's SendGrid account
+	SMTPHost           string
+	SMTPPort           int // Zero uses the default port for SMTPTLSMode
+	SMTPUsername       string
+	SMTPPassword       string
+	SMTPTLSMode        string
+	SMTPAuth           string
+}
+
+// APIKey authorizes a service to call the API within its scopes. The secret that
+// makes up the rest of the key is only known when the key is created.
+type APIKey struct {
+	ID         string
+	TenantID   string // The tenant every call made with the key acts for
+	Name       string // Who the key was issued to, e.g. "billing-service"
+	SecretHash []byte
+	Scopes     []string
+	CreatedAt  time.Time
+	RevokedAt  *time.Time // Nil while the key is usable
+
+	// ClientIdentity is the mutual TLS client certificate identity, as returned
+	// by grpc.ClientIdentity, that authenticates as the key without its token
+	ClientIdentity *string
+}
+
+// HasScope reports whether the key grants a scope
+func (k *APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope)
 }
 
 // JobArgs holds pre-rendered email content for the job queue.
 type JobArgs struct {
-	To          string
+	To          AddressList
+	CC          AddressList
+	BCC         AddressList
+	ReplyTo     *Address
 	From        string
 	FromName    string
 	Subject     string
 	HTMLBody    string
 	TextBody    string
+	Attachments []Attachment
 	Priority    int32
 	ScheduledAt *time.Time
+	Provider    string // Tried before the server's other providers when set
+
+	// TemplateName is kept in job metadata so jobs can be found by template
+	TemplateName string `json:"-"`
+
+	// Tenant is kept in job metadata so jobs can be scoped to the caller's tenant
+	Tenant string `json:"-"`
+
+	// CancellationKey is kept in job metadata rather than args so it can be queried
+	CancellationKey string `json:"-"`
+
+	// IdempotencyKey is recorded by the queue alongside the job it enqueued rather
+	// than in the job itself
+	IdempotencyKey string `json:"-"`
+
+	// TraceContext carries the W3C trace context of the request that enqueued the
+	// email so its delivery joins the same trace. It is kept in job metadata
+	// rather than args.
+	TraceContext map[string]string `json:"-"`
+
+	// MessageID is set by the worker so delivery clients can tag the email for
+	// correlating provider events; it is not part of the stored args
+	MessageID string `json:"-"`
 }
 
 // Kind returns the unique identifier for this job type
 func (JobArgs) Kind() string { return "send_email" }
 
+// State describes where an email is in the delivery lifecycle
+type State string
+
+const (
+	StateScheduled State = "scheduled"
+	StateQueued    State = "queued"
+	StateSending   State = "sending"
+	StateSent      State = "sent"
+	StateRetrying  State = "retrying"
+	StateFailed    State = "failed"
+	StateCancelled State = "cancelled"
+)
+
+// Status reports the delivery progress of an enqueued email
+type Status struct {
+	MessageID   string
+	State       State
+	Attempts    int
+	MaxAttempts int
+	LastError   string
+	CreatedAt   time.Time
+	ScheduledAt time.Time
+	AttemptedAt *time.Time
+	FinalizedAt *time.Time
+	Provider    string           // Which provider delivered the email, once sent
+	Events      []*DeliveryEvent // Reported by the provider after the email was handed off
+}
+
+// JobFilter selects email jobs for inspection and bulk changes. Zero fields
+// match every job.
+type JobFilter struct {
+	States        []State
+	TemplateName  string
+	Recipient     string // Matches To, CC or BCC, ignoring case
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+// Job is an enqueued email as seen by an operator inspecting the queue
+type Job struct {
+	Status
+	TemplateName string // Empty for emails enqueued before templates were recorded
+	To           AddressList
+	CC           AddressList
+	BCC          AddressList
+	From         Address
+	Subject      string
+	Errors       []JobError // One per failed attempt, oldest first
+}
+
+// JobError is the error a delivery attempt failed with
+type JobError struct {
+	Attempt    int
+	Error      string
+	OccurredAt time.Time
+}
+
+// Delivery event types reported by email providers
+const (
+	EventProcessed    = "processed"
+	EventDelivered    = "delivered"
+	EventDeferred     = "deferred"
+	EventDropped      = "dropped"
+	EventBounced      = "bounced" // Hard bounce; the address does not accept mail
+	EventBlocked      = "blocked" // Soft bounce; delivery may succeed later
+	EventOpened       = "opened"
+	EventClicked      = "clicked"
+	EventSpamReport   = "spam_report"
+	EventUnsubscribed = "unsubscribed"
+)
+
+// DeliveryEvent is something a provider reported happening to an email after it was sent
+type DeliveryEvent struct {
+	Provider        string
+	ProviderEventID string // Deduplicates webhook retries
+	MessageID       string // Empty if the event could not be correlated to an email
+	Tenant          string // Tenant that sent the email; empty if it could not be correlated
+	Type            string
+	Recipient       string
+	Reason          string
+	OccurredAt      time.Time
+}
+
 // RenderedTemplate contains the rendered email content
 type RenderedTemplate struct {
 	Subject  string
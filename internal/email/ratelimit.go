@@ -0,0 +1,157 @@
+package email
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit allows Count sends every Per, in bursts of up to Count. The zero
+// value is unlimited.
+type RateLimit struct {
+	Count int
+	Per   time.Duration
+}
+
+// Enabled reports whether the limit restricts sending at all
+func (l RateLimit) Enabled() bool {
+	return l.Count > 0 && l.Per > 0
+}
+
+// Rate returns how many tokens the limit refills per second
+func (l RateLimit) Rate() float64 {
+	return float64(l.Count) / l.Per.Seconds()
+}
+
+// ParseRateLimit parses limits such as "100/s", "600/m" or "5000/h". Units may
+// also be Go durations, as in "10/30s". An empty string is unlimited.
+func ParseRateLimit(s string) (RateLimit, error) {
+	if s == "" {
+		return RateLimit{}, nil
+	}
+
+	count, unit, ok := strings.Cut(s, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: expected <count>/<unit>", s)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || n < 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: count must be a non-negative integer", s)
+	}
+
+	var per time.Duration
+	switch unit = strings.TrimSpace(unit); unit {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		per, err = time.ParseDuration(unit)
+		if err != nil || per <= 0 {
+			return RateLimit{}, fmt.Errorf("invalid rate limit %q: unknown unit %q", s, unit)
+		}
+	}
+
+	return RateLimit{Count: n, Per: per}, nil
+}
+
+// RateLimits configures how fast emails are handed to the provider
+type RateLimits struct {
+	Provider RateLimit            // Across all recipients
+	Domain   RateLimit            // Per recipient domain, unless overridden in Domains
+	Domains  map[string]RateLimit // Per recipient domain overrides, keyed by lowercase domain
+}
+
+// RateLimitBucket is a token bucket shared by every worker
+type RateLimitBucket struct {
+	Key   string
+	Limit RateLimit
+}
+
+type rateLimitDB interface {
+	// TakeTokens takes one token from every bucket if all of them have one,
+	// and otherwise returns how long until they will
+	TakeTokens(ctx context.Context, buckets []RateLimitBucket) (time.Duration, error)
+}
+
+// RateLimiter throttles deliveries per provider and per recipient domain
+type RateLimiter struct {
+	db     rateLimitDB
+	limits RateLimits
+}
+
+// NewRateLimiter creates a rate limiter for deliveries
+func NewRateLimiter(db rateLimitDB, limits RateLimits) *RateLimiter {
+	return &RateLimiter{
+		db:     db,
+		limits: limits,
+	}
+}
+
+// Reserve claims a send for the email through the named provider against every
+// limit it is subject to. owner is the tenant whose own account the provider
+// sends through, which has buckets of its own, or empty for the server's
+// account. A positive duration means a limit is exhausted; nothing is claimed
+// and the email should be retried after that long.
+func (l *RateLimiter) Reserve(ctx context.Context, args JobArgs, provider, owner string) (time.Duration, error) {
+	buckets := l.buckets(args, provider, owner)
+	if len(buckets) == 0 {
+		return 0, nil
+	}
+
+	return l.db.TakeTokens(ctx, buckets)
+}
+
+// buckets returns the limited buckets the email draws from, sorted by key so
+// concurrent workers lock them in the same order
+func (l *RateLimiter) buckets(args JobArgs, provider, owner string) []RateLimitBucket {
+	var buckets []RateLimitBucket
+
+	// A tenant's own account neither draws from nor uses up the server's buckets
+	prefix := ""
+	if owner != "" {
+		prefix = "tenant:" + owner + ":"
+	}
+
+	if l.limits.Provider.Enabled() {
+		buckets = append(buckets, RateLimitBucket{Key: prefix + "provider:" + provider, Limit: l.limits.Provider})
+	}
+
+	seen := make(map[string]bool)
+	for _, list := range []AddressList{args.To, args.CC, args.BCC} {
+		for _, address := range list {
+			domain := recipientDomain(address.Email)
+			if domain == "" || seen[domain] {
+				continue
+			}
+			seen[domain] = true
+
+			limit, ok := l.limits.Domains[domain]
+			if !ok {
+				limit = l.limits.Domain
+			}
+			if limit.Enabled() {
+				buckets = append(buckets, RateLimitBucket{Key: prefix + "domain:" + domain, Limit: limit})
+			}
+		}
+	}
+
+	slices.SortFunc(buckets, func(a, b RateLimitBucket) int { return cmp.Compare(a.Key, b.Key) })
+	return buckets
+}
+
+// recipientDomain returns the lowercase domain of an email address
+func recipientDomain(address string) string {
+	at := strings.LastIndex(address, "@")
+	if at < 0 {
+		return ""
+	}
+	return normalizeAddress(address[at+1:])
+}
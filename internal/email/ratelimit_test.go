@@ -0,0 +1,145 @@
+package email_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// mockRateLimitDB records the buckets it is asked for and returns a fixed wait
+type mockRateLimitDB struct {
+	buckets []email.RateLimitBucket
+	wait    time.Duration
+}
+
+func (m *mockRateLimitDB) TakeTokens(_ context.Context, buckets []email.RateLimitBucket) (time.Duration, error) {
+	m.buckets = buckets
+	return m.wait, nil
+}
+
+func TestParseRateLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    email.RateLimit
+		wantErr bool
+	}{
+		{input: "", want: email.RateLimit{}},
+		{input: "100/s", want: email.RateLimit{Count: 100, Per: time.Second}},
+		{input: "600/m", want: email.RateLimit{Count: 600, Per: time.Minute}},
+		{input: "5000/h", want: email.RateLimit{Count: 5000, Per: time.Hour}},
+		{input: "10/30s", want: email.RateLimit{Count: 10, Per: 30 * time.Second}},
+		{input: "100", wantErr: true},
+		{input: "-1/s", wantErr: true},
+		{input: "ten/s", wantErr: true},
+		{input: "10/fortnight", wantErr: true},
+		{input: "10/0s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := email.ParseRateLimit(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	t.Parallel()
+
+	perSecond := email.RateLimit{Count: 10, Per: time.Second}
+	perMinute := email.RateLimit{Count: 5, Per: time.Minute}
+
+	tests := []struct {
+		name     string
+		limits   email.RateLimits
+		args     email.JobArgs
+		owner    string // Tenant whose own account sends the email
+		wantKeys []string
+	}{
+		{
+			name:   "unlimited",
+			limits: email.RateLimits{},
+			args:   email.JobArgs{To: email.AddressList{{Email: "a@example.com"}}},
+		},
+		{
+			name:     "provider only",
+			limits:   email.RateLimits{Provider: perSecond},
+			args:     email.JobArgs{To: email.AddressList{{Email: "a@example.com"}}},
+			wantKeys: []string{"provider:sendgrid"},
+		},
+		{
+			name:   "each domain once across recipient lists",
+			limits: email.RateLimits{Domain: perSecond},
+			args: email.JobArgs{
+				To:  email.AddressList{{Email: "a@Example.com"}, {Email: "b@example.com"}},
+				CC:  email.AddressList{{Email: "c@corp.io"}},
+				BCC: email.AddressList{{Email: "d@example.com"}},
+			},
+			wantKeys: []string{"domain:corp.io", "domain:example.com"},
+		},
+		{
+			name: "domain override",
+			limits: email.RateLimits{
+				Provider: perSecond,
+				Domains:  map[string]email.RateLimit{"corp.io": perMinute},
+			},
+			args: email.JobArgs{
+				To: email.AddressList{{Email: "a@example.com"}, {Email: "b@corp.io"}},
+			},
+			wantKeys: []string{"domain:corp.io", "provider:sendgrid"},
+		},
+		{
+			name:     "tenant's own account",
+			limits:   email.RateLimits{Provider: perSecond, Domain: perSecond},
+			args:     email.JobArgs{To: email.AddressList{{Email: "a@example.com"}}, Tenant: "payroll"},
+			owner:    "payroll",
+			wantKeys: []string{"tenant:payroll:domain:example.com", "tenant:payroll:provider:sendgrid"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &mockRateLimitDB{}
+			limiter := email.NewRateLimiter(db, tt.limits)
+
+			wait, err := limiter.Reserve(context.Background(), tt.args, "sendgrid", tt.owner)
+			require.NoError(t, err)
+			assert.Zero(t, wait)
+
+			var keys []string
+			for _, bucket := range db.buckets {
+				keys = append(keys, bucket.Key)
+			}
+			assert.Equal(t, tt.wantKeys, keys)
+		})
+	}
+}
+
+func TestRateLimiter_Reserve_Exhausted(t *testing.T) {
+	t.Parallel()
+
+	db := &mockRateLimitDB{wait: 2 * time.Second}
+	limiter := email.NewRateLimiter(db, email.RateLimits{
+		Domains: map[string]email.RateLimit{"corp.io": {Count: 5, Per: time.Minute}},
+	})
+
+	wait, err := limiter.Reserve(context.Background(), email.JobArgs{To: email.AddressList{{Email: "a@corp.io"}}}, "sendgrid", "")
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, wait)
+	assert.Equal(t, []email.RateLimitBucket{{Key: "domain:corp.io", Limit: email.RateLimit{Count: 5, Per: time.Minute}}}, db.buckets)
+}
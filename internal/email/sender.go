@@ -0,0 +1,53 @@
+package email
+
+import (
+	"context"
+	"strings"
+)
+
+type senderDB interface {
+	GetSender(ctx context.Context, address string) (*Sender, error)
+	ListSenders(ctx context.Context) ([]*Sender, error)
+	CreateSender(ctx context.Context, sender *Sender) (*Sender, error)
+	DeleteSender(ctx context.Context, address string) error
+}
+
+// SenderService manages the allowlist of sender identities
+type SenderService struct {
+	db senderDB
+}
+
+// NewSenderService creates a new sender registry
+func NewSenderService(db senderDB) *SenderService {
+	return &SenderService{
+		db: db,
+	}
+}
+
+// AddSender registers an address that emails may be sent from
+func (s *SenderService) AddSender(ctx context.Context, address, name string) (*Sender, error) {
+	addr, err := ParseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.db.CreateSender(ctx, &Sender{
+		Address: normalizeAddress(addr.Email),
+		Name:    name,
+	})
+}
+
+// ListSenders returns every registered sender
+func (s *SenderService) ListSenders(ctx context.Context) ([]*Sender, error) {
+	return s.db.ListSenders(ctx)
+}
+
+// RemoveSender unregisters an address, refusing if a template uses it as its default
+func (s *SenderService) RemoveSender(ctx context.Context, address string) error {
+	return s.db.DeleteSender(ctx, normalizeAddress(address))
+}
+
+// normalizeAddress lowercases an address so allowlist lookups are case-insensitive
+func normalizeAddress(address string) string {
+	return strings.ToLower(strings.TrimSpace(address))
+}
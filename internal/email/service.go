@@ -2,57 +2,347 @@ package email
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// Renderer defines the interface for rendering email templates.
+// Renderer defines the interface for rendering email templates. Templates are
+// loaded with their bases already resolved through Template.Base.
 type Renderer interface {
-	Render(ctx context.Context, templateName string, variables map[string]string) (*RenderedTemplate, error)
+	Render(ctx context.Context, tmpl *Template, variables map[string]any) (*RenderedTemplate, error)
 }
 
 type jobQueue interface {
-	EnqueueEmailJob(ctx context.Context, jobArgs *JobArgs) error
+	EnqueueEmailJob(ctx context.Context, jobArgs *JobArgs) (string, error)
+	EnqueueEmailJobs(ctx context.Context, jobs []*JobArgs) ([]string, error)
+	LookupIdempotencyKeys(ctx context.Context, keys []string) (map[string]string, error)
+	RecordDroppedEmails(ctx context.Context, idempotencyKeys []string) ([]string, error)
+	GetEmailStatus(ctx context.Context, messageID string) (*Status, error)
+	CancelEmail(ctx context.Context, messageID string) error
+	CancelEmailGroup(ctx context.Context, cancellationKey string) (int, error)
+	RescheduleEmail(ctx context.Context, messageID string, scheduledAt time.Time) error
+	RescheduleEmailGroup(ctx context.Context, cancellationKey string, scheduledAt time.Time) (int, error)
+	ListEmailJobs(ctx context.Context, filter JobFilter, pageSize int, pageToken string) ([]*Job, string, error)
+	GetEmailJob(ctx context.Context, messageID string) (*Job, error)
+	RetryEmail(ctx context.Context, messageID string) error
+	DiscardEmail(ctx context.Context, messageID string) error
 }
 
 // Service orchestrates template validation, rendering, and job enqueueing.
 type Service struct {
-	Templates   templateDB
-	Renderer    Renderer
-	Queue       jobQueue
-	FromAddress string
-	FromName    string
+	Templates    templateDB
+	Senders      senderDB
+	Suppressions suppressionDB
+	Events       deliveryEventDB
+	Tenants      tenantDB // Nil gives every tenant the server's sending defaults
+	Renderer     Renderer
+	Queue        jobQueue
+	FromAddress  string
+	FromName     string
 }
 
 // Send validates the template, renders it, and enqueues the pre-rendered email.
-func (s *Service) Send(ctx context.Context, req SendRequest) error {
-	tmpl, err := s.Templates.GetTemplate(ctx, req.TemplateName)
+// It returns the message ID that can be used to look up the email's status. The
+// message ID is empty if the template's suppression policy dropped every recipient,
+// or if the request is a dry run. A request repeating an idempotency key returns
+// the result of the original without preparing the email again, since the
+// template or suppressions may have changed in between.
+func (s *Service) Send(ctx context.Context, req SendRequest) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "email.Send", templateAttributes(req.TemplateName, req.Locale, req.TemplateVersion))
+	defer func() { endSpan(span, err) }()
+
+	sent, err := s.lookupIdempotencyKeys(ctx, []SendRequest{req})
+	if err != nil {
+		return "", err
+	}
+	if messageID, ok := sent[req.IdempotencyKey]; ok {
+		return messageID, nil
+	}
+
+	tenant, err := s.loadTenant(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	jobArgs, err := s.prepare(ctx, tenant, req)
+	if err != nil {
+		return "", err
+	}
+	if req.DryRun {
+		return "", nil
+	}
+
+	if jobArgs == nil {
+		if req.IdempotencyKey == "" {
+			return "", nil
+		}
+		messageIDs, err := s.Queue.RecordDroppedEmails(ctx, []string{req.IdempotencyKey})
+		if err != nil {
+			return "", err
+		}
+		return messageIDs[0], nil
+	}
+
+	return s.Queue.EnqueueEmailJob(ctx, jobArgs)
+}
+
+// SendBatch renders every email before enqueueing any of them, then enqueues them
+// all atomically. If any email fails, nothing is sent and the returned *BatchError
+// identifies the offending email. Message IDs are returned in request order, with
+// an empty ID for dry runs and any email whose recipients were all dropped by suppression.
+func (s *Service) SendBatch(ctx context.Context, reqs []SendRequest) (_ []string, err error) {
+	ctx, span := tracer.Start(ctx, "email.SendBatch", trace.WithAttributes(attribute.Int("mailman.batch_size", len(reqs))))
+	defer func() { endSpan(span, err) }()
+
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+
+	sent, err := s.lookupIdempotencyKeys(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	tenant, err := s.loadTenant(ctx)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	messageIDs := make([]string, len(reqs))
+	var jobs []*JobArgs
+	var indexes []int        // Position in reqs of each job
+	var droppedKeys []string // Idempotency keys of emails suppression dropped
+	var dropped []int        // Position in reqs of each dropped key
+	for i, req := range reqs {
+		if messageID, ok := sent[req.IdempotencyKey]; ok && !req.DryRun {
+			messageIDs[i] = messageID
+			continue
+		}
+
+		jobArgs, err := s.prepare(ctx, tenant, req)
+		if err != nil {
+			return nil, &BatchError{Index: i, Err: err}
+		}
+
+		switch {
+		case req.DryRun:
+		case jobArgs != nil:
+			jobs = append(jobs, jobArgs)
+			indexes = append(indexes, i)
+		case req.IdempotencyKey != "":
+			droppedKeys = append(droppedKeys, req.IdempotencyKey)
+			dropped = append(dropped, i)
+		}
+	}
+
+	if len(jobs) > 0 {
+		enqueued, err := s.Queue.EnqueueEmailJobs(ctx, jobs)
+		if err != nil {
+			return nil, err
+		}
+		for i, messageID := range enqueued {
+			messageIDs[indexes[i]] = messageID
+		}
+	}
+
+	if len(droppedKeys) > 0 {
+		recorded, err := s.Queue.RecordDroppedEmails(ctx, droppedKeys)
+		if err != nil {
+			return nil, err
+		}
+		for i, messageID := range recorded {
+			messageIDs[dropped[i]] = messageID
+		}
 	}
 
-	for _, v := range tmpl.Variables {
-		if _, ok := req.Variables[v]; !ok {
-			return fmt.Errorf("%w: %s", ErrMissingVariable, v)
+	return messageIDs, nil
+}
+
+// lookupIdempotencyKeys returns the message ID each request's idempotency key
+// was already used for, keyed by idempotency key. Keys whose email suppression
+// dropped map to an empty message ID. Dry runs are left out, since they never
+// use up a key.
+func (s *Service) lookupIdempotencyKeys(ctx context.Context, reqs []SendRequest) (map[string]string, error) {
+	var keys []string
+	for _, req := range reqs {
+		if req.IdempotencyKey != "" && !req.DryRun {
+			keys = append(keys, req.IdempotencyKey)
 		}
 	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
 
-	rendered, err := s.Renderer.Render(ctx, req.TemplateName, req.Variables)
+	return s.Queue.LookupIdempotencyKeys(ctx, keys)
+}
+
+// loadTenant returns the tenant the context acts for
+func (s *Service) loadTenant(ctx context.Context) (*Tenant, error) {
+	tenantID := TenantFromContext(ctx)
+	if s.Tenants == nil {
+		return &Tenant{ID: tenantID}, nil
+	}
+	return s.Tenants.GetTenant(ctx, tenantID)
+}
+
+// prepare loads and renders the template, applies the suppression list and resolves
+// the sender, producing the job that delivers the email. It returns a nil job if
+// suppression left the email with no one to send to.
+func (s *Service) prepare(ctx context.Context, tenant *Tenant, req SendRequest) (*JobArgs, error) {
+	locale, err := NormalizeLocale(req.Locale)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := s.loadTemplate(ctx, req.TemplateName, locale, req.TemplateVersion)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := s.Queue.EnqueueEmailJob(ctx, &JobArgs{
+	if err := tmpl.CheckVariables(req.Variables); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.applySuppressions(ctx, tmpl.SuppressionPolicy, &req)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	from, err := s.resolveSender(ctx, req.Sender, tmpl, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := s.Renderer.Render(ctx, tmpl, req.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JobArgs{
 		To:          req.To,
-		From:        s.FromAddress,
-		FromName:    s.FromName,
+		CC:          req.CC,
+		BCC:         req.BCC,
+		ReplyTo:     req.ReplyTo,
+		From:        from.Email,
+		FromName:    from.Name,
 		Subject:     rendered.Subject,
 		HTMLBody:    rendered.HTMLBody,
 		TextBody:    rendered.TextBody,
+		Attachments: req.Attachments,
 		Priority:    req.Priority,
 		ScheduledAt: req.ScheduledAt,
-	}); err != nil {
-		return err
+		Provider:    tenant.Provider,
+
+		TemplateName:    tmpl.Name,
+		Tenant:          tenant.ID,
+		CancellationKey: req.CancellationKey,
+		IdempotencyKey:  req.IdempotencyKey,
+		TraceContext:    traceContext(ctx),
+	}, nil
+}
+
+// Render validates the variables and renders a template exactly as Send would,
+// without sending anything.
+func (s *Service) Render(ctx context.Context, req RenderRequest) (_ *RenderedTemplate, err error) {
+	ctx, span := tracer.Start(ctx, "email.Render", templateAttributes(req.TemplateName, req.Locale, req.TemplateVersion))
+	defer func() { endSpan(span, err) }()
+
+	locale, err := NormalizeLocale(req.Locale)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	tmpl, err := s.loadTemplate(ctx, req.TemplateName, locale, req.TemplateVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tmpl.CheckVariables(req.Variables); err != nil {
+		return nil, err
+	}
+
+	return s.Renderer.Render(ctx, tmpl, req.Variables)
+}
+
+// loadTemplate returns the best variant of a template for the locale, with its bases,
+// at the pinned revision or the latest if version is zero
+func (s *Service) loadTemplate(ctx context.Context, name, locale string, version int32) (*Template, error) {
+	if version > 0 {
+		return s.Templates.GetTemplateVersion(ctx, name, locale, version)
+	}
+	return s.Templates.GetTemplate(ctx, name, locale)
+}
+
+// resolveSender picks the requested sender, then the template's default, then the
+// tenant's default, then the server's configured address. Anything other than the
+// tenant or server default must be registered.
+func (s *Service) resolveSender(ctx context.Context, requested string, tmpl *Template, tenant *Tenant) (Address, error) {
+	address := normalizeAddress(requested)
+	if address == "" && tmpl.DefaultSender != nil {
+		address = *tmpl.DefaultSender
+	}
+
+	if tenant.FromAddress != "" && (address == "" || strings.EqualFold(address, tenant.FromAddress)) {
+		return Address{Name: tenant.FromName, Email: tenant.FromAddress}, nil
+	}
+	if address == "" || strings.EqualFold(address, s.FromAddress) {
+		return Address{Name: s.FromName, Email: s.FromAddress}, nil
+	}
+
+	sender, err := s.Senders.GetSender(ctx, address)
+	if err != nil {
+		if errors.Is(err, ErrSenderNotFound) {
+			return Address{}, fmt.Errorf("%w: %s", ErrSenderNotAllowed, address)
+		}
+		return Address{}, err
+	}
+
+	return Address{Name: sender.Name, Email: sender.Address}, nil
+}
+
+// GetStatus returns the delivery status of a previously enqueued email, including
+// any events the provider has reported for it.
+func (s *Service) GetStatus(ctx context.Context, messageID string) (*Status, error) {
+	status, err := s.Queue.GetEmailStatus(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Events != nil {
+		if status.Events, err = s.Events.ListEvents(ctx, messageID); err != nil {
+			return nil, err
+		}
+	}
+
+	return status, nil
+}
+
+// Cancel stops a pending email from being sent.
+func (s *Service) Cancel(ctx context.Context, messageID string) error {
+	return s.Queue.CancelEmail(ctx, messageID)
+}
+
+// CancelGroup cancels every pending email sent with the cancellation key and
+// returns how many were cancelled.
+func (s *Service) CancelGroup(ctx context.Context, cancellationKey string) (int, error) {
+	return s.Queue.CancelEmailGroup(ctx, cancellationKey)
+}
+
+// Reschedule moves a pending email to a new delivery time.
+func (s *Service) Reschedule(ctx context.Context, messageID string, scheduledAt time.Time) error {
+	return s.Queue.RescheduleEmail(ctx, messageID, scheduledAt)
+}
+
+// RescheduleGroup moves every pending email sent with the cancellation key to a
+// new delivery time and returns how many were rescheduled.
+func (s *Service) RescheduleGroup(ctx context.Context, cancellationKey string, scheduledAt time.Time) (int, error) {
+	return s.Queue.RescheduleEmailGroup(ctx, cancellationKey, scheduledAt)
 }
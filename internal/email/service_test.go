@@ -3,28 +3,54 @@ package email_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/travisbale/mailman/internal/email"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// mockTemplateDB returns a fixed template or error from GetTemplate.
+// mockTemplateDB returns a fixed template or error from GetTemplate, and
+// records which locale and revision were requested.
 type mockTemplateDB struct {
-	template *email.Template
-	err      error
+	template         *email.Template
+	err              error
+	requestedLocale  string
+	requestedVersion int32
 }
 
-func (m *mockTemplateDB) GetTemplate(_ context.Context, _ string) (*email.Template, error) {
+func (m *mockTemplateDB) GetTemplate(_ context.Context, _, locale string) (*email.Template, error) {
+	m.requestedLocale = locale
 	return m.template, m.err
 }
 
+func (m *mockTemplateDB) GetTemplateVersion(_ context.Context, _, locale string, version int32) (*email.Template, error) {
+	m.requestedLocale = locale
+	m.requestedVersion = version
+	return m.template, m.err
+}
+
+func (m *mockTemplateDB) ListVersions(_ context.Context, _, _ string) ([]*email.Template, error) {
+	panic("not implemented")
+}
+
 func (m *mockTemplateDB) Create(_ context.Context, _ *email.Template) (*email.Template, error) {
 	panic("not implemented")
 }
 
+func (m *mockTemplateDB) Update(_ context.Context, _ *email.Template) (*email.Template, error) {
+	panic("not implemented")
+}
+
+func (m *mockTemplateDB) Delete(_ context.Context, _, _ string, _ int32) error {
+	panic("not implemented")
+}
+
 func (m *mockTemplateDB) List(_ context.Context) ([]*email.Template, error) {
 	panic("not implemented")
 }
@@ -32,29 +58,164 @@ func (m *mockTemplateDB) List(_ context.Context) ([]*email.Template, error) {
 // mockRenderer returns a fixed rendered template or error from Render.
 type mockRenderer struct {
 	rendered *email.RenderedTemplate
+	template *email.Template
 	err      error
 }
 
-func (m *mockRenderer) Render(_ context.Context, _ string, _ map[string]string) (*email.RenderedTemplate, error) {
+func (m *mockRenderer) Render(_ context.Context, tmpl *email.Template, _ map[string]any) (*email.RenderedTemplate, error) {
+	m.template = tmpl
 	return m.rendered, m.err
 }
 
-// mockQueue captures the JobArgs passed to EnqueueEmailJob.
+// mockSenderDB is an in-memory sender allowlist keyed by address.
+type mockSenderDB struct {
+	senders map[string]*email.Sender
+}
+
+func (m *mockSenderDB) GetSender(_ context.Context, address string) (*email.Sender, error) {
+	sender, ok := m.senders[address]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", email.ErrSenderNotFound, address)
+	}
+	return sender, nil
+}
+
+func (m *mockSenderDB) ListSenders(_ context.Context) ([]*email.Sender, error) {
+	panic("not implemented")
+}
+
+func (m *mockSenderDB) CreateSender(_ context.Context, _ *email.Sender) (*email.Sender, error) {
+	panic("not implemented")
+}
+
+func (m *mockSenderDB) DeleteSender(_ context.Context, _ string) error {
+	panic("not implemented")
+}
+
+// mockSuppressionDB holds the active suppressions keyed by address and records upserts.
+type mockSuppressionDB struct {
+	suppressed map[string]bool
+	upserted   []*email.Suppression
+}
+
+func (m *mockSuppressionDB) ListActiveSuppressions(_ context.Context, addresses []string) ([]*email.Suppression, error) {
+	var suppressions []*email.Suppression
+	for _, address := range addresses {
+		if m.suppressed[address] {
+			suppressions = append(suppressions, &email.Suppression{Address: address, Reason: email.SuppressionReasonBounce})
+		}
+	}
+	return suppressions, nil
+}
+
+func (m *mockSuppressionDB) ListSuppressions(_ context.Context) ([]*email.Suppression, error) {
+	panic("not implemented")
+}
+
+func (m *mockSuppressionDB) UpsertSuppression(_ context.Context, suppression *email.Suppression) (*email.Suppression, error) {
+	m.upserted = append(m.upserted, suppression)
+	return suppression, nil
+}
+
+func (m *mockSuppressionDB) DeleteSuppression(_ context.Context, _ string) error {
+	panic("not implemented")
+}
+
+// mockQueue captures the JobArgs passed to EnqueueEmailJob and EnqueueEmailJobs
+// and the target of cancel and reschedule calls.
 type mockQueue struct {
-	jobArgs *email.JobArgs
-	err     error
+	jobArgs     *email.JobArgs
+	batch       []*email.JobArgs
+	messageID   string
+	status      *email.Status
+	err         error
+	target      string // Message ID or cancellation key passed to cancel/reschedule
+	scheduledAt time.Time
+	count       int
+	keys        map[string]string // Message ID sent for each used idempotency key
 }
 
-func (m *mockQueue) EnqueueEmailJob(_ context.Context, jobArgs *email.JobArgs) error {
+func (m *mockQueue) EnqueueEmailJob(_ context.Context, jobArgs *email.JobArgs) (string, error) {
 	m.jobArgs = jobArgs
+	return m.messageID, m.err
+}
+
+func (m *mockQueue) EnqueueEmailJobs(_ context.Context, jobs []*email.JobArgs) ([]string, error) {
+	m.batch = jobs
+	messageIDs := make([]string, len(jobs))
+	for i := range jobs {
+		messageIDs[i] = fmt.Sprint(i + 1)
+	}
+	return messageIDs, m.err
+}
+
+func (m *mockQueue) LookupIdempotencyKeys(_ context.Context, keys []string) (map[string]string, error) {
+	sent := make(map[string]string)
+	for _, key := range keys {
+		if messageID, ok := m.keys[key]; ok {
+			sent[key] = messageID
+		}
+	}
+	return sent, m.err
+}
+
+func (m *mockQueue) RecordDroppedEmails(_ context.Context, idempotencyKeys []string) ([]string, error) {
+	for _, key := range idempotencyKeys {
+		if m.keys == nil {
+			m.keys = make(map[string]string)
+		}
+		m.keys[key] = ""
+	}
+	return make([]string, len(idempotencyKeys)), m.err
+}
+
+func (m *mockQueue) GetEmailStatus(_ context.Context, _ string) (*email.Status, error) {
+	return m.status, m.err
+}
+
+func (m *mockQueue) CancelEmail(_ context.Context, messageID string) error {
+	m.target = messageID
 	return m.err
 }
 
+func (m *mockQueue) CancelEmailGroup(_ context.Context, cancellationKey string) (int, error) {
+	m.target = cancellationKey
+	return m.count, m.err
+}
+
+func (m *mockQueue) RescheduleEmail(_ context.Context, messageID string, scheduledAt time.Time) error {
+	m.target = messageID
+	m.scheduledAt = scheduledAt
+	return m.err
+}
+
+func (m *mockQueue) RescheduleEmailGroup(_ context.Context, cancellationKey string, scheduledAt time.Time) (int, error) {
+	m.target = cancellationKey
+	m.scheduledAt = scheduledAt
+	return m.count, m.err
+}
+
+func (m *mockQueue) ListEmailJobs(_ context.Context, _ email.JobFilter, _ int, _ string) ([]*email.Job, string, error) {
+	panic("not implemented")
+}
+
+func (m *mockQueue) GetEmailJob(_ context.Context, _ string) (*email.Job, error) {
+	panic("not implemented")
+}
+
+func (m *mockQueue) RetryEmail(_ context.Context, _ string) error {
+	panic("not implemented")
+}
+
+func (m *mockQueue) DiscardEmail(_ context.Context, _ string) error {
+	panic("not implemented")
+}
+
 func TestService_Send_Success(t *testing.T) {
 	t.Parallel()
 
 	scheduledAt := time.Now().Add(5 * time.Minute)
-	queue := &mockQueue{}
+	queue := &mockQueue{messageID: "42"}
 
 	svc := &email.Service{
 		Templates: &mockTemplateDB{
@@ -76,19 +237,28 @@ func TestService_Send_Success(t *testing.T) {
 	}
 
 	req := email.SendRequest{
-		To:           "user@example.com",
+		To:           email.AddressList{{Name: "Alice", Email: "user@example.com"}},
+		CC:           email.AddressList{{Email: "support@example.com"}},
+		BCC:          email.AddressList{{Email: "audit@example.com"}},
+		ReplyTo:      &email.Address{Email: "tickets@example.com"},
 		TemplateName: "welcome",
-		Variables:    map[string]string{"Name": "Alice"},
+		Variables:    map[string]any{"Name": "Alice"},
 		Priority:     2,
 		ScheduledAt:  &scheduledAt,
+
+		CancellationKey: "onboarding:alice",
 	}
 
-	err := svc.Send(context.Background(), req)
+	messageID, err := svc.Send(context.Background(), req)
 	require.NoError(t, err)
+	assert.Equal(t, "42", messageID)
 
 	// Verify the enqueued job contains pre-rendered content and service config.
 	require.NotNil(t, queue.jobArgs)
-	assert.Equal(t, "user@example.com", queue.jobArgs.To)
+	assert.Equal(t, req.To, queue.jobArgs.To)
+	assert.Equal(t, req.CC, queue.jobArgs.CC)
+	assert.Equal(t, req.BCC, queue.jobArgs.BCC)
+	assert.Equal(t, req.ReplyTo, queue.jobArgs.ReplyTo)
 	assert.Equal(t, "no-reply@example.com", queue.jobArgs.From)
 	assert.Equal(t, "Example", queue.jobArgs.FromName)
 	assert.Equal(t, "Hello, World!", queue.jobArgs.Subject)
@@ -96,6 +266,7 @@ func TestService_Send_Success(t *testing.T) {
 	assert.Equal(t, "Hello", queue.jobArgs.TextBody)
 	assert.Equal(t, int32(2), queue.jobArgs.Priority)
 	assert.Equal(t, &scheduledAt, queue.jobArgs.ScheduledAt)
+	assert.Equal(t, "onboarding:alice", queue.jobArgs.CancellationKey)
 }
 
 func TestService_Send_MissingVariable(t *testing.T) {
@@ -115,11 +286,521 @@ func TestService_Send_MissingVariable(t *testing.T) {
 	// Only "Name" provided; "Company" is missing.
 	req := email.SendRequest{
 		TemplateName: "invite",
-		Variables:    map[string]string{"Name": "Alice"},
+		Variables:    map[string]any{"Name": "Alice"},
 	}
 
-	err := svc.Send(context.Background(), req)
+	_, err := svc.Send(context.Background(), req)
 	require.Error(t, err)
 	assert.True(t, errors.Is(err, email.ErrMissingVariable))
 	assert.Contains(t, err.Error(), "Company")
 }
+
+func TestService_Send_Locale(t *testing.T) {
+	t.Parallel()
+
+	t.Run("normalizes the locale", func(t *testing.T) {
+		t.Parallel()
+
+		templates := &mockTemplateDB{template: &email.Template{Name: "welcome", Locale: "fr"}}
+		svc := &email.Service{Templates: templates, Renderer: &mockRenderer{rendered: &email.RenderedTemplate{}}, Queue: &mockQueue{}}
+
+		_, err := svc.Send(context.Background(), email.SendRequest{
+			To:           email.AddressList{{Email: "alice@example.com"}},
+			TemplateName: "welcome",
+			Locale:       "fr_ca",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "fr-CA", templates.requestedLocale)
+	})
+
+	t.Run("rejects invalid locales", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &email.Service{Templates: &mockTemplateDB{}, Renderer: &mockRenderer{}, Queue: &mockQueue{}}
+
+		_, err := svc.Send(context.Background(), email.SendRequest{
+			To:           email.AddressList{{Email: "alice@example.com"}},
+			TemplateName: "welcome",
+			Locale:       "not a locale",
+		})
+		require.ErrorIs(t, err, email.ErrInvalidLocale)
+	})
+}
+
+func TestService_Send_TraceContext(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	traceID := trace.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c}
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     trace.SpanID{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31},
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	queue := &mockQueue{messageID: "42"}
+	svc := &email.Service{
+		Templates: &mockTemplateDB{template: &email.Template{Name: "welcome"}},
+		Renderer:  &mockRenderer{rendered: &email.RenderedTemplate{}},
+		Queue:     queue,
+	}
+
+	t.Run("carries the request's trace to the job", func(t *testing.T) {
+		_, err := svc.Send(ctx, email.SendRequest{
+			To:           email.AddressList{{Email: "alice@example.com"}},
+			TemplateName: "welcome",
+		})
+		require.NoError(t, err)
+		require.NotNil(t, queue.jobArgs)
+		assert.Contains(t, queue.jobArgs.TraceContext["traceparent"], traceID.String())
+	})
+
+	t.Run("omits trace context outside a trace", func(t *testing.T) {
+		_, err := svc.Send(context.Background(), email.SendRequest{
+			To:           email.AddressList{{Email: "alice@example.com"}},
+			TemplateName: "welcome",
+		})
+		require.NoError(t, err)
+		require.NotNil(t, queue.jobArgs)
+		assert.Nil(t, queue.jobArgs.TraceContext)
+	})
+}
+
+func TestService_Send_DryRun(t *testing.T) {
+	t.Parallel()
+
+	queue := &mockQueue{messageID: "42"}
+	svc := &email.Service{
+		Templates: &mockTemplateDB{
+			template: &email.Template{Name: "welcome", Variables: []string{"Name"}},
+		},
+		Renderer: &mockRenderer{rendered: &email.RenderedTemplate{Subject: "Hello"}},
+		Queue:    queue,
+	}
+
+	t.Run("validates without enqueueing", func(t *testing.T) {
+		messageID, err := svc.Send(context.Background(), email.SendRequest{
+			To:           email.AddressList{{Email: "alice@example.com"}},
+			TemplateName: "welcome",
+			Variables:    map[string]any{"Name": "Alice"},
+			DryRun:       true,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, messageID)
+		assert.Nil(t, queue.jobArgs, "nothing should be enqueued")
+	})
+
+	t.Run("still reports validation errors", func(t *testing.T) {
+		_, err := svc.Send(context.Background(), email.SendRequest{
+			To:           email.AddressList{{Email: "alice@example.com"}},
+			TemplateName: "welcome",
+			DryRun:       true,
+		})
+		require.ErrorIs(t, err, email.ErrMissingVariable)
+	})
+}
+
+func TestService_Render(t *testing.T) {
+	t.Parallel()
+
+	rendered := &email.RenderedTemplate{Subject: "Hello, Alice", HTMLBody: "<p>Hello</p>", TextBody: "Hello"}
+	templates := &mockTemplateDB{
+		template: &email.Template{Name: "welcome", Variables: []string{"Name"}},
+	}
+	queue := &mockQueue{}
+	svc := &email.Service{
+		Templates: templates,
+		Renderer:  &mockRenderer{rendered: rendered},
+		Queue:     queue,
+	}
+
+	t.Run("renders without enqueueing", func(t *testing.T) {
+		got, err := svc.Render(context.Background(), email.RenderRequest{
+			TemplateName:    "welcome",
+			TemplateVersion: 3,
+			Variables:       map[string]any{"Name": "Alice"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, rendered, got)
+		assert.Equal(t, int32(3), templates.requestedVersion)
+		assert.Nil(t, queue.jobArgs)
+	})
+
+	t.Run("requires the template's variables", func(t *testing.T) {
+		_, err := svc.Render(context.Background(), email.RenderRequest{TemplateName: "welcome"})
+		require.ErrorIs(t, err, email.ErrMissingVariable)
+	})
+}
+
+func TestService_SendBatch(t *testing.T) {
+	t.Parallel()
+
+	newService := func(queue *mockQueue) *email.Service {
+		return &email.Service{
+			Templates: &mockTemplateDB{
+				template: &email.Template{Name: "welcome", Variables: []string{"Name"}},
+			},
+			Renderer: &mockRenderer{rendered: &email.RenderedTemplate{Subject: "Hello"}},
+			Queue:    queue,
+		}
+	}
+
+	t.Run("enqueues every email together", func(t *testing.T) {
+		t.Parallel()
+
+		queue := &mockQueue{}
+		messageIDs, err := newService(queue).SendBatch(context.Background(), []email.SendRequest{
+			{To: email.AddressList{{Email: "alice@example.com"}}, TemplateName: "welcome", Variables: map[string]any{"Name": "Alice"}},
+			{To: email.AddressList{{Email: "bob@example.com"}}, TemplateName: "welcome", Variables: map[string]any{"Name": "Bob"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1", "2"}, messageIDs)
+		require.Len(t, queue.batch, 2)
+		assert.Equal(t, "bob@example.com", queue.batch[1].To.Emails()[0])
+	})
+
+	t.Run("rejects the batch if any email fails", func(t *testing.T) {
+		t.Parallel()
+
+		queue := &mockQueue{}
+		_, err := newService(queue).SendBatch(context.Background(), []email.SendRequest{
+			{To: email.AddressList{{Email: "alice@example.com"}}, TemplateName: "welcome", Variables: map[string]any{"Name": "Alice"}},
+			{To: email.AddressList{{Email: "bob@example.com"}}, TemplateName: "welcome"},
+		})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, email.ErrMissingVariable))
+
+		var batchErr *email.BatchError
+		require.True(t, errors.As(err, &batchErr))
+		assert.Equal(t, 1, batchErr.Index)
+		assert.Nil(t, queue.batch, "nothing should be enqueued")
+	})
+
+	t.Run("skips dry runs", func(t *testing.T) {
+		t.Parallel()
+
+		queue := &mockQueue{}
+		messageIDs, err := newService(queue).SendBatch(context.Background(), []email.SendRequest{
+			{To: email.AddressList{{Email: "alice@example.com"}}, TemplateName: "welcome", Variables: map[string]any{"Name": "Alice"}, DryRun: true},
+			{To: email.AddressList{{Email: "bob@example.com"}}, TemplateName: "welcome", Variables: map[string]any{"Name": "Bob"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"", "1"}, messageIDs)
+		require.Len(t, queue.batch, 1)
+		assert.Equal(t, "bob@example.com", queue.batch[0].To.Emails()[0])
+	})
+}
+
+func TestService_Send_PinnedVersion(t *testing.T) {
+	t.Parallel()
+
+	pinned := &email.Template{Name: "welcome", Version: 3}
+	templates := &mockTemplateDB{template: pinned}
+	renderer := &mockRenderer{rendered: &email.RenderedTemplate{Subject: "v3"}}
+
+	svc := &email.Service{
+		Templates: templates,
+		Renderer:  renderer,
+		Queue:     &mockQueue{},
+	}
+
+	_, err := svc.Send(context.Background(), email.SendRequest{
+		To:              email.AddressList{{Email: "user@example.com"}},
+		TemplateName:    "welcome",
+		TemplateVersion: 3,
+	})
+	require.NoError(t, err)
+
+	// The exact revision fetched is the one rendered
+	assert.Equal(t, int32(3), templates.requestedVersion)
+	assert.Same(t, pinned, renderer.template)
+}
+
+func TestService_Send_Sender(t *testing.T) {
+	t.Parallel()
+
+	senders := &mockSenderDB{senders: map[string]*email.Sender{
+		"billing@example.com":  {Address: "billing@example.com", Name: "Billing"},
+		"security@example.com": {Address: "security@example.com", Name: "Security"},
+	}}
+
+	tests := []struct {
+		name          string
+		sender        string
+		defaultSender *string
+		wantFrom      string
+		wantFromName  string
+		wantErr       error
+	}{
+		{
+			name:         "server default",
+			wantFrom:     "no-reply@example.com",
+			wantFromName: "Example",
+		},
+		{
+			name:         "requested sender",
+			sender:       "Billing@Example.com",
+			wantFrom:     "billing@example.com",
+			wantFromName: "Billing",
+		},
+		{
+			name:          "template default sender",
+			defaultSender: strPtr("security@example.com"),
+			wantFrom:      "security@example.com",
+			wantFromName:  "Security",
+		},
+		{
+			name:          "request overrides template default",
+			sender:        "billing@example.com",
+			defaultSender: strPtr("security@example.com"),
+			wantFrom:      "billing@example.com",
+			wantFromName:  "Billing",
+		},
+		{
+			name:    "unregistered sender",
+			sender:  "ceo@example.com",
+			wantErr: email.ErrSenderNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queue := &mockQueue{}
+			svc := &email.Service{
+				Templates: &mockTemplateDB{
+					template: &email.Template{Name: "invoice", DefaultSender: tt.defaultSender},
+				},
+				Senders:     senders,
+				Renderer:    &mockRenderer{rendered: &email.RenderedTemplate{}},
+				Queue:       queue,
+				FromAddress: "no-reply@example.com",
+				FromName:    "Example",
+			}
+
+			_, err := svc.Send(context.Background(), email.SendRequest{
+				To:           email.AddressList{{Email: "user@example.com"}},
+				TemplateName: "invoice",
+				Sender:       tt.sender,
+			})
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr))
+				assert.Nil(t, queue.jobArgs)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFrom, queue.jobArgs.From)
+			assert.Equal(t, tt.wantFromName, queue.jobArgs.FromName)
+		})
+	}
+}
+
+func TestService_Send_Suppressed(t *testing.T) {
+	t.Parallel()
+
+	suppressions := &mockSuppressionDB{suppressed: map[string]bool{
+		"bounced@example.com": true,
+	}}
+
+	tests := []struct {
+		name     string
+		policy   string
+		to       email.AddressList
+		cc       email.AddressList
+		wantErr  error
+		wantTo   []string
+		wantCC   []string
+		wantDrop bool // Nothing enqueued and no error
+	}{
+		{
+			name:   "no suppressed recipients",
+			policy: email.SuppressionPolicyReject,
+			to:     email.AddressList{{Email: "alice@example.com"}},
+			cc:     email.AddressList{{Email: "bob@example.com"}},
+			wantTo: []string{"alice@example.com"},
+			wantCC: []string{"bob@example.com"},
+		},
+		{
+			name:    "reject policy fails the send",
+			policy:  email.SuppressionPolicyReject,
+			to:      email.AddressList{{Email: "alice@example.com"}},
+			cc:      email.AddressList{{Email: "Bounced@Example.com"}},
+			wantErr: email.ErrRecipientSuppressed,
+		},
+		{
+			name:   "drop policy removes the recipient",
+			policy: email.SuppressionPolicyDrop,
+			to:     email.AddressList{{Email: "alice@example.com"}},
+			cc:     email.AddressList{{Email: "Bounced@Example.com"}, {Email: "bob@example.com"}},
+			wantTo: []string{"alice@example.com"},
+			wantCC: []string{"bob@example.com"},
+		},
+		{
+			name:     "drop policy skips the email without To recipients",
+			policy:   email.SuppressionPolicyDrop,
+			to:       email.AddressList{{Email: "bounced@example.com"}},
+			cc:       email.AddressList{{Email: "bob@example.com"}},
+			wantDrop: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queue := &mockQueue{messageID: "42"}
+			svc := &email.Service{
+				Templates: &mockTemplateDB{
+					template: &email.Template{Name: "welcome", SuppressionPolicy: tt.policy},
+				},
+				Suppressions: suppressions,
+				Renderer:     &mockRenderer{rendered: &email.RenderedTemplate{}},
+				Queue:        queue,
+			}
+
+			messageID, err := svc.Send(context.Background(), email.SendRequest{
+				To:           tt.to,
+				CC:           tt.cc,
+				TemplateName: "welcome",
+			})
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, tt.wantErr))
+				assert.Contains(t, err.Error(), "bounced@example.com")
+				assert.Nil(t, queue.jobArgs)
+				return
+			}
+
+			require.NoError(t, err)
+			if tt.wantDrop {
+				assert.Empty(t, messageID)
+				assert.Nil(t, queue.jobArgs)
+				return
+			}
+
+			assert.Equal(t, "42", messageID)
+			assert.Equal(t, tt.wantTo, queue.jobArgs.To.Emails())
+			assert.Equal(t, tt.wantCC, queue.jobArgs.CC.Emails())
+		})
+	}
+}
+
+func TestService_Send_IdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	queue := &mockQueue{messageID: "42", keys: map[string]string{"order-1": "7"}}
+	svc := &email.Service{
+		Templates: &mockTemplateDB{
+			template: &email.Template{Name: "welcome", SuppressionPolicy: email.SuppressionPolicyDrop},
+		},
+		Suppressions: &mockSuppressionDB{suppressed: map[string]bool{"bounced@example.com": true}},
+		Renderer:     &mockRenderer{rendered: &email.RenderedTemplate{}},
+		Queue:        queue,
+	}
+
+	// A repeated key returns the original email, even if the template can no longer be sent
+	svc.Templates = &mockTemplateDB{err: email.ErrTemplateNotFound}
+	messageID, err := svc.Send(context.Background(), email.SendRequest{
+		To:             email.AddressList{{Email: "alice@example.com"}},
+		TemplateName:   "welcome",
+		IdempotencyKey: "order-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "7", messageID)
+	assert.Nil(t, queue.jobArgs)
+
+	// An email whose recipients were all dropped uses up its key too
+	svc.Templates = &mockTemplateDB{
+		template: &email.Template{Name: "welcome", SuppressionPolicy: email.SuppressionPolicyDrop},
+	}
+	messageID, err = svc.Send(context.Background(), email.SendRequest{
+		To:             email.AddressList{{Email: "bounced@example.com"}},
+		TemplateName:   "welcome",
+		IdempotencyKey: "order-2",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, messageID)
+	assert.Contains(t, queue.keys, "order-2")
+
+	// so retrying it returns the same result, even once the recipient is no longer suppressed
+	svc.Suppressions = &mockSuppressionDB{}
+	messageID, err = svc.Send(context.Background(), email.SendRequest{
+		To:             email.AddressList{{Email: "bounced@example.com"}},
+		TemplateName:   "welcome",
+		IdempotencyKey: "order-2",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, messageID)
+	assert.Nil(t, queue.jobArgs)
+
+	// Batches do the same for each email
+	messageIDs, err := svc.SendBatch(context.Background(), []email.SendRequest{
+		{To: email.AddressList{{Email: "alice@example.com"}}, TemplateName: "welcome", IdempotencyKey: "order-1"},
+		{To: email.AddressList{{Email: "alice@example.com"}}, TemplateName: "welcome", IdempotencyKey: "order-2"},
+		{To: email.AddressList{{Email: "alice@example.com"}}, TemplateName: "welcome", IdempotencyKey: "order-3"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"7", "", "1"}, messageIDs)
+	assert.Len(t, queue.batch, 1)
+}
+
+func TestService_GetStatus(t *testing.T) {
+	t.Parallel()
+
+	queue := &mockQueue{
+		status: &email.Status{
+			MessageID: "42",
+			State:     email.StateRetrying,
+			Attempts:  2,
+			LastError: "provider unavailable",
+		},
+	}
+
+	svc := &email.Service{Queue: queue}
+
+	status, err := svc.GetStatus(context.Background(), "42")
+	require.NoError(t, err)
+	assert.Equal(t, email.StateRetrying, status.State)
+	assert.Equal(t, 2, status.Attempts)
+	assert.Equal(t, "provider unavailable", status.LastError)
+}
+
+func TestService_GetStatus_NotFound(t *testing.T) {
+	t.Parallel()
+
+	svc := &email.Service{
+		Queue: &mockQueue{err: email.ErrMessageNotFound},
+	}
+
+	_, err := svc.GetStatus(context.Background(), "missing")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, email.ErrMessageNotFound))
+}
+
+func TestService_Cancel_NotPending(t *testing.T) {
+	t.Parallel()
+
+	queue := &mockQueue{err: email.ErrMessageNotPending}
+	svc := &email.Service{Queue: queue}
+
+	err := svc.Cancel(context.Background(), "42")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, email.ErrMessageNotPending))
+	assert.Equal(t, "42", queue.target)
+}
+
+func TestService_RescheduleGroup(t *testing.T) {
+	t.Parallel()
+
+	scheduledAt := time.Now().Add(time.Hour)
+	queue := &mockQueue{count: 3}
+	svc := &email.Service{Queue: queue}
+
+	rescheduled, err := svc.RescheduleGroup(context.Background(), "onboarding:alice", scheduledAt)
+	require.NoError(t, err)
+	assert.Equal(t, 3, rescheduled)
+	assert.Equal(t, "onboarding:alice", queue.target)
+	assert.Equal(t, scheduledAt, queue.scheduledAt)
+}
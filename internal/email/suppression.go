@@ -0,0 +1,111 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+type suppressionDB interface {
+	ListActiveSuppressions(ctx context.Context, addresses []string) ([]*Suppression, error)
+	ListSuppressions(ctx context.Context) ([]*Suppression, error)
+	UpsertSuppression(ctx context.Context, suppression *Suppression) (*Suppression, error)
+	DeleteSuppression(ctx context.Context, address string) error
+}
+
+// SuppressionService manages the addresses emails must not be sent to
+type SuppressionService struct {
+	db suppressionDB
+}
+
+// NewSuppressionService creates a new suppression list
+func NewSuppressionService(db suppressionDB) *SuppressionService {
+	return &SuppressionService{
+		db: db,
+	}
+}
+
+// Suppress stops emails from being sent to an address. Suppressing an address
+// again replaces its reason, source and expiry.
+func (s *SuppressionService) Suppress(ctx context.Context, address, reason, source string, expiresAt *time.Time) (*Suppression, error) {
+	addr, err := ParseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(suppressionReasons, reason) {
+		return nil, fmt.Errorf("%w %q", ErrInvalidSuppressionReason, reason)
+	}
+
+	return s.db.UpsertSuppression(ctx, &Suppression{
+		Address:   normalizeAddress(addr.Email),
+		Reason:    reason,
+		Source:    source,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// ListSuppressions returns every suppression, including expired ones
+func (s *SuppressionService) ListSuppressions(ctx context.Context) ([]*Suppression, error) {
+	return s.db.ListSuppressions(ctx)
+}
+
+// RemoveSuppression allows emails to be sent to an address again
+func (s *SuppressionService) RemoveSuppression(ctx context.Context, address string) error {
+	return s.db.DeleteSuppression(ctx, normalizeAddress(address))
+}
+
+var suppressionReasons = []string{
+	SuppressionReasonBounce,
+	SuppressionReasonComplaint,
+	SuppressionReasonUnsubscribe,
+	SuppressionReasonManual,
+}
+
+// applySuppressions checks the request's recipients against the suppression list.
+// Under the reject policy any suppressed recipient fails the send; under the drop
+// policy suppressed recipients are removed from req, and it reports false if no
+// To recipients remain.
+func (s *Service) applySuppressions(ctx context.Context, policy string, req *SendRequest) (bool, error) {
+	if s.Suppressions == nil {
+		return true, nil
+	}
+
+	var addresses []string
+	for _, list := range []AddressList{req.To, req.CC, req.BCC} {
+		for _, addr := range list {
+			addresses = append(addresses, normalizeAddress(addr.Email))
+		}
+	}
+
+	suppressions, err := s.Suppressions.ListActiveSuppressions(ctx, addresses)
+	if err != nil {
+		return false, fmt.Errorf("failed to check suppressions: %w", err)
+	}
+	if len(suppressions) == 0 {
+		return true, nil
+	}
+
+	if policy != SuppressionPolicyDrop {
+		suppressed := make([]string, len(suppressions))
+		for i, suppression := range suppressions {
+			suppressed[i] = suppression.Address
+		}
+		return false, fmt.Errorf("%w: %s", ErrRecipientSuppressed, strings.Join(suppressed, ", "))
+	}
+
+	isSuppressed := func(addr Address) bool {
+		return slices.ContainsFunc(suppressions, func(suppression *Suppression) bool {
+			return suppression.Address == normalizeAddress(addr.Email)
+		})
+	}
+
+	// Clone so the caller's slices are left untouched
+	req.To = slices.DeleteFunc(slices.Clone(req.To), isSuppressed)
+	req.CC = slices.DeleteFunc(slices.Clone(req.CC), isSuppressed)
+	req.BCC = slices.DeleteFunc(slices.Clone(req.BCC), isSuppressed)
+
+	return len(req.To) > 0, nil
+}
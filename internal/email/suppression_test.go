@@ -0,0 +1,22 @@
+package email_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+func TestSuppressionService_Suppress_Invalid(t *testing.T) {
+	t.Parallel()
+
+	// Invalid suppressions are rejected before reaching the database
+	service := email.NewSuppressionService(nil)
+
+	_, err := service.Suppress(context.Background(), "not an address", email.SuppressionReasonManual, "api", nil)
+	assert.ErrorIs(t, err, email.ErrInvalidAddress)
+
+	_, err = service.Suppress(context.Background(), "user@example.com", "spite", "api", nil)
+	assert.ErrorIs(t, err, email.ErrInvalidSuppressionReason)
+}
@@ -3,11 +3,18 @@ package email
 import (
 	"context"
 	"fmt"
+	"strings"
 )
 
 type templateDB interface {
-	GetTemplate(ctx context.Context, name string) (*Template, error)
+	// GetTemplate and GetTemplateVersion return the first variant of the template
+	// found in the locale's fallback chain
+	GetTemplate(ctx context.Context, name, locale string) (*Template, error)
+	GetTemplateVersion(ctx context.Context, name, locale string, version int32) (*Template, error)
+	ListVersions(ctx context.Context, name, locale string) ([]*Template, error)
 	Create(ctx context.Context, template *Template) (*Template, error)
+	Update(ctx context.Context, template *Template) (*Template, error)
+	Delete(ctx context.Context, name, locale string, version int32) error
 	List(ctx context.Context) ([]*Template, error)
 }
 
@@ -23,40 +30,210 @@ func NewTemplateService(db templateDB) *TemplateService {
 	}
 }
 
-func (s *TemplateService) GetTemplate(ctx context.Context, name string) (*Template, error) {
-	return s.db.GetTemplate(ctx, name)
+// GetTemplate returns the variant of a template that would be sent for the locale,
+// falling back to less specific locales and then the default variant
+func (s *TemplateService) GetTemplate(ctx context.Context, name, locale string) (*Template, error) {
+	locale, err := NormalizeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.db.GetTemplate(ctx, name, locale)
 }
 
 // CreateTemplate creates a new template with circular reference validation
 func (s *TemplateService) CreateTemplate(ctx context.Context, template *Template) (*Template, error) {
+	if err := normalizeTemplateLocale(template); err != nil {
+		return nil, err
+	}
+
 	if template.BaseTemplateName != nil && *template.BaseTemplateName != "" {
 		// Catch circular references at creation time instead of runtime
-		if err := s.validateNoCircularReference(ctx, template.Name, *template.BaseTemplateName); err != nil {
+		if err := s.validateNoCircularReference(ctx, template.Name, template.Locale, *template.BaseTemplateName); err != nil {
 			return nil, err
 		}
 	}
 
+	normalizeDefaultSender(template)
+	defaultSuppressionPolicy(template)
 	return s.db.Create(ctx, template)
 }
 
-// ListTemplates returns all templates
+// UpdateTemplate replaces a template's content. template.Version must match the
+// stored version, so concurrent editors can't silently overwrite each other.
+func (s *TemplateService) UpdateTemplate(ctx context.Context, template *Template) (*Template, error) {
+	if err := normalizeTemplateLocale(template); err != nil {
+		return nil, err
+	}
+
+	if template.BaseTemplateName != nil && *template.BaseTemplateName != "" {
+		// The new base may already inherit from this template
+		if err := s.validateNoCircularReference(ctx, template.Name, template.Locale, *template.BaseTemplateName); err != nil {
+			return nil, err
+		}
+	}
+
+	normalizeDefaultSender(template)
+	defaultSuppressionPolicy(template)
+	return s.db.Update(ctx, template)
+}
+
+// DeleteTemplate removes one locale's variant of a template. A version of 0
+// deletes whatever version is current; any other value must match the stored version.
+func (s *TemplateService) DeleteTemplate(ctx context.Context, name, locale string, version int32) error {
+	locale, err := NormalizeLocale(locale)
+	if err != nil {
+		return err
+	}
+
+	if version == 0 {
+		current, err := s.getVariant(ctx, name, locale)
+		if err != nil {
+			return err
+		}
+		version = current.Version
+	}
+
+	return s.db.Delete(ctx, name, locale, version)
+}
+
+// GetTemplateVersion returns a specific published revision of a template
+func (s *TemplateService) GetTemplateVersion(ctx context.Context, name, locale string, version int32) (*Template, error) {
+	locale, err := NormalizeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.db.GetTemplateVersion(ctx, name, locale, version)
+}
+
+// ListTemplateVersions returns every published revision of one locale's variant
+// of a template, newest first
+func (s *TemplateService) ListTemplateVersions(ctx context.Context, name, locale string) ([]*Template, error) {
+	locale, err := NormalizeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.db.ListVersions(ctx, name, locale)
+}
+
+// RollbackTemplate republishes the content of an earlier revision as a new version.
+// History is never rewritten, so the rollback itself can be rolled back.
+func (s *TemplateService) RollbackTemplate(ctx context.Context, name, locale string, version int32) (*Template, error) {
+	locale, err := NormalizeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.getVariant(ctx, name, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.db.GetTemplateVersion(ctx, name, locale, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.UpdateTemplate(ctx, &Template{
+		Name:              name,
+		Locale:            locale,
+		Subject:           target.Subject,
+		HTMLBody:          target.HTMLBody,
+		TextBody:          target.TextBody,
+		BaseTemplateName:  target.BaseTemplateName,
+		Variables:         target.Variables,
+		DefaultSender:     target.DefaultSender,
+		SuppressionPolicy: target.SuppressionPolicy,
+		Version:           current.Version,
+	})
+}
+
+// ListTemplates returns every variant of every template
 func (s *TemplateService) ListTemplates(ctx context.Context) ([]*Template, error) {
 	return s.db.List(ctx)
 }
 
-// validateNoCircularReference checks if adding a template would create a circular reference
-func (s *TemplateService) validateNoCircularReference(ctx context.Context, newTemplateName, baseTemplateName string) error {
+// getVariant returns the variant for exactly this locale, without falling back
+func (s *TemplateService) getVariant(ctx context.Context, name, locale string) (*Template, error) {
+	template, err := s.db.GetTemplate(ctx, name, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	if template.Locale != locale {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, DescribeTemplate(name, locale))
+	}
+
+	return template, nil
+}
+
+// CheckVariables fails if any variable the template requires was not provided.
+// Dotted names such as Order.Total must resolve through nested maps.
+func (t *Template) CheckVariables(variables map[string]any) error {
+	for _, name := range t.Variables {
+		if !hasVariable(variables, name) {
+			return fmt.Errorf("%w: %s", ErrMissingVariable, name)
+		}
+	}
+	return nil
+}
+
+// hasVariable reports whether a dotted path resolves to a value
+func hasVariable(variables map[string]any, path string) bool {
+	var value any = variables
+	for _, key := range strings.Split(path, ".") {
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return false
+		}
+		if value, ok = fields[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeTemplateLocale stores locales in canonical form so lookups match
+func normalizeTemplateLocale(template *Template) error {
+	locale, err := NormalizeLocale(template.Locale)
+	if err != nil {
+		return err
+	}
+	template.Locale = locale
+	return nil
+}
+
+// normalizeDefaultSender matches the normalization applied when senders are registered
+func normalizeDefaultSender(template *Template) {
+	if template.DefaultSender != nil {
+		address := normalizeAddress(*template.DefaultSender)
+		template.DefaultSender = &address
+	}
+}
+
+// defaultSuppressionPolicy rejects sends to suppressed recipients unless the template says otherwise
+func defaultSuppressionPolicy(template *Template) {
+	if template.SuppressionPolicy == "" {
+		template.SuppressionPolicy = SuppressionPolicyReject
+	}
+}
+
+// validateNoCircularReference checks if adding a template would create a circular
+// reference, following bases as they resolve in the template's locale
+func (s *TemplateService) validateNoCircularReference(ctx context.Context, newTemplateName, locale, baseTemplateName string) error {
 	seen := make(map[string]bool)
 	seen[newTemplateName] = true
 
 	currentName := baseTemplateName
 	for currentName != "" {
 		if seen[currentName] {
-			return fmt.Errorf("circular reference detected: template '%s' already appears in the inheritance chain", currentName)
+			return fmt.Errorf("%w: template '%s' already appears in the inheritance chain", ErrCircularReference, currentName)
 		}
 		seen[currentName] = true
 
-		current, err := s.db.GetTemplate(ctx, currentName)
+		current, err := s.db.GetTemplate(ctx, currentName, locale)
 		if err != nil {
 			return fmt.Errorf("failed to load base template '%s': %w", currentName, err)
 		}
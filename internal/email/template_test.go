@@ -0,0 +1,308 @@
+package email_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// fakeTemplateDB is an in-memory template store keyed by email.DescribeTemplate,
+// so default variants are keyed by name alone.
+type fakeTemplateDB struct {
+	templates map[string]*email.Template
+	versions  map[string]map[int32]*email.Template
+	deleted   map[string]int32
+}
+
+func newFakeTemplateDB(templates ...*email.Template) *fakeTemplateDB {
+	db := &fakeTemplateDB{
+		templates: make(map[string]*email.Template),
+		versions:  make(map[string]map[int32]*email.Template),
+		deleted:   make(map[string]int32),
+	}
+	for _, t := range templates {
+		db.publish(t)
+	}
+	return db
+}
+
+// publish makes t the current revision and records it in the version history
+func (f *fakeTemplateDB) publish(t *email.Template) {
+	key := email.DescribeTemplate(t.Name, t.Locale)
+	f.templates[key] = t
+	if f.versions[key] == nil {
+		f.versions[key] = make(map[int32]*email.Template)
+	}
+	f.versions[key][t.Version] = t
+}
+
+func (f *fakeTemplateDB) GetTemplate(_ context.Context, name, locale string) (*email.Template, error) {
+	for _, fallback := range email.LocaleFallbacks(locale) {
+		if t, ok := f.templates[email.DescribeTemplate(name, fallback)]; ok {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", email.ErrTemplateNotFound, name)
+}
+
+func (f *fakeTemplateDB) GetTemplateVersion(ctx context.Context, name, locale string, version int32) (*email.Template, error) {
+	current, err := f.GetTemplate(ctx, name, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := f.versions[email.DescribeTemplate(name, current.Locale)][version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s version %d", email.ErrTemplateNotFound, name, version)
+	}
+	return t, nil
+}
+
+func (f *fakeTemplateDB) ListVersions(_ context.Context, _, _ string) ([]*email.Template, error) {
+	panic("not implemented")
+}
+
+func (f *fakeTemplateDB) Create(_ context.Context, t *email.Template) (*email.Template, error) {
+	f.publish(t)
+	return t, nil
+}
+
+func (f *fakeTemplateDB) Update(_ context.Context, t *email.Template) (*email.Template, error) {
+	current := f.templates[email.DescribeTemplate(t.Name, t.Locale)]
+	if current.Version != t.Version {
+		return nil, email.ErrVersionConflict
+	}
+
+	updated := *t
+	updated.Version++
+	f.publish(&updated)
+	return &updated, nil
+}
+
+func (f *fakeTemplateDB) Delete(_ context.Context, name, locale string, version int32) error {
+	f.deleted[email.DescribeTemplate(name, locale)] = version
+	return nil
+}
+
+func (f *fakeTemplateDB) List(_ context.Context) ([]*email.Template, error) {
+	panic("not implemented")
+}
+
+func TestTemplateService_CreateTemplate_CircularReference(t *testing.T) {
+	t.Parallel()
+
+	db := newFakeTemplateDB(
+		&email.Template{Name: "layout", BaseTemplateName: strPtr("child")},
+	)
+	svc := email.NewTemplateService(db)
+
+	_, err := svc.CreateTemplate(context.Background(), &email.Template{
+		Name:             "child",
+		BaseTemplateName: strPtr("layout"),
+	})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, email.ErrCircularReference))
+}
+
+func TestTemplateService_UpdateTemplate_CircularReference(t *testing.T) {
+	t.Parallel()
+
+	// Pointing layout at child would create layout -> child -> layout
+	db := newFakeTemplateDB(
+		&email.Template{Name: "layout", Version: 1},
+		&email.Template{Name: "child", BaseTemplateName: strPtr("layout"), Version: 1},
+	)
+	svc := email.NewTemplateService(db)
+
+	_, err := svc.UpdateTemplate(context.Background(), &email.Template{
+		Name:             "layout",
+		BaseTemplateName: strPtr("child"),
+		Version:          1,
+	})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, email.ErrCircularReference))
+}
+
+func TestTemplateService_UpdateTemplate(t *testing.T) {
+	t.Parallel()
+
+	db := newFakeTemplateDB(
+		&email.Template{Name: "welcome", HTMLBody: "<p>v1</p>", Version: 1},
+	)
+	svc := email.NewTemplateService(db)
+
+	updated, err := svc.UpdateTemplate(context.Background(), &email.Template{
+		Name:     "welcome",
+		HTMLBody: "<p>v2</p>",
+		Version:  1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), updated.Version)
+	assert.Equal(t, "<p>v2</p>", updated.HTMLBody)
+}
+
+func TestTemplateService_CreateTemplate_DefaultSender(t *testing.T) {
+	t.Parallel()
+
+	db := newFakeTemplateDB()
+	svc := email.NewTemplateService(db)
+
+	// Senders are stored lowercased, so the reference must be too
+	created, err := svc.CreateTemplate(context.Background(), &email.Template{
+		Name:          "invoice",
+		DefaultSender: strPtr("Billing@Example.com"),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, created.DefaultSender)
+	assert.Equal(t, "billing@example.com", *created.DefaultSender)
+}
+
+func TestTemplateService_UpdateTemplate_DefaultSender(t *testing.T) {
+	t.Parallel()
+
+	db := newFakeTemplateDB(
+		&email.Template{Name: "invoice", Version: 1},
+	)
+	svc := email.NewTemplateService(db)
+
+	updated, err := svc.UpdateTemplate(context.Background(), &email.Template{
+		Name:          "invoice",
+		DefaultSender: strPtr("Billing@Example.com"),
+		Version:       1,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, updated.DefaultSender)
+	assert.Equal(t, "billing@example.com", *updated.DefaultSender)
+}
+
+func TestTemplateService_DeleteTemplate_CurrentVersion(t *testing.T) {
+	t.Parallel()
+
+	db := newFakeTemplateDB(&email.Template{Name: "welcome", Version: 3})
+	svc := email.NewTemplateService(db)
+
+	require.NoError(t, svc.DeleteTemplate(context.Background(), "welcome", "", 0))
+	assert.Equal(t, int32(3), db.deleted["welcome"])
+}
+
+func TestTemplateService_DeleteTemplate_NotFound(t *testing.T) {
+	t.Parallel()
+
+	svc := email.NewTemplateService(newFakeTemplateDB())
+
+	err := svc.DeleteTemplate(context.Background(), "missing", "", 0)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, email.ErrTemplateNotFound))
+}
+
+func TestTemplateService_DeleteTemplate_Locale(t *testing.T) {
+	t.Parallel()
+
+	db := newFakeTemplateDB(
+		&email.Template{Name: "welcome", Version: 2},
+		&email.Template{Name: "welcome", Locale: "fr", Version: 5},
+	)
+	svc := email.NewTemplateService(db)
+
+	require.NoError(t, svc.DeleteTemplate(context.Background(), "welcome", "FR", 0))
+	assert.Equal(t, int32(5), db.deleted["welcome (fr)"])
+
+	// Deleting a variant never falls back to another locale's variant
+	err := svc.DeleteTemplate(context.Background(), "welcome", "de", 0)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, email.ErrTemplateNotFound))
+	assert.NotContains(t, db.deleted, "welcome")
+}
+
+func TestTemplateService_CreateTemplate_InvalidLocale(t *testing.T) {
+	t.Parallel()
+
+	svc := email.NewTemplateService(newFakeTemplateDB())
+
+	_, err := svc.CreateTemplate(context.Background(), &email.Template{Name: "welcome", Locale: "not a locale"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, email.ErrInvalidLocale))
+}
+
+func TestTemplateService_RollbackTemplate(t *testing.T) {
+	t.Parallel()
+
+	db := newFakeTemplateDB(&email.Template{Name: "welcome", HTMLBody: "<p>v1</p>", Version: 1})
+	db.publish(&email.Template{Name: "welcome", HTMLBody: "<p>v2</p>", Version: 2})
+	svc := email.NewTemplateService(db)
+
+	rolledBack, err := svc.RollbackTemplate(context.Background(), "welcome", "", 1)
+	require.NoError(t, err)
+
+	// Rolling back publishes a new revision rather than rewriting history
+	assert.Equal(t, int32(3), rolledBack.Version)
+	assert.Equal(t, "<p>v1</p>", rolledBack.HTMLBody)
+	assert.Equal(t, "<p>v2</p>", db.versions["welcome"][2].HTMLBody)
+}
+
+func TestTemplateService_RollbackTemplate_UnknownVersion(t *testing.T) {
+	t.Parallel()
+
+	db := newFakeTemplateDB(&email.Template{Name: "welcome", Version: 1})
+	svc := email.NewTemplateService(db)
+
+	_, err := svc.RollbackTemplate(context.Background(), "welcome", "", 7)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, email.ErrTemplateNotFound))
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestTemplate_CheckVariables(t *testing.T) {
+	t.Parallel()
+
+	tmpl := &email.Template{Variables: []string{"Name", "Order.Total", "Order.Customer.Email"}}
+
+	tests := []struct {
+		name        string
+		variables   map[string]any
+		wantMissing string
+	}{
+		{
+			name: "all present",
+			variables: map[string]any{
+				"Name":  "Alice",
+				"Order": map[string]any{"Total": 12.5, "Customer": map[string]any{"Email": "alice@example.com"}},
+			},
+		},
+		{
+			name:        "missing top-level variable",
+			variables:   map[string]any{"Order": map[string]any{"Total": 12.5}},
+			wantMissing: "Name",
+		},
+		{
+			name:        "missing nested field",
+			variables:   map[string]any{"Name": "Alice", "Order": map[string]any{"Customer": map[string]any{}}},
+			wantMissing: "Order.Total",
+		},
+		{
+			name:        "path through a non-object",
+			variables:   map[string]any{"Name": "Alice", "Order": "A-100"},
+			wantMissing: "Order.Total",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tmpl.CheckVariables(tt.variables)
+			if tt.wantMissing == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, email.ErrMissingVariable)
+			assert.Contains(t, err.Error(), tt.wantMissing)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultTenant owns everything created before tenants existed, and acts for
+// callers that aren't authenticated as any other tenant
+const DefaultTenant = "default"
+
+// tenantIDPattern keeps tenant IDs short, lowercase identifiers such as "billing"
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+type tenantContextKey struct{}
+
+// WithTenant returns a context acting for a tenant. Templates and emails are only
+// visible to the tenant that created them.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant a context acts for, or DefaultTenant if
+// none was set
+func TenantFromContext(ctx context.Context) string {
+	if tenantID, ok := ctx.Value(tenantContextKey{}).(string); ok && tenantID != "" {
+		return tenantID
+	}
+	return DefaultTenant
+}
+
+type tenantDB interface {
+	GetTenant(ctx context.Context, id string) (*Tenant, error)
+	ListTenants(ctx context.Context) ([]*Tenant, error)
+	CreateTenant(ctx context.Context, tenant *Tenant) (*Tenant, error)
+	UpdateTenant(ctx context.Context, tenant *Tenant) (*Tenant, error)
+}
+
+// TenantService manages the tenants sharing the server
+type TenantService struct {
+	db tenantDB
+}
+
+// NewTenantService creates a new tenant service
+func NewTenantService(db tenantDB) *TenantService {
+	return &TenantService{
+		db: db,
+	}
+}
+
+// GetTenant returns a tenant by ID
+func (s *TenantService) GetTenant(ctx context.Context, id string) (*Tenant, error) {
+	return s.db.GetTenant(ctx, id)
+}
+
+// ListTenants returns every tenant
+func (s *TenantService) ListTenants(ctx context.Context) ([]*Tenant, error) {
+	return s.db.ListTenants(ctx)
+}
+
+// AddTenant creates a tenant
+func (s *TenantService) AddTenant(ctx context.Context, tenant *Tenant) (*Tenant, error) {
+	if !tenantIDPattern.MatchString(tenant.ID) {
+		return nil, fmt.Errorf("invalid tenant ID %q: use lowercase letters, digits, hyphens and underscores", tenant.ID)
+	}
+
+	if err := normalizeTenant(tenant); err != nil {
+		return nil, err
+	}
+
+	return s.db.CreateTenant(ctx, tenant)
+}
+
+// UpdateTenant replaces a tenant's name and sending defaults
+func (s *TenantService) UpdateTenant(ctx context.Context, tenant *Tenant) (*Tenant, error) {
+	if err := normalizeTenant(tenant); err != nil {
+		return nil, err
+	}
+
+	return s.db.UpdateTenant(ctx, tenant)
+}
+
+// normalizeTenant validates a tenant's sending defaults
+func normalizeTenant(tenant *Tenant) error {
+	tenant.Name = strings.TrimSpace(tenant.Name)
+	tenant.Provider = strings.TrimSpace(tenant.Provider)
+
+	if tenant.Credentials != nil && tenant.Provider == "" {
+		return errors.New("provider credentials require a provider")
+	}
+	if tenant.ProviderFallback && tenant.Credentials == nil {
+		return errors.New("provider fallback requires provider credentials")
+	}
+
+	if tenant.FromAddress == "" {
+		if tenant.FromName != "" {
+			return errors.New("a from name requires a from address")
+		}
+		return nil
+	}
+
+	addr, err := ParseAddress(tenant.FromAddress)
+	if err != nil {
+		return err
+	}
+	tenant.FromAddress = normalizeAddress(addr.Email)
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package email_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// fakeTenantDB is an in-memory tenant store keyed by ID.
+type fakeTenantDB struct {
+	tenants map[string]*email.Tenant
+}
+
+func (f *fakeTenantDB) GetTenant(_ context.Context, id string) (*email.Tenant, error) {
+	tenant, ok := f.tenants[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", email.ErrTenantNotFound, id)
+	}
+	return tenant, nil
+}
+
+func (f *fakeTenantDB) ListTenants(_ context.Context) ([]*email.Tenant, error) {
+	panic("not implemented")
+}
+
+func (f *fakeTenantDB) CreateTenant(_ context.Context, tenant *email.Tenant) (*email.Tenant, error) {
+	f.tenants[tenant.ID] = tenant
+	return tenant, nil
+}
+
+func (f *fakeTenantDB) UpdateTenant(_ context.Context, tenant *email.Tenant) (*email.Tenant, error) {
+	f.tenants[tenant.ID] = tenant
+	return tenant, nil
+}
+
+func TestTenantFromContext(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, email.DefaultTenant, email.TenantFromContext(context.Background()))
+	assert.Equal(t, email.DefaultTenant, email.TenantFromContext(email.WithTenant(context.Background(), "")))
+	assert.Equal(t, "payroll", email.TenantFromContext(email.WithTenant(context.Background(), "payroll")))
+}
+
+func TestTenantService_AddTenant(t *testing.T) {
+	t.Parallel()
+
+	svc := email.NewTenantService(&fakeTenantDB{tenants: make(map[string]*email.Tenant)})
+
+	tenant, err := svc.AddTenant(context.Background(), &email.Tenant{ID: "payroll", FromAddress: "Payroll@Example.com", FromName: "Payroll"})
+	require.NoError(t, err)
+	assert.Equal(t, "payroll@example.com", tenant.FromAddress)
+
+	tests := []struct {
+		name   string
+		tenant *email.Tenant
+	}{
+		{name: "uppercase id", tenant: &email.Tenant{ID: "Payroll"}},
+		{name: "empty id", tenant: &email.Tenant{}},
+		{name: "from name without address", tenant: &email.Tenant{ID: "hr", FromName: "HR"}},
+		{name: "invalid from address", tenant: &email.Tenant{ID: "hr", FromAddress: "not an address"}},
+		{name: "fallback without credentials", tenant: &email.Tenant{ID: "hr", Provider: "smtp", ProviderFallback: true}},
+		{name: "credentials without provider", tenant: &email.Tenant{ID: "hr", Credentials: &email.ProviderCredentials{SendGridAPIKey: "key"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.AddTenant(context.Background(), tt.tenant)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestService_Send_Tenant(t *testing.T) {
+	t.Parallel()
+
+	tenants := &fakeTenantDB{tenants: map[string]*email.Tenant{
+		email.DefaultTenant: {ID: email.DefaultTenant},
+		"payroll":           {ID: "payroll", FromAddress: "payroll@example.com", FromName: "Payroll", Provider: "smtp"},
+	}}
+
+	tests := []struct {
+		name         string
+		tenant       string
+		wantFrom     string
+		wantFromName string
+		wantProvider string
+	}{
+		{
+			name:         "default tenant",
+			wantFrom:     "no-reply@example.com",
+			wantFromName: "Example",
+		},
+		{
+			name:         "tenant defaults",
+			tenant:       "payroll",
+			wantFrom:     "payroll@example.com",
+			wantFromName: "Payroll",
+			wantProvider: "smtp",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			if tt.tenant != "" {
+				ctx = email.WithTenant(ctx, tt.tenant)
+			}
+
+			queue := &mockQueue{}
+			svc := &email.Service{
+				Templates:   &mockTemplateDB{template: &email.Template{Name: "payslip"}},
+				Senders:     &mockSenderDB{},
+				Tenants:     tenants,
+				Renderer:    &mockRenderer{rendered: &email.RenderedTemplate{}},
+				Queue:       queue,
+				FromAddress: "no-reply@example.com",
+				FromName:    "Example",
+			}
+
+			_, err := svc.Send(ctx, email.SendRequest{
+				To:           email.AddressList{{Email: "user@example.com"}},
+				TemplateName: "payslip",
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFrom, queue.jobArgs.From)
+			assert.Equal(t, tt.wantFromName, queue.jobArgs.FromName)
+			assert.Equal(t, tt.wantProvider, queue.jobArgs.Provider)
+			assert.Equal(t, email.TenantFromContext(ctx), queue.jobArgs.Tenant)
+		})
+	}
+
+	svc := &email.Service{Tenants: tenants}
+	_, err := svc.Send(email.WithTenant(context.Background(), "unknown"), email.SendRequest{TemplateName: "payslip"})
+	assert.ErrorIs(t, err, email.ErrTenantNotFound)
+}
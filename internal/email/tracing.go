@@ -0,0 +1,43 @@
+package email
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/travisbale/mailman/internal/email")
+
+// templateAttributes describes the template a request names for its span
+func templateAttributes(name, locale string, version int32) trace.SpanStartOption {
+	return trace.WithAttributes(
+		attribute.String("mailman.template", name),
+		attribute.String("mailman.locale", locale),
+		attribute.Int("mailman.template_version", int(version)),
+	)
+}
+
+// endSpan records a failed operation's error on its span and ends the span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// traceContext captures the trace context of ctx so an email's delivery, which
+// happens later in a worker, can continue the trace. It returns nil if ctx isn't
+// part of a trace.
+func traceContext(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
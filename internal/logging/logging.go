@@ -0,0 +1,79 @@
+// Package logging configures structured logging and carries request-scoped log
+// fields through contexts
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Output formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// New creates a logger writing to w in the given format at the given level
+// (debug, info, warn or error)
+func New(w io.Writer, format, level string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: expected debug, info, warn or error", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	switch strings.ToLower(format) {
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q: expected %s or %s", format, FormatText, FormatJSON)
+	}
+}
+
+type contextKey struct{}
+
+// fields collects the log attributes of a request or job as it is handled
+type fields struct {
+	mu    sync.Mutex
+	attrs []any
+}
+
+// NewContext starts a set of request-scoped log fields, seeded with args given as
+// alternating keys and values like slog.Logger.With
+func NewContext(ctx context.Context, args ...any) context.Context {
+	return context.WithValue(ctx, contextKey{}, &fields{attrs: args})
+}
+
+// AddAttrs adds fields to every later log line for the request in ctx. It does
+// nothing if ctx has no request-scoped fields.
+func AddAttrs(ctx context.Context, args ...any) {
+	f, ok := ctx.Value(contextKey{}).(*fields)
+	if !ok {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.attrs = append(f.attrs, args...)
+}
+
+// FromContext returns the default logger with the request-scoped fields in ctx
+func FromContext(ctx context.Context) *slog.Logger {
+	f, ok := ctx.Value(contextKey{}).(*fields)
+	if !ok {
+		return slog.Default()
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slog.Default().With(f.attrs...)
+}
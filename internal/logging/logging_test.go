@@ -0,0 +1,92 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/internal/logging"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		format  string
+		level   string
+		wantErr bool
+	}{
+		{name: "text", format: "text", level: "info"},
+		{name: "json", format: "json", level: "debug"},
+		{name: "case insensitive", format: "JSON", level: "WARN"},
+		{name: "unknown format", format: "xml", level: "info", wantErr: true},
+		{name: "unknown level", format: "text", level: "verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			logger, err := logging.New(&bytes.Buffer{}, tt.format, tt.level)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, logger)
+		})
+	}
+}
+
+func TestNew_Level(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "text", "warn")
+	require.NoError(t, err)
+
+	logger.Info("hidden")
+	logger.Warn("shown")
+
+	assert.NotContains(t, buf.String(), "hidden")
+	assert.Contains(t, buf.String(), "shown")
+}
+
+// TestFromContext replaces the default logger, so it must not run in parallel
+func TestFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "json", "info")
+	require.NoError(t, err)
+
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	t.Run("includes request fields", func(t *testing.T) {
+		buf.Reset()
+
+		ctx := logging.NewContext(context.Background(), "method", "/mailman.v1.MailmanService/SendEmail")
+		logging.AddAttrs(ctx, "template", "welcome")
+		logging.FromContext(ctx).Info("Request handled")
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Equal(t, "/mailman.v1.MailmanService/SendEmail", entry["method"])
+		assert.Equal(t, "welcome", entry["template"])
+	})
+
+	t.Run("ignores fields without a request", func(t *testing.T) {
+		buf.Reset()
+
+		ctx := context.Background()
+		logging.AddAttrs(ctx, "template", "welcome")
+		logging.FromContext(ctx).Info("No request")
+
+		assert.NotContains(t, buf.String(), "template")
+	})
+}
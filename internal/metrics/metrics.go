@@ -0,0 +1,95 @@
+// Package metrics defines the Prometheus metrics mailman exposes on /metrics
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "mailman"
+
+// Outcomes of a delivery attempt through a provider
+const (
+	OutcomeSent     = "sent"
+	OutcomeFailed   = "failed"   // Transient failure, the email may be retried
+	OutcomeRejected = "rejected" // The provider refused the email outright
+)
+
+// Registry holds every mailman metric along with the Go runtime and process collectors
+var Registry = prometheus.NewRegistry()
+
+var (
+	// GRPCRequests counts handled gRPC requests by method and status code
+	GRPCRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_requests_total",
+		Help:      "gRPC requests handled, by method and status code.",
+	}, []string{"method", "code"})
+
+	// GRPCRequestDuration observes how long gRPC requests take by method and status code
+	GRPCRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_request_duration_seconds",
+		Help:      "Time taken to handle gRPC requests, by method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	// RenderDuration observes how long templates take to render by template name
+	RenderDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "render_duration_seconds",
+		Help:      "Time taken to render email templates, by template.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"template"})
+
+	// EnqueueErrors counts emails that could not be added to the job queue
+	EnqueueErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "enqueue_errors_total",
+		Help:      "Emails that failed to be enqueued.",
+	})
+
+	// SendDuration observes delivery attempts by provider and outcome
+	SendDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "send_duration_seconds",
+		Help:      "Time taken to hand emails to a provider, by provider and outcome.",
+		Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"provider", "outcome"})
+
+	// RateLimited counts delivery attempts snoozed by an exhausted rate limit, by provider
+	RateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_total",
+		Help:      "Delivery attempts snoozed because a rate limit was exhausted, by provider.",
+	}, []string{"provider"})
+
+	// SendRetries counts send jobs worked after an earlier attempt failed
+	SendRetries = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "send_retries_total",
+		Help:      "Send job attempts that retried an earlier failed attempt.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		GRPCRequests,
+		GRPCRequestDuration,
+		RenderDuration,
+		EnqueueErrors,
+		SendDuration,
+		RateLimited,
+		SendRetries,
+	)
+}
+
+// Handler serves the metrics in the registry in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
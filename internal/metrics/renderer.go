@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// Renderer records how long each template takes to render
+type Renderer struct {
+	renderer email.Renderer
+}
+
+// NewRenderer wraps a renderer to record render latency per template
+func NewRenderer(renderer email.Renderer) *Renderer {
+	return &Renderer{renderer: renderer}
+}
+
+// Render renders the template with the wrapped renderer, timing it
+func (r *Renderer) Render(ctx context.Context, tmpl *email.Template, variables map[string]any) (*email.RenderedTemplate, error) {
+	start := time.Now()
+	defer func() {
+		RenderDuration.WithLabelValues(tmpl.Name).Observe(time.Since(start).Seconds())
+	}()
+
+	return r.renderer.Render(ctx, tmpl, variables)
+}
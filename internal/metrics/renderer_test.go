@@ -0,0 +1,41 @@
+package metrics_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/internal/email"
+	"github.com/travisbale/mailman/internal/metrics"
+)
+
+type stubRenderer struct{}
+
+func (stubRenderer) Render(_ context.Context, tmpl *email.Template, _ map[string]any) (*email.RenderedTemplate, error) {
+	return &email.RenderedTemplate{Subject: tmpl.Subject}, nil
+}
+
+// renderCount returns how many renders of the template have been recorded
+func renderCount(t *testing.T, template string) uint64 {
+	t.Helper()
+
+	var m dto.Metric
+	require.NoError(t, metrics.RenderDuration.WithLabelValues(template).(prometheus.Metric).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestRenderer_Render(t *testing.T) {
+	t.Parallel()
+
+	before := renderCount(t, "metrics_test_welcome")
+
+	renderer := metrics.NewRenderer(stubRenderer{})
+	rendered, err := renderer.Render(context.Background(), &email.Template{Name: "metrics_test_welcome", Subject: "Hi"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", rendered.Subject)
+
+	assert.Equal(t, before+1, renderCount(t, "metrics_test_welcome"))
+}
@@ -19,9 +19,25 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MailmanService_SendEmail_FullMethodName      = "/mailman.v1.MailmanService/SendEmail"
-	MailmanService_SendEmailBatch_FullMethodName = "/mailman.v1.MailmanService/SendEmailBatch"
-	MailmanService_ListTemplates_FullMethodName  = "/mailman.v1.MailmanService/ListTemplates"
+	MailmanService_SendEmail_FullMethodName         = "/mailman.v1.MailmanService/SendEmail"
+	MailmanService_SendEmailBatch_FullMethodName    = "/mailman.v1.MailmanService/SendEmailBatch"
+	MailmanService_RenderEmail_FullMethodName       = "/mailman.v1.MailmanService/RenderEmail"
+	MailmanService_GetEmailStatus_FullMethodName    = "/mailman.v1.MailmanService/GetEmailStatus"
+	MailmanService_CancelEmail_FullMethodName       = "/mailman.v1.MailmanService/CancelEmail"
+	MailmanService_RescheduleEmail_FullMethodName   = "/mailman.v1.MailmanService/RescheduleEmail"
+	MailmanService_ListTemplates_FullMethodName     = "/mailman.v1.MailmanService/ListTemplates"
+	MailmanService_CreateTemplate_FullMethodName    = "/mailman.v1.MailmanService/CreateTemplate"
+	MailmanService_GetTemplate_FullMethodName       = "/mailman.v1.MailmanService/GetTemplate"
+	MailmanService_UpdateTemplate_FullMethodName    = "/mailman.v1.MailmanService/UpdateTemplate"
+	MailmanService_DeleteTemplate_FullMethodName    = "/mailman.v1.MailmanService/DeleteTemplate"
+	MailmanService_AddSuppression_FullMethodName    = "/mailman.v1.MailmanService/AddSuppression"
+	MailmanService_ListSuppressions_FullMethodName  = "/mailman.v1.MailmanService/ListSuppressions"
+	MailmanService_RemoveSuppression_FullMethodName = "/mailman.v1.MailmanService/RemoveSuppression"
+	MailmanService_ListJobs_FullMethodName          = "/mailman.v1.MailmanService/ListJobs"
+	MailmanService_GetJob_FullMethodName            = "/mailman.v1.MailmanService/GetJob"
+	MailmanService_RetryJobs_FullMethodName         = "/mailman.v1.MailmanService/RetryJobs"
+	MailmanService_CancelJobs_FullMethodName        = "/mailman.v1.MailmanService/CancelJobs"
+	MailmanService_DiscardJobs_FullMethodName       = "/mailman.v1.MailmanService/DiscardJobs"
 )
 
 // MailmanServiceClient is the client API for MailmanService service.
@@ -34,8 +50,40 @@ type MailmanServiceClient interface {
 	SendEmail(ctx context.Context, in *SendEmailRequest, opts ...grpc.CallOption) (*SendEmailResponse, error)
 	// SendEmailBatch enqueues multiple emails in a single request.
 	SendEmailBatch(ctx context.Context, in *SendEmailBatchRequest, opts ...grpc.CallOption) (*SendEmailBatchResponse, error)
+	// RenderEmail renders a template as SendEmail would, without sending anything.
+	RenderEmail(ctx context.Context, in *RenderEmailRequest, opts ...grpc.CallOption) (*RenderEmailResponse, error)
+	// GetEmailStatus reports the delivery state of a previously enqueued email.
+	GetEmailStatus(ctx context.Context, in *GetEmailStatusRequest, opts ...grpc.CallOption) (*GetEmailStatusResponse, error)
+	// CancelEmail stops pending emails from being sent.
+	CancelEmail(ctx context.Context, in *CancelEmailRequest, opts ...grpc.CallOption) (*CancelEmailResponse, error)
+	// RescheduleEmail moves pending emails to a new delivery time.
+	RescheduleEmail(ctx context.Context, in *RescheduleEmailRequest, opts ...grpc.CallOption) (*RescheduleEmailResponse, error)
 	// ListTemplates returns all available email templates.
 	ListTemplates(ctx context.Context, in *ListTemplatesRequest, opts ...grpc.CallOption) (*ListTemplatesResponse, error)
+	// CreateTemplate stores a new email template.
+	CreateTemplate(ctx context.Context, in *CreateTemplateRequest, opts ...grpc.CallOption) (*CreateTemplateResponse, error)
+	// GetTemplate returns a single email template including its bodies.
+	GetTemplate(ctx context.Context, in *GetTemplateRequest, opts ...grpc.CallOption) (*GetTemplateResponse, error)
+	// UpdateTemplate replaces a template's content if its version is unchanged.
+	UpdateTemplate(ctx context.Context, in *UpdateTemplateRequest, opts ...grpc.CallOption) (*UpdateTemplateResponse, error)
+	// DeleteTemplate removes a template that no other template inherits from.
+	DeleteTemplate(ctx context.Context, in *DeleteTemplateRequest, opts ...grpc.CallOption) (*DeleteTemplateResponse, error)
+	// AddSuppression stops emails from being sent to an address.
+	AddSuppression(ctx context.Context, in *AddSuppressionRequest, opts ...grpc.CallOption) (*AddSuppressionResponse, error)
+	// ListSuppressions returns every suppressed address.
+	ListSuppressions(ctx context.Context, in *ListSuppressionsRequest, opts ...grpc.CallOption) (*ListSuppressionsResponse, error)
+	// RemoveSuppression allows emails to be sent to an address again.
+	RemoveSuppression(ctx context.Context, in *RemoveSuppressionRequest, opts ...grpc.CallOption) (*RemoveSuppressionResponse, error)
+	// ListJobs returns enqueued emails matching a filter, newest first.
+	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error)
+	// GetJob returns an enqueued email with the error from every failed attempt.
+	GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*GetJobResponse, error)
+	// RetryJobs makes failed emails available for delivery again.
+	RetryJobs(ctx context.Context, in *RetryJobsRequest, opts ...grpc.CallOption) (*RetryJobsResponse, error)
+	// CancelJobs stops pending emails from being sent.
+	CancelJobs(ctx context.Context, in *CancelJobsRequest, opts ...grpc.CallOption) (*CancelJobsResponse, error)
+	// DiscardJobs deletes failed and cancelled emails.
+	DiscardJobs(ctx context.Context, in *DiscardJobsRequest, opts ...grpc.CallOption) (*DiscardJobsResponse, error)
 }
 
 type mailmanServiceClient struct {
@@ -66,6 +114,46 @@ func (c *mailmanServiceClient) SendEmailBatch(ctx context.Context, in *SendEmail
 	return out, nil
 }
 
+func (c *mailmanServiceClient) RenderEmail(ctx context.Context, in *RenderEmailRequest, opts ...grpc.CallOption) (*RenderEmailResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RenderEmailResponse)
+	err := c.cc.Invoke(ctx, MailmanService_RenderEmail_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailmanServiceClient) GetEmailStatus(ctx context.Context, in *GetEmailStatusRequest, opts ...grpc.CallOption) (*GetEmailStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetEmailStatusResponse)
+	err := c.cc.Invoke(ctx, MailmanService_GetEmailStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailmanServiceClient) CancelEmail(ctx context.Context, in *CancelEmailRequest, opts ...grpc.CallOption) (*CancelEmailResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelEmailResponse)
+	err := c.cc.Invoke(ctx, MailmanService_CancelEmail_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailmanServiceClient) RescheduleEmail(ctx context.Context, in *RescheduleEmailRequest, opts ...grpc.CallOption) (*RescheduleEmailResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RescheduleEmailResponse)
+	err := c.cc.Invoke(ctx, MailmanService_RescheduleEmail_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mailmanServiceClient) ListTemplates(ctx context.Context, in *ListTemplatesRequest, opts ...grpc.CallOption) (*ListTemplatesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListTemplatesResponse)
@@ -76,6 +164,126 @@ func (c *mailmanServiceClient) ListTemplates(ctx context.Context, in *ListTempla
 	return out, nil
 }
 
+func (c *mailmanServiceClient) CreateTemplate(ctx context.Context, in *CreateTemplateRequest, opts ...grpc.CallOption) (*CreateTemplateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateTemplateResponse)
+	err := c.cc.Invoke(ctx, MailmanService_CreateTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailmanServiceClient) GetTemplate(ctx context.Context, in *GetTemplateRequest, opts ...grpc.CallOption) (*GetTemplateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTemplateResponse)
+	err := c.cc.Invoke(ctx, MailmanService_GetTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailmanServiceClient) UpdateTemplate(ctx context.Context, in *UpdateTemplateRequest, opts ...grpc.CallOption) (*UpdateTemplateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateTemplateResponse)
+	err := c.cc.Invoke(ctx, MailmanService_UpdateTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailmanServiceClient) DeleteTemplate(ctx context.Context, in *DeleteTemplateRequest, opts ...grpc.CallOption) (*DeleteTemplateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteTemplateResponse)
+	err := c.cc.Invoke(ctx, MailmanService_DeleteTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailmanServiceClient) AddSuppression(ctx context.Context, in *AddSuppressionRequest, opts ...grpc.CallOption) (*AddSuppressionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddSuppressionResponse)
+	err := c.cc.Invoke(ctx, MailmanService_AddSuppression_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailmanServiceClient) ListSuppressions(ctx context.Context, in *ListSuppressionsRequest, opts ...grpc.CallOption) (*ListSuppressionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSuppressionsResponse)
+	err := c.cc.Invoke(ctx, MailmanService_ListSuppressions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailmanServiceClient) RemoveSuppression(ctx context.Context, in *RemoveSuppressionRequest, opts ...grpc.CallOption) (*RemoveSuppressionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveSuppressionResponse)
+	err := c.cc.Invoke(ctx, MailmanService_RemoveSuppression_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailmanServiceClient) ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListJobsResponse)
+	err := c.cc.Invoke(ctx, MailmanService_ListJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailmanServiceClient) GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*GetJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetJobResponse)
+	err := c.cc.Invoke(ctx, MailmanService_GetJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailmanServiceClient) RetryJobs(ctx context.Context, in *RetryJobsRequest, opts ...grpc.CallOption) (*RetryJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RetryJobsResponse)
+	err := c.cc.Invoke(ctx, MailmanService_RetryJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailmanServiceClient) CancelJobs(ctx context.Context, in *CancelJobsRequest, opts ...grpc.CallOption) (*CancelJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelJobsResponse)
+	err := c.cc.Invoke(ctx, MailmanService_CancelJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailmanServiceClient) DiscardJobs(ctx context.Context, in *DiscardJobsRequest, opts ...grpc.CallOption) (*DiscardJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiscardJobsResponse)
+	err := c.cc.Invoke(ctx, MailmanService_DiscardJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MailmanServiceServer is the server API for MailmanService service.
 // All implementations must embed UnimplementedMailmanServiceServer
 // for forward compatibility.
@@ -86,8 +294,40 @@ type MailmanServiceServer interface {
 	SendEmail(context.Context, *SendEmailRequest) (*SendEmailResponse, error)
 	// SendEmailBatch enqueues multiple emails in a single request.
 	SendEmailBatch(context.Context, *SendEmailBatchRequest) (*SendEmailBatchResponse, error)
+	// RenderEmail renders a template as SendEmail would, without sending anything.
+	RenderEmail(context.Context, *RenderEmailRequest) (*RenderEmailResponse, error)
+	// GetEmailStatus reports the delivery state of a previously enqueued email.
+	GetEmailStatus(context.Context, *GetEmailStatusRequest) (*GetEmailStatusResponse, error)
+	// CancelEmail stops pending emails from being sent.
+	CancelEmail(context.Context, *CancelEmailRequest) (*CancelEmailResponse, error)
+	// RescheduleEmail moves pending emails to a new delivery time.
+	RescheduleEmail(context.Context, *RescheduleEmailRequest) (*RescheduleEmailResponse, error)
 	// ListTemplates returns all available email templates.
 	ListTemplates(context.Context, *ListTemplatesRequest) (*ListTemplatesResponse, error)
+	// CreateTemplate stores a new email template.
+	CreateTemplate(context.Context, *CreateTemplateRequest) (*CreateTemplateResponse, error)
+	// GetTemplate returns a single email template including its bodies.
+	GetTemplate(context.Context, *GetTemplateRequest) (*GetTemplateResponse, error)
+	// UpdateTemplate replaces a template's content if its version is unchanged.
+	UpdateTemplate(context.Context, *UpdateTemplateRequest) (*UpdateTemplateResponse, error)
+	// DeleteTemplate removes a template that no other template inherits from.
+	DeleteTemplate(context.Context, *DeleteTemplateRequest) (*DeleteTemplateResponse, error)
+	// AddSuppression stops emails from being sent to an address.
+	AddSuppression(context.Context, *AddSuppressionRequest) (*AddSuppressionResponse, error)
+	// ListSuppressions returns every suppressed address.
+	ListSuppressions(context.Context, *ListSuppressionsRequest) (*ListSuppressionsResponse, error)
+	// RemoveSuppression allows emails to be sent to an address again.
+	RemoveSuppression(context.Context, *RemoveSuppressionRequest) (*RemoveSuppressionResponse, error)
+	// ListJobs returns enqueued emails matching a filter, newest first.
+	ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error)
+	// GetJob returns an enqueued email with the error from every failed attempt.
+	GetJob(context.Context, *GetJobRequest) (*GetJobResponse, error)
+	// RetryJobs makes failed emails available for delivery again.
+	RetryJobs(context.Context, *RetryJobsRequest) (*RetryJobsResponse, error)
+	// CancelJobs stops pending emails from being sent.
+	CancelJobs(context.Context, *CancelJobsRequest) (*CancelJobsResponse, error)
+	// DiscardJobs deletes failed and cancelled emails.
+	DiscardJobs(context.Context, *DiscardJobsRequest) (*DiscardJobsResponse, error)
 	mustEmbedUnimplementedMailmanServiceServer()
 }
 
@@ -104,9 +344,57 @@ func (UnimplementedMailmanServiceServer) SendEmail(context.Context, *SendEmailRe
 func (UnimplementedMailmanServiceServer) SendEmailBatch(context.Context, *SendEmailBatchRequest) (*SendEmailBatchResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendEmailBatch not implemented")
 }
+func (UnimplementedMailmanServiceServer) RenderEmail(context.Context, *RenderEmailRequest) (*RenderEmailResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenderEmail not implemented")
+}
+func (UnimplementedMailmanServiceServer) GetEmailStatus(context.Context, *GetEmailStatusRequest) (*GetEmailStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEmailStatus not implemented")
+}
+func (UnimplementedMailmanServiceServer) CancelEmail(context.Context, *CancelEmailRequest) (*CancelEmailResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelEmail not implemented")
+}
+func (UnimplementedMailmanServiceServer) RescheduleEmail(context.Context, *RescheduleEmailRequest) (*RescheduleEmailResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RescheduleEmail not implemented")
+}
 func (UnimplementedMailmanServiceServer) ListTemplates(context.Context, *ListTemplatesRequest) (*ListTemplatesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListTemplates not implemented")
 }
+func (UnimplementedMailmanServiceServer) CreateTemplate(context.Context, *CreateTemplateRequest) (*CreateTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTemplate not implemented")
+}
+func (UnimplementedMailmanServiceServer) GetTemplate(context.Context, *GetTemplateRequest) (*GetTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTemplate not implemented")
+}
+func (UnimplementedMailmanServiceServer) UpdateTemplate(context.Context, *UpdateTemplateRequest) (*UpdateTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTemplate not implemented")
+}
+func (UnimplementedMailmanServiceServer) DeleteTemplate(context.Context, *DeleteTemplateRequest) (*DeleteTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTemplate not implemented")
+}
+func (UnimplementedMailmanServiceServer) AddSuppression(context.Context, *AddSuppressionRequest) (*AddSuppressionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddSuppression not implemented")
+}
+func (UnimplementedMailmanServiceServer) ListSuppressions(context.Context, *ListSuppressionsRequest) (*ListSuppressionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSuppressions not implemented")
+}
+func (UnimplementedMailmanServiceServer) RemoveSuppression(context.Context, *RemoveSuppressionRequest) (*RemoveSuppressionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveSuppression not implemented")
+}
+func (UnimplementedMailmanServiceServer) ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedMailmanServiceServer) GetJob(context.Context, *GetJobRequest) (*GetJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJob not implemented")
+}
+func (UnimplementedMailmanServiceServer) RetryJobs(context.Context, *RetryJobsRequest) (*RetryJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetryJobs not implemented")
+}
+func (UnimplementedMailmanServiceServer) CancelJobs(context.Context, *CancelJobsRequest) (*CancelJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelJobs not implemented")
+}
+func (UnimplementedMailmanServiceServer) DiscardJobs(context.Context, *DiscardJobsRequest) (*DiscardJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiscardJobs not implemented")
+}
 func (UnimplementedMailmanServiceServer) mustEmbedUnimplementedMailmanServiceServer() {}
 func (UnimplementedMailmanServiceServer) testEmbeddedByValue()                        {}
 
@@ -164,6 +452,78 @@ func _MailmanService_SendEmailBatch_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MailmanService_RenderEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenderEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailmanServiceServer).RenderEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MailmanService_RenderEmail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailmanServiceServer).RenderEmail(ctx, req.(*RenderEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MailmanService_GetEmailStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEmailStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailmanServiceServer).GetEmailStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MailmanService_GetEmailStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailmanServiceServer).GetEmailStatus(ctx, req.(*GetEmailStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MailmanService_CancelEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailmanServiceServer).CancelEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MailmanService_CancelEmail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailmanServiceServer).CancelEmail(ctx, req.(*CancelEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MailmanService_RescheduleEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RescheduleEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailmanServiceServer).RescheduleEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MailmanService_RescheduleEmail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailmanServiceServer).RescheduleEmail(ctx, req.(*RescheduleEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MailmanService_ListTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListTemplatesRequest)
 	if err := dec(in); err != nil {
@@ -182,6 +542,222 @@ func _MailmanService_ListTemplates_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MailmanService_CreateTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailmanServiceServer).CreateTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MailmanService_CreateTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailmanServiceServer).CreateTemplate(ctx, req.(*CreateTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MailmanService_GetTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailmanServiceServer).GetTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MailmanService_GetTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailmanServiceServer).GetTemplate(ctx, req.(*GetTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MailmanService_UpdateTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailmanServiceServer).UpdateTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MailmanService_UpdateTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailmanServiceServer).UpdateTemplate(ctx, req.(*UpdateTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MailmanService_DeleteTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailmanServiceServer).DeleteTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MailmanService_DeleteTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailmanServiceServer).DeleteTemplate(ctx, req.(*DeleteTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MailmanService_AddSuppression_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddSuppressionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailmanServiceServer).AddSuppression(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MailmanService_AddSuppression_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailmanServiceServer).AddSuppression(ctx, req.(*AddSuppressionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MailmanService_ListSuppressions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSuppressionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailmanServiceServer).ListSuppressions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MailmanService_ListSuppressions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailmanServiceServer).ListSuppressions(ctx, req.(*ListSuppressionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MailmanService_RemoveSuppression_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveSuppressionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailmanServiceServer).RemoveSuppression(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MailmanService_RemoveSuppression_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailmanServiceServer).RemoveSuppression(ctx, req.(*RemoveSuppressionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MailmanService_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailmanServiceServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MailmanService_ListJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailmanServiceServer).ListJobs(ctx, req.(*ListJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MailmanService_GetJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailmanServiceServer).GetJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MailmanService_GetJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailmanServiceServer).GetJob(ctx, req.(*GetJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MailmanService_RetryJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetryJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailmanServiceServer).RetryJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MailmanService_RetryJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailmanServiceServer).RetryJobs(ctx, req.(*RetryJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MailmanService_CancelJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailmanServiceServer).CancelJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MailmanService_CancelJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailmanServiceServer).CancelJobs(ctx, req.(*CancelJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MailmanService_DiscardJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiscardJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailmanServiceServer).DiscardJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MailmanService_DiscardJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailmanServiceServer).DiscardJobs(ctx, req.(*DiscardJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MailmanService_ServiceDesc is the grpc.ServiceDesc for MailmanService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -197,10 +773,74 @@ var MailmanService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendEmailBatch",
 			Handler:    _MailmanService_SendEmailBatch_Handler,
 		},
+		{
+			MethodName: "RenderEmail",
+			Handler:    _MailmanService_RenderEmail_Handler,
+		},
+		{
+			MethodName: "GetEmailStatus",
+			Handler:    _MailmanService_GetEmailStatus_Handler,
+		},
+		{
+			MethodName: "CancelEmail",
+			Handler:    _MailmanService_CancelEmail_Handler,
+		},
+		{
+			MethodName: "RescheduleEmail",
+			Handler:    _MailmanService_RescheduleEmail_Handler,
+		},
 		{
 			MethodName: "ListTemplates",
 			Handler:    _MailmanService_ListTemplates_Handler,
 		},
+		{
+			MethodName: "CreateTemplate",
+			Handler:    _MailmanService_CreateTemplate_Handler,
+		},
+		{
+			MethodName: "GetTemplate",
+			Handler:    _MailmanService_GetTemplate_Handler,
+		},
+		{
+			MethodName: "UpdateTemplate",
+			Handler:    _MailmanService_UpdateTemplate_Handler,
+		},
+		{
+			MethodName: "DeleteTemplate",
+			Handler:    _MailmanService_DeleteTemplate_Handler,
+		},
+		{
+			MethodName: "AddSuppression",
+			Handler:    _MailmanService_AddSuppression_Handler,
+		},
+		{
+			MethodName: "ListSuppressions",
+			Handler:    _MailmanService_ListSuppressions_Handler,
+		},
+		{
+			MethodName: "RemoveSuppression",
+			Handler:    _MailmanService_RemoveSuppression_Handler,
+		},
+		{
+			MethodName: "ListJobs",
+			Handler:    _MailmanService_ListJobs_Handler,
+		},
+		{
+			MethodName: "GetJob",
+			Handler:    _MailmanService_GetJob_Handler,
+		},
+		{
+			MethodName: "RetryJobs",
+			Handler:    _MailmanService_RetryJobs_Handler,
+		},
+		{
+			MethodName: "CancelJobs",
+			Handler:    _MailmanService_CancelJobs_Handler,
+		},
+		{
+			MethodName: "DiscardJobs",
+			Handler:    _MailmanService_DiscardJobs_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "mailman.proto",
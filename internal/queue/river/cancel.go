@@ -0,0 +1,174 @@
+package river
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// jobMetadata is stored alongside email jobs so they can be found without decoding args
+type jobMetadata struct {
+	Tenant          string            `json:"tenant,omitempty"`
+	Template        string            `json:"template,omitempty"`
+	CancellationKey string            `json:"cancellation_key,omitempty"`
+	TraceContext    map[string]string `json:"trace_context,omitempty"`
+}
+
+// pendingStates are the states in which an email has not yet been handed to a
+// worker, so it can still be cancelled or rescheduled
+var pendingStates = []rivertype.JobState{
+	rivertype.JobStateAvailable,
+	rivertype.JobStateScheduled,
+	rivertype.JobStateRetryable,
+}
+
+// CancelEmail stops a pending email from being sent. Cancelling an already
+// cancelled email succeeds; one that is sending or finished fails with ErrMessageNotPending.
+func (c *JobQueue) CancelEmail(ctx context.Context, messageID string) error {
+	job, err := c.getEmailJob(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	if job.State == rivertype.JobStateCancelled {
+		return nil
+	}
+	if !isPending(job.State) {
+		return notPendingError(messageID, job.State)
+	}
+
+	job, err = c.client.JobCancel(ctx, job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to cancel email job: %w", err)
+	}
+
+	// A worker may have picked the job up between the lookup and the cancel
+	if job.State != rivertype.JobStateCancelled {
+		return notPendingError(messageID, job.State)
+	}
+
+	return nil
+}
+
+// CancelEmailGroup cancels every pending email the context's tenant enqueued with
+// the cancellation key and returns how many were cancelled
+func (c *JobQueue) CancelEmailGroup(ctx context.Context, cancellationKey string) (int, error) {
+	metadata, err := json.Marshal(jobMetadata{CancellationKey: cancellationKey})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode job metadata: %w", err)
+	}
+
+	params := river.NewJobListParams().
+		Kinds((email.JobArgs{}).Kind()).
+		Metadata(string(metadata)).
+		States(pendingStates...).
+		First(100)
+
+	cancelled := 0
+	for {
+		result, err := c.client.JobList(ctx, params)
+		if err != nil {
+			return cancelled, fmt.Errorf("failed to list email jobs: %w", err)
+		}
+
+		for _, job := range result.Jobs {
+			// Tenants choose their own keys, so theirs may collide
+			if jobTenant(job) != email.TenantFromContext(ctx) {
+				continue
+			}
+
+			job, err := c.client.JobCancel(ctx, job.ID)
+			if err != nil {
+				return cancelled, fmt.Errorf("failed to cancel email job: %w", err)
+			}
+			if job.State == rivertype.JobStateCancelled {
+				cancelled++
+			}
+		}
+
+		if len(result.Jobs) == 0 || result.LastCursor == nil {
+			return cancelled, nil
+		}
+		params = params.After(result.LastCursor)
+	}
+}
+
+// River has no API for moving a job's run time, so pending jobs are updated
+// directly. Jobs due in the future are parked as scheduled for River's scheduler
+// to promote; jobs due now are made available to workers immediately.
+//
+// Cancelling the job and inserting a new one would go through River's API, but
+// would change the email's message ID. This instead relies on River's job table
+// as of v0.26 (see go.mod): the scheduled_at and state columns, the
+// river_job_state values used here, and River's scheduler promoting scheduled
+// jobs once scheduled_at passes without any other bookkeeping. TestRescheduleEmail
+// in the integration tests checks this against the pinned version, so re-run it
+// when upgrading River.
+const rescheduleSQL = `
+UPDATE river_job
+SET scheduled_at = $2,
+    state = CASE WHEN $2 > now() THEN 'scheduled' ELSE 'available' END::river_job_state
+WHERE kind = $3
+  AND state IN ('available', 'scheduled', 'retryable')`
+
+// RescheduleEmail moves a pending email to a new delivery time
+func (c *JobQueue) RescheduleEmail(ctx context.Context, messageID string, scheduledAt time.Time) error {
+	job, err := c.getEmailJob(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	tag, err := c.pool.Exec(ctx, rescheduleSQL+` AND id = $1`, job.ID, scheduledAt, job.Kind)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule email job: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		// Re-read the state so the error reflects what happened to the job
+		job, err := c.getEmailJob(ctx, messageID)
+		if err != nil {
+			return err
+		}
+		return notPendingError(messageID, job.State)
+	}
+
+	return nil
+}
+
+// RescheduleEmailGroup moves every pending email the context's tenant enqueued with
+// the cancellation key to a new delivery time and returns how many were rescheduled
+func (c *JobQueue) RescheduleEmailGroup(ctx context.Context, cancellationKey string, scheduledAt time.Time) (int, error) {
+	tag, err := c.pool.Exec(ctx, rescheduleSQL+` AND metadata->>'cancellation_key' = $1 AND COALESCE(metadata->>'tenant', $5) = $4`,
+		cancellationKey, scheduledAt, (email.JobArgs{}).Kind(), email.TenantFromContext(ctx), email.DefaultTenant)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reschedule email jobs: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// jobTenant returns the tenant that enqueued a job. Jobs enqueued before tenants
+// existed belong to the default tenant.
+func jobTenant(job *rivertype.JobRow) string {
+	var meta jobMetadata
+	if err := json.Unmarshal(job.Metadata, &meta); err != nil || meta.Tenant == "" {
+		return email.DefaultTenant
+	}
+	return meta.Tenant
+}
+
+// isPending reports whether a job has yet to be handed to a worker
+func isPending(state rivertype.JobState) bool {
+	return slices.Contains(pendingStates, state)
+}
+
+// notPendingError reports that an email can no longer be changed
+func notPendingError(messageID string, state rivertype.JobState) error {
+	return fmt.Errorf("%w: %s is %s", email.ErrMessageNotPending, messageID, convertJobState(state))
+}
@@ -2,32 +2,44 @@ package river
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/riverqueue/river"
 	"github.com/riverqueue/river/riverdriver/riverpgxv5"
+	"github.com/riverqueue/river/rivertype"
 	"github.com/travisbale/mailman/internal/db/postgres"
 	"github.com/travisbale/mailman/internal/email"
+	"github.com/travisbale/mailman/internal/metrics"
 )
 
 // JobQueue wraps the River client for email job processing
 type JobQueue struct {
-	client *river.Client[pgx.Tx]
+	client         *river.Client[pgx.Tx]
+	pool           *pgxpool.Pool
+	idempotencyTTL time.Duration
 }
 
-// NewJobQueue creates a new River-based job queue client
-func NewJobQueue(db *postgres.DB, client EmailClient) (*JobQueue, error) {
+// NewJobQueue creates a new River-based job queue client. Idempotency keys are
+// remembered for idempotencyTTL after the email they enqueued.
+func NewJobQueue(db *postgres.DB, client EmailClient, idempotencyTTL time.Duration) (*JobQueue, error) {
 	emailWorker := NewSendEmailWorker(client)
 	workers := river.NewWorkers()
 	river.AddWorker(workers, emailWorker)
+	river.AddWorker(workers, &idempotencyCleanupWorker{pool: db.Pool()})
 
 	riverConfig := &river.Config{
 		Queues: map[string]river.QueueConfig{
 			river.QueueDefault: {MaxWorkers: 5},
 		},
-		Workers: workers,
+		Workers:      workers,
+		PeriodicJobs: []*river.PeriodicJob{idempotencyCleanupJob()},
+		Logger:       slog.Default(),
 		// Retain job records for debugging failed email deliveries
 		CompletedJobRetentionPeriod: 7 * 24 * time.Hour,
 	}
@@ -38,30 +50,174 @@ func NewJobQueue(db *postgres.DB, client EmailClient) (*JobQueue, error) {
 	}
 
 	return &JobQueue{
-		client: riverClient,
+		client:         riverClient,
+		pool:           db.Pool(),
+		idempotencyTTL: idempotencyTTL,
 	}, nil
 }
 
-// EnqueueEmailJob enqueues a pre-rendered email job to the queue
-func (c *JobQueue) EnqueueEmailJob(ctx context.Context, jobArgs *email.JobArgs) error {
+// EnqueueEmailJob enqueues a pre-rendered email job to the queue and returns
+// its message ID. A repeated idempotency key returns the ID of the original job.
+func (c *JobQueue) EnqueueEmailJob(ctx context.Context, jobArgs *email.JobArgs) (string, error) {
+	// Claiming the key and inserting the job must happen in one transaction
+	if jobArgs.IdempotencyKey != "" {
+		messageIDs, err := c.EnqueueEmailJobs(ctx, []*email.JobArgs{jobArgs})
+		if err != nil {
+			return "", err
+		}
+		return messageIDs[0], nil
+	}
+
+	insertOpts, err := emailInsertOpts(jobArgs)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := c.client.Insert(ctx, jobArgs, insertOpts)
+	if err != nil {
+		metrics.EnqueueErrors.Inc()
+		return "", fmt.Errorf("failed to enqueue email job: %w", err)
+	}
+
+	return formatMessageID(result.Job.ID), nil
+}
+
+// EnqueueEmailJobs enqueues several email jobs in a single transaction, so
+// either all of them are queued or none are. Message IDs are returned in order.
+// Jobs repeating an idempotency key, whether of an earlier request or of another
+// job in the batch, are not enqueued and get the message ID of the original.
+func (c *JobQueue) EnqueueEmailJobs(ctx context.Context, jobs []*email.JobArgs) ([]string, error) {
+	tenant := email.TenantFromContext(ctx)
+
+	tx, err := c.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	messageIDs := make([]string, len(jobs))
+	claimed := make(map[string]int) // Idempotency key -> index of the job enqueued for it
+	repeated := make(map[int]int)   // Index of a job -> index of the earlier job with its key
+	var params []river.InsertManyParams
+	var inserted []int // Index of the job behind each insert
+
+	for i, jobArgs := range jobs {
+		if key := jobArgs.IdempotencyKey; key != "" {
+			if first, ok := claimed[key]; ok {
+				repeated[i] = first
+				continue
+			}
+
+			messageID, held, err := claimIdempotencyKey(ctx, tx, tenant, key, c.idempotencyTTL)
+			if err != nil {
+				return nil, err
+			}
+			if held {
+				messageIDs[i] = messageID
+				continue
+			}
+			claimed[key] = i
+		}
+
+		insertOpts, err := emailInsertOpts(jobArgs)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, river.InsertManyParams{Args: jobArgs, InsertOpts: insertOpts})
+		inserted = append(inserted, i)
+	}
+
+	if len(params) > 0 {
+		results, err := c.client.InsertManyTx(ctx, tx, params)
+		if err != nil {
+			metrics.EnqueueErrors.Add(float64(len(params)))
+			return nil, fmt.Errorf("failed to enqueue email jobs: %w", err)
+		}
+
+		for j, result := range results {
+			i := inserted[j]
+			messageIDs[i] = formatMessageID(result.Job.ID)
+
+			if key := jobs[i].IdempotencyKey; key != "" {
+				if err := recordIdempotencyKey(ctx, tx, tenant, key, result.Job.ID); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	for i, first := range repeated {
+		messageIDs[i] = messageIDs[first]
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		metrics.EnqueueErrors.Add(float64(len(params)))
+		return nil, fmt.Errorf("failed to commit email jobs: %w", err)
+	}
+
+	return messageIDs, nil
+}
+
+// emailInsertOpts builds the River insert options for an email job. Jobs are
+// not deduplicated by args, as two identical emails may both be intended;
+// clients retrying a request send an idempotency key instead.
+func emailInsertOpts(jobArgs *email.JobArgs) (*river.InsertOpts, error) {
 	insertOpts := &river.InsertOpts{
 		MaxAttempts: 4, // Retries handle transient SendGrid API failures
 		Queue:       river.QueueDefault,
-		UniqueOpts: river.UniqueOpts{
-			ByArgs: true, // Prevents sending duplicate emails if client retries request
-		},
 	}
 
 	if jobArgs.ScheduledAt != nil {
 		insertOpts.ScheduledAt = *jobArgs.ScheduledAt
 	}
 
-	_, err := c.client.Insert(ctx, jobArgs, insertOpts)
+	if jobArgs.Tenant != "" || jobArgs.TemplateName != "" || jobArgs.CancellationKey != "" || len(jobArgs.TraceContext) > 0 {
+		metadata, err := json.Marshal(jobMetadata{
+			Tenant:          jobArgs.Tenant,
+			Template:        jobArgs.TemplateName,
+			CancellationKey: jobArgs.CancellationKey,
+			TraceContext:    jobArgs.TraceContext,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode job metadata: %w", err)
+		}
+		insertOpts.Metadata = metadata
+	}
+
+	return insertOpts, nil
+}
+
+// GetEmailStatus looks up the email job identified by messageID
+func (c *JobQueue) GetEmailStatus(ctx context.Context, messageID string) (*email.Status, error) {
+	job, err := c.getEmailJob(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertJobToStatus(job), nil
+}
+
+// getEmailJob fetches the River job behind a message ID, if the context's tenant enqueued it
+func (c *JobQueue) getEmailJob(ctx context.Context, messageID string) (*rivertype.JobRow, error) {
+	jobID, err := parseMessageID(messageID)
 	if err != nil {
-		return fmt.Errorf("failed to enqueue email job: %w", err)
+		return nil, err
 	}
 
-	return nil
+	job, err := c.client.JobGet(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, river.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s", email.ErrMessageNotFound, messageID)
+		}
+		return nil, fmt.Errorf("failed to get email job: %w", err)
+	}
+
+	// Job IDs are shared by every job kind and tenant, so don't leak unrelated jobs
+	if job.Kind != (email.JobArgs{}).Kind() || jobTenant(job) != email.TenantFromContext(ctx) {
+		return nil, fmt.Errorf("%w: %s", email.ErrMessageNotFound, messageID)
+	}
+
+	return job, nil
 }
 
 // Start starts the River job queue workers
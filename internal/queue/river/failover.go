@@ -0,0 +1,200 @@
+package river
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/riverqueue/river"
+	"github.com/travisbale/mailman/internal/email"
+	"github.com/travisbale/mailman/internal/logging"
+	"github.com/travisbale/mailman/internal/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Provider is a named email client that a FailoverClient can deliver through
+type Provider struct {
+	Name   string
+	Client EmailClient
+	Tenant string // The tenant whose own account this is; empty for the server's
+}
+
+// deliveryOutput is recorded on each job that delivers successfully
+type deliveryOutput struct {
+	Provider string `json:"provider"`
+}
+
+// RateLimiter decides whether an email may be handed to a provider yet
+type RateLimiter interface {
+	Reserve(ctx context.Context, args email.JobArgs, provider, owner string) (time.Duration, error)
+}
+
+// TenantProviders looks up the provider a tenant sends through with its own
+// credentials
+type TenantProviders interface {
+	// Provider returns the tenant's own provider, or nil if the tenant sends
+	// through the server's. fallback reports whether the tenant's email may fail
+	// over to the server's providers when its own fails.
+	Provider(ctx context.Context, tenantID string) (provider *Provider, fallback bool, err error)
+}
+
+// FailoverClient delivers each email through the first provider, in order, that
+// accepts it
+type FailoverClient struct {
+	limiter   RateLimiter     // Nil sends without rate limits
+	tenants   TenantProviders // Nil if tenants can't send through their own accounts
+	providers []Provider
+}
+
+// NewFailoverClient creates a client that fails over between providers in the
+// given order. Tenants with their own provider send through it instead. Each provider's rate limits
+// are charged before an email is handed to it.
+func NewFailoverClient(limiter RateLimiter, tenants TenantProviders, providers ...Provider) *FailoverClient {
+	return &FailoverClient{
+		limiter:   limiter,
+		tenants:   tenants,
+		providers: providers,
+	}
+}
+
+// Send tries each provider until one delivers the email, recording which one did.
+// A tenant's own provider is used in place of the server's, unless the tenant
+// opted into failing over to them; otherwise the provider the email names is
+// tried first.
+// Emails rejected outright are not retried elsewhere, since the next provider
+// would reject them too. A provider whose rate limit is exhausted snoozes the
+// email until it has capacity again.
+func (c *FailoverClient) Send(ctx context.Context, args email.JobArgs) error {
+	providers, err := c.order(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, provider := range providers {
+		if err := c.reserve(ctx, provider, args); err != nil {
+			return err
+		}
+
+		err := send(ctx, provider, args)
+		if err == nil {
+			// The email is already out, so failing to record who sent it must not
+			// fail the job and trigger a duplicate delivery
+			_ = river.RecordOutput(ctx, deliveryOutput{Provider: provider.Name})
+			logging.AddAttrs(ctx, "provider", provider.Name)
+			return nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name, err))
+		logging.FromContext(ctx).Debug("Provider failed to deliver email", "provider", provider.Name, "error", err)
+		if errors.Is(err, email.ErrRejected) || ctx.Err() != nil {
+			break
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// order lists the providers in the order to try them. A tenant's own provider
+// is used alone, so its email never goes out under the operator's account,
+// unless the tenant opted into failing over to the server's. Otherwise the
+// email's preferred provider is moved to the front, and must be one the server
+// is configured with.
+func (c *FailoverClient) order(ctx context.Context, args email.JobArgs) ([]Provider, error) {
+	if c.tenants != nil && args.Tenant != "" {
+		own, fallback, err := c.tenants.Provider(ctx, args.Tenant)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load provider for tenant %s: %w", args.Tenant, err)
+		}
+		if own != nil && !fallback {
+			return []Provider{*own}, nil
+		}
+		if own != nil {
+			return append([]Provider{*own}, c.providers...), nil
+		}
+	}
+
+	if args.Provider == "" {
+		return c.providers, nil
+	}
+
+	i := slices.IndexFunc(c.providers, func(p Provider) bool { return p.Name == args.Provider })
+	if i < 0 {
+		return nil, fmt.Errorf("provider %q is not configured on this server", args.Provider)
+	}
+
+	ordered := make([]Provider, 0, len(c.providers))
+	ordered = append(ordered, c.providers[i])
+	ordered = append(ordered, c.providers[:i]...)
+	return append(ordered, c.providers[i+1:]...), nil
+}
+
+// reserve charges an email to the provider's rate limits, returning a snooze
+// error if the provider has no capacity for it yet
+func (c *FailoverClient) reserve(ctx context.Context, provider Provider, args email.JobArgs) error {
+	if c.limiter == nil {
+		return nil
+	}
+
+	wait, err := c.limiter.Reserve(ctx, args, provider.Name, provider.Tenant)
+	if err != nil {
+		return fmt.Errorf("failed to check rate limits: %w", err)
+	}
+	if wait > 0 {
+		trace.SpanFromContext(ctx).AddEvent("rate limited", trace.WithAttributes(
+			attribute.String("mailman.provider", provider.Name),
+			attribute.String("mailman.wait", wait.String()),
+		))
+		logging.FromContext(ctx).Debug("Send rate limited", "provider", provider.Name, "wait", wait)
+		metrics.RateLimited.WithLabelValues(provider.Name).Inc()
+		return river.JobSnooze(wait)
+	}
+
+	return nil
+}
+
+// send delivers an email through a single provider, recording how long it took
+// and whether it was accepted
+func send(ctx context.Context, provider Provider, args email.JobArgs) error {
+	ctx, span := tracer.Start(ctx, "email.Send "+provider.Name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("mailman.provider", provider.Name)),
+	)
+	defer span.End()
+
+	start := time.Now()
+	err := provider.Client.Send(ctx, args)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	outcome := metrics.OutcomeSent
+	if errors.Is(err, email.ErrRejected) {
+		outcome = metrics.OutcomeRejected
+	} else if err != nil {
+		outcome = metrics.OutcomeFailed
+	}
+	metrics.SendDuration.WithLabelValues(provider.Name, outcome).Observe(time.Since(start).Seconds())
+
+	return err
+}
+
+// Close releases any providers that hold open connections, including tenants' own
+func (c *FailoverClient) Close() error {
+	var errs []error
+	if closer, ok := c.tenants.(io.Closer); ok {
+		errs = append(errs, closer.Close())
+	}
+	for _, provider := range c.providers {
+		if closer, ok := provider.Client.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,220 @@
+package river_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	riverqueue "github.com/riverqueue/river"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/internal/email"
+	"github.com/travisbale/mailman/internal/queue/river"
+)
+
+// mockEmailClient fails with err, or succeeds when err is nil, counting its
+// sends and keeping the last email it was given
+type mockEmailClient struct {
+	err   error
+	sends int
+	last  email.JobArgs
+}
+
+func (m *mockEmailClient) Send(_ context.Context, args email.JobArgs) error {
+	m.sends++
+	m.last = args
+	return m.err
+}
+
+func TestFailoverClient_Send(t *testing.T) {
+	t.Parallel()
+
+	outage := errors.New("503 service unavailable")
+	rejected := fmt.Errorf("%w: 550 no such user", email.ErrRejected)
+
+	tests := []struct {
+		name      string
+		errs      []error // One per provider
+		provider  string  // Preferred provider named by the email
+		wantSends []int
+		wantErr   error
+	}{
+		{
+			name:      "primary delivers",
+			errs:      []error{nil, nil},
+			wantSends: []int{1, 0},
+		},
+		{
+			name:      "fails over to secondary",
+			errs:      []error{outage, nil},
+			wantSends: []int{1, 1},
+		},
+		{
+			name:      "all providers fail",
+			errs:      []error{outage, outage},
+			wantSends: []int{1, 1},
+			wantErr:   outage,
+		},
+		{
+			name:      "rejected email is not failed over",
+			errs:      []error{rejected, nil},
+			wantSends: []int{1, 0},
+			wantErr:   email.ErrRejected,
+		},
+		{
+			name:      "preferred provider delivers",
+			errs:      []error{nil, nil},
+			provider:  "provider1",
+			wantSends: []int{0, 1},
+		},
+		{
+			name:      "preferred provider fails over to the rest",
+			errs:      []error{nil, outage},
+			provider:  "provider1",
+			wantSends: []int{1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			clients := make([]*mockEmailClient, len(tt.errs))
+			providers := make([]river.Provider, len(tt.errs))
+			for i, err := range tt.errs {
+				clients[i] = &mockEmailClient{err: err}
+				providers[i] = river.Provider{Name: fmt.Sprintf("provider%d", i), Client: clients[i]}
+			}
+
+			err := river.NewFailoverClient(nil, nil, providers...).Send(context.Background(), email.JobArgs{Provider: tt.provider})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			for i, client := range clients {
+				assert.Equal(t, tt.wantSends[i], client.sends, "sends via provider%d", i)
+			}
+		})
+	}
+}
+
+func TestFailoverClient_Send_UnknownProvider(t *testing.T) {
+	t.Parallel()
+
+	client := &mockEmailClient{}
+	failover := river.NewFailoverClient(nil, nil, river.Provider{Name: "smtp", Client: client})
+
+	// A provider the server isn't configured with fails the send rather than
+	// quietly delivering through another
+	err := failover.Send(context.Background(), email.JobArgs{Provider: "sendgrid"})
+	require.ErrorContains(t, err, `provider "sendgrid" is not configured`)
+	assert.Zero(t, client.sends)
+}
+
+// fakeTenantProviders returns each tenant's own provider, if it has one, and
+// lets the tenants in fallback fail over to the server's
+type fakeTenantProviders struct {
+	providers map[string]*river.Provider
+	fallback  map[string]bool
+	err       error
+}
+
+func (f *fakeTenantProviders) Provider(_ context.Context, tenantID string) (*river.Provider, bool, error) {
+	return f.providers[tenantID], f.fallback[tenantID], f.err
+}
+
+func TestFailoverClient_Send_TenantProvider(t *testing.T) {
+	t.Parallel()
+
+	outage := errors.New("503 service unavailable")
+
+	own := &mockEmailClient{err: outage}
+	server := &mockEmailClient{}
+	tenants := &fakeTenantProviders{
+		providers: map[string]*river.Provider{
+			"payroll": {Name: "sendgrid", Client: own},
+			"hr":      {Name: "sendgrid", Client: own},
+		},
+		fallback: map[string]bool{"hr": true},
+	}
+	failover := river.NewFailoverClient(nil, tenants, river.Provider{Name: "smtp", Client: server})
+
+	// A tenant's own account is never failed over to the server's
+	require.ErrorIs(t, failover.Send(context.Background(), email.JobArgs{Tenant: "payroll", Provider: "sendgrid"}), outage)
+	assert.Equal(t, 1, own.sends)
+	assert.Zero(t, server.sends)
+
+	// unless the tenant opted in
+	require.NoError(t, failover.Send(context.Background(), email.JobArgs{Tenant: "hr", Provider: "sendgrid"}))
+	assert.Equal(t, 2, own.sends)
+	assert.Equal(t, 1, server.sends)
+
+	// Tenants without their own account send through the server's
+	require.NoError(t, failover.Send(context.Background(), email.JobArgs{Tenant: "billing"}))
+	assert.Equal(t, 2, own.sends)
+	assert.Equal(t, 2, server.sends)
+
+	// Failing to look up the tenant's provider fails the attempt so it is retried
+	tenants.err = errors.New("connection refused")
+	require.Error(t, failover.Send(context.Background(), email.JobArgs{Tenant: "payroll"}))
+	assert.Equal(t, 2, server.sends)
+}
+
+// mockRateLimiter records the providers charged, snoozing those in exhausted
+type mockRateLimiter struct {
+	exhausted map[string]time.Duration
+	charged   []string
+}
+
+func (m *mockRateLimiter) Reserve(_ context.Context, _ email.JobArgs, provider, owner string) (time.Duration, error) {
+	if owner != "" {
+		provider = owner + "/" + provider
+	}
+	if wait := m.exhausted[provider]; wait > 0 {
+		return wait, nil
+	}
+	m.charged = append(m.charged, provider)
+	return 0, nil
+}
+
+func TestFailoverClient_Send_RateLimits(t *testing.T) {
+	t.Parallel()
+
+	outage := errors.New("503 service unavailable")
+
+	// The provider that delivers the email is charged, not only the primary
+	limiter := &mockRateLimiter{}
+	primary := &mockEmailClient{err: outage}
+	secondary := &mockEmailClient{}
+	failover := river.NewFailoverClient(limiter, nil,
+		river.Provider{Name: "sendgrid", Client: primary},
+		river.Provider{Name: "smtp", Client: secondary},
+	)
+	require.NoError(t, failover.Send(context.Background(), email.JobArgs{}))
+	assert.Equal(t, []string{"sendgrid", "smtp"}, limiter.charged)
+
+	// An exhausted limit snoozes the email without sending it
+	limiter = &mockRateLimiter{exhausted: map[string]time.Duration{"smtp": 2 * time.Second}}
+	failover = river.NewFailoverClient(limiter, nil,
+		river.Provider{Name: "sendgrid", Client: primary},
+		river.Provider{Name: "smtp", Client: secondary},
+	)
+	err := failover.Send(context.Background(), email.JobArgs{})
+	var snooze *riverqueue.JobSnoozeError
+	require.ErrorAs(t, err, &snooze)
+	assert.Equal(t, 2*time.Second, snooze.Duration)
+	assert.Equal(t, 1, secondary.sends)
+
+	// A tenant's own account is charged separately from the server's
+	limiter = &mockRateLimiter{}
+	tenants := &fakeTenantProviders{providers: map[string]*river.Provider{
+		"payroll": {Name: "sendgrid", Client: &mockEmailClient{}, Tenant: "payroll"},
+	}}
+	failover = river.NewFailoverClient(limiter, tenants, river.Provider{Name: "sendgrid", Client: primary})
+	require.NoError(t, failover.Send(context.Background(), email.JobArgs{Tenant: "payroll"}))
+	assert.Equal(t, []string{"payroll/sendgrid"}, limiter.charged)
+}
@@ -0,0 +1,161 @@
+package river
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// Claiming a key records it, or takes it over once it has expired. No row is
+// returned while another request holds the key. A concurrent request with the
+// same key blocks on the row until the first transaction ends, so only one of
+// them enqueues an email. Once committed, a key without a job is one whose email
+// suppression dropped.
+const claimIdempotencyKeySQL = `
+INSERT INTO idempotency_keys (tenant_id, key, expires_at)
+VALUES ($1, $2, now() + make_interval(secs => $3))
+ON CONFLICT (tenant_id, key) DO UPDATE
+SET job_id = NULL, created_at = now(), expires_at = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at <= now()
+RETURNING true`
+
+// claimIdempotencyKey claims a key for a job about to be enqueued in tx. If the
+// key is still held, held is true and the message ID of the job holding it is
+// returned instead, which is empty if that email was dropped.
+func claimIdempotencyKey(ctx context.Context, tx pgx.Tx, tenant, key string, ttl time.Duration) (messageID string, held bool, err error) {
+	var claimed bool
+	err = tx.QueryRow(ctx, claimIdempotencyKeySQL, tenant, key, ttl.Seconds()).Scan(&claimed)
+	if err == nil {
+		return "", false, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return "", false, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+
+	var jobID *int64
+	err = tx.QueryRow(ctx, `SELECT job_id FROM idempotency_keys WHERE tenant_id = $1 AND key = $2`, tenant, key).Scan(&jobID)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to look up idempotency key: %w", err)
+	}
+	if jobID == nil {
+		return "", true, nil
+	}
+
+	return formatMessageID(*jobID), true, nil
+}
+
+// LookupIdempotencyKeys returns the message ID of the email each unexpired key
+// was used for, keyed by idempotency key. Keys whose email was dropped map to
+// an empty message ID, and unused keys are left out.
+func (c *JobQueue) LookupIdempotencyKeys(ctx context.Context, keys []string) (map[string]string, error) {
+	rows, err := c.pool.Query(ctx, `
+		SELECT key, job_id FROM idempotency_keys
+		WHERE tenant_id = $1 AND key = ANY($2) AND expires_at > now()`,
+		email.TenantFromContext(ctx), keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up idempotency keys: %w", err)
+	}
+	defer rows.Close()
+
+	sent := make(map[string]string)
+	for rows.Next() {
+		var key string
+		var jobID *int64
+		if err := rows.Scan(&key, &jobID); err != nil {
+			return nil, fmt.Errorf("failed to read idempotency key: %w", err)
+		}
+
+		sent[key] = ""
+		if jobID != nil {
+			sent[key] = formatMessageID(*jobID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to look up idempotency keys: %w", err)
+	}
+
+	return sent, nil
+}
+
+// RecordDroppedEmails uses up the idempotency keys of emails whose recipients
+// were all dropped, so repeating them returns the same empty result. Keys that
+// were meanwhile used to send an email return its message ID, in order.
+func (c *JobQueue) RecordDroppedEmails(ctx context.Context, idempotencyKeys []string) ([]string, error) {
+	tenant := email.TenantFromContext(ctx)
+
+	tx, err := c.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	messageIDs := make([]string, len(idempotencyKeys))
+	for i, key := range idempotencyKeys {
+		// A claimed key is left without a job, marking the email as dropped
+		if messageIDs[i], _, err = claimIdempotencyKey(ctx, tx, tenant, key, c.idempotencyTTL); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit idempotency keys: %w", err)
+	}
+
+	return messageIDs, nil
+}
+
+// recordIdempotencyKey stores the job enqueued for a claimed key
+func recordIdempotencyKey(ctx context.Context, tx pgx.Tx, tenant, key string, jobID int64) error {
+	_, err := tx.Exec(ctx, `UPDATE idempotency_keys SET job_id = $3 WHERE tenant_id = $1 AND key = $2`, tenant, key, jobID)
+	if err != nil {
+		return fmt.Errorf("failed to record idempotency key: %w", err)
+	}
+	return nil
+}
+
+// idempotencyCleanupInterval is how often expired idempotency keys are deleted
+const idempotencyCleanupInterval = time.Hour
+
+// idempotencyCleanupArgs are the args of the periodic job that deletes expired
+// idempotency keys
+type idempotencyCleanupArgs struct{}
+
+// Kind returns the unique identifier for this job type
+func (idempotencyCleanupArgs) Kind() string { return "idempotency_key_cleanup" }
+
+// idempotencyCleanupWorker deletes expired idempotency keys. Expired keys are
+// already ignored when claiming, so this only keeps the table small.
+type idempotencyCleanupWorker struct {
+	river.WorkerDefaults[idempotencyCleanupArgs]
+	pool *pgxpool.Pool
+}
+
+// Work deletes every expired idempotency key
+func (w *idempotencyCleanupWorker) Work(ctx context.Context, _ *river.Job[idempotencyCleanupArgs]) error {
+	tag, err := w.pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= now()`)
+	if err != nil {
+		return fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	slog.Debug("Deleted expired idempotency keys", "count", tag.RowsAffected())
+	return nil
+}
+
+// idempotencyCleanupJob schedules the cleanup worker. River only runs periodic
+// jobs on the elected leader, so one replica cleans up for all of them.
+func idempotencyCleanupJob() *river.PeriodicJob {
+	return river.NewPeriodicJob(
+		river.PeriodicInterval(idempotencyCleanupInterval),
+		func() (river.JobArgs, *river.InsertOpts) {
+			return idempotencyCleanupArgs{}, nil
+		},
+		&river.PeriodicJobOpts{RunOnStart: true},
+	)
+}
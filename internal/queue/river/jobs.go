@@ -0,0 +1,192 @@
+package river
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// Bounds on the number of jobs listed at once
+const (
+	defaultJobPageSize = 50
+	maxJobPageSize     = 1000
+)
+
+// ListEmailJobs returns a page of the context's tenant's email jobs matching the
+// filter, newest first, and a token for the next page that is empty on the last
+// page
+func (c *JobQueue) ListEmailJobs(ctx context.Context, filter email.JobFilter, pageSize int, pageToken string) ([]*email.Job, string, error) {
+	if pageSize <= 0 {
+		pageSize = defaultJobPageSize
+	}
+
+	params := river.NewJobListParams().
+		Kinds((email.JobArgs{}).Kind()).
+		OrderBy(river.JobListOrderByID, river.SortOrderDesc).
+		First(min(pageSize, maxJobPageSize)).
+		Where(`COALESCE(metadata->>'tenant', @default_tenant) = @tenant`, river.NamedArgs{
+			"default_tenant": email.DefaultTenant,
+			"tenant":         email.TenantFromContext(ctx),
+		})
+
+	if len(filter.States) > 0 {
+		params = params.States(jobStates(filter.States)...)
+	}
+	if filter.TemplateName != "" {
+		params = params.Where(`metadata->>'template' = @template`, river.NamedArgs{"template": filter.TemplateName})
+	}
+	if filter.Recipient != "" {
+		// Empty recipient lists are stored as null, and jobs enqueued before
+		// multiple recipients were supported stored To as a bare address
+		params = params.Where(`(lower(args->>'To') = @recipient OR EXISTS (
+			SELECT 1
+			FROM (VALUES ('To'), ('CC'), ('BCC')) AS list (field),
+				jsonb_array_elements(CASE jsonb_typeof(args->list.field) WHEN 'array' THEN args->list.field ELSE '[]' END) AS recipient
+			WHERE lower(recipient->>'Email') = @recipient
+		))`, river.NamedArgs{"recipient": filter.Recipient})
+	}
+	if filter.CreatedAfter != nil {
+		params = params.Where(`created_at >= @created_after`, river.NamedArgs{"created_after": *filter.CreatedAfter})
+	}
+	if filter.CreatedBefore != nil {
+		params = params.Where(`created_at < @created_before`, river.NamedArgs{"created_before": *filter.CreatedBefore})
+	}
+
+	if pageToken != "" {
+		cursor := &river.JobListCursor{}
+		if err := cursor.UnmarshalText([]byte(pageToken)); err != nil {
+			return nil, "", fmt.Errorf("%w: %v", email.ErrInvalidPageToken, err)
+		}
+		params = params.After(cursor)
+	}
+
+	result, err := c.client.JobList(ctx, params)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list email jobs: %w", err)
+	}
+
+	jobs := make([]*email.Job, len(result.Jobs))
+	for i, job := range result.Jobs {
+		jobs[i] = convertJobToJob(job)
+	}
+
+	// A short page is the last one
+	if len(result.Jobs) < min(pageSize, maxJobPageSize) || result.LastCursor == nil {
+		return jobs, "", nil
+	}
+
+	next, err := result.LastCursor.MarshalText()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode page token: %w", err)
+	}
+
+	return jobs, string(next), nil
+}
+
+// GetEmailJob returns the email job identified by messageID
+func (c *JobQueue) GetEmailJob(ctx context.Context, messageID string) (*email.Job, error) {
+	job, err := c.getEmailJob(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertJobToJob(job), nil
+}
+
+// RetryEmail makes a failed or retrying email available to workers immediately.
+// River allows failed jobs one more attempt than they have already made.
+func (c *JobQueue) RetryEmail(ctx context.Context, messageID string) error {
+	job, err := c.getEmailJob(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	if job.State != rivertype.JobStateDiscarded && job.State != rivertype.JobStateRetryable {
+		return fmt.Errorf("%w: %s is %s", email.ErrMessageNotFailed, messageID, convertJobState(job.State))
+	}
+
+	if _, err := c.client.JobRetry(ctx, job.ID); err != nil {
+		return fmt.Errorf("failed to retry email job: %w", err)
+	}
+
+	return nil
+}
+
+// DiscardEmail deletes a failed or cancelled email job
+func (c *JobQueue) DiscardEmail(ctx context.Context, messageID string) error {
+	job, err := c.getEmailJob(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	if job.State != rivertype.JobStateDiscarded && job.State != rivertype.JobStateCancelled {
+		return fmt.Errorf("%w: %s is %s", email.ErrMessageNotFinished, messageID, convertJobState(job.State))
+	}
+
+	if _, err := c.client.JobDelete(ctx, job.ID); err != nil {
+		if errors.Is(err, river.ErrNotFound) {
+			return fmt.Errorf("%w: %s", email.ErrMessageNotFound, messageID)
+		}
+		return fmt.Errorf("failed to discard email job: %w", err)
+	}
+
+	return nil
+}
+
+// convertJobToJob maps a River job row onto the job shown to operators
+func convertJobToJob(row *rivertype.JobRow) *email.Job {
+	job := &email.Job{Status: *convertJobToStatus(row)}
+
+	var args email.JobArgs
+	if err := json.Unmarshal(row.EncodedArgs, &args); err == nil {
+		job.To = args.To
+		job.CC = args.CC
+		job.BCC = args.BCC
+		job.From = email.Address{Name: args.FromName, Email: args.From}
+		job.Subject = args.Subject
+	}
+
+	var meta jobMetadata
+	if err := json.Unmarshal(row.Metadata, &meta); err == nil {
+		job.TemplateName = meta.Template
+	}
+
+	for _, attemptErr := range row.Errors {
+		job.Errors = append(job.Errors, email.JobError{
+			Attempt:    attemptErr.Attempt,
+			Error:      attemptErr.Error,
+			OccurredAt: attemptErr.At,
+		})
+	}
+
+	return job
+}
+
+// jobStates maps the states exposed to callers back onto River's job states
+func jobStates(states []email.State) []rivertype.JobState {
+	var jobStates []rivertype.JobState
+	for _, state := range states {
+		switch state {
+		case email.StateScheduled:
+			jobStates = append(jobStates, rivertype.JobStateScheduled)
+		case email.StateQueued:
+			jobStates = append(jobStates, rivertype.JobStateAvailable, rivertype.JobStatePending)
+		case email.StateSending:
+			jobStates = append(jobStates, rivertype.JobStateRunning)
+		case email.StateSent:
+			jobStates = append(jobStates, rivertype.JobStateCompleted)
+		case email.StateRetrying:
+			jobStates = append(jobStates, rivertype.JobStateRetryable)
+		case email.StateFailed:
+			jobStates = append(jobStates, rivertype.JobStateDiscarded)
+		case email.StateCancelled:
+			jobStates = append(jobStates, rivertype.JobStateCancelled)
+		}
+	}
+	return jobStates
+}
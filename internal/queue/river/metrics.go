@@ -0,0 +1,82 @@
+package river
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// depthQueryTimeout bounds the queue depth query so a slow database can't stall scrapes
+const depthQueryTimeout = 5 * time.Second
+
+var queueDepthDesc = prometheus.NewDesc(
+	"mailman_queue_jobs",
+	"Email jobs in the queue, by state.",
+	[]string{"state"}, nil,
+)
+
+// depthCollector reports how many email jobs are in each state when scraped
+type depthCollector struct {
+	countJobs func(ctx context.Context) (map[rivertype.JobState]int64, error)
+}
+
+// DepthCollector returns a Prometheus collector for the number of email jobs in
+// each state, counted when metrics are scraped
+func (c *JobQueue) DepthCollector() prometheus.Collector {
+	return &depthCollector{countJobs: func(ctx context.Context) (map[rivertype.JobState]int64, error) {
+		return countJobs(ctx, c.pool)
+	}}
+}
+
+// Describe implements prometheus.Collector
+func (d *depthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueDepthDesc
+}
+
+// Collect implements prometheus.Collector
+func (d *depthCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), depthQueryTimeout)
+	defer cancel()
+
+	counts, err := d.countJobs(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(queueDepthDesc, err)
+		return
+	}
+
+	// Report every state so empty states read as zero rather than disappearing
+	for _, state := range rivertype.JobStates() {
+		ch <- prometheus.MustNewConstMetric(queueDepthDesc, prometheus.GaugeValue, float64(counts[state]), string(state))
+	}
+}
+
+// countJobs counts the email jobs in each state
+func countJobs(ctx context.Context, pool *pgxpool.Pool) (map[rivertype.JobState]int64, error) {
+	rows, err := pool.Query(ctx, `SELECT state, count(*) FROM river_job WHERE kind = $1 GROUP BY state`,
+		(email.JobArgs{}).Kind())
+	if err != nil {
+		return nil, fmt.Errorf("failed to count email jobs: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[rivertype.JobState]int64)
+	for rows.Next() {
+		var state string
+		var count int64
+		if err := rows.Scan(&state, &count); err != nil {
+			return nil, fmt.Errorf("failed to count email jobs: %w", err)
+		}
+		counts[rivertype.JobState(state)] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count email jobs: %w", err)
+	}
+
+	return counts, nil
+}
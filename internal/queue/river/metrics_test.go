@@ -0,0 +1,138 @@
+package river
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	riverqueue "github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/internal/email"
+	"github.com/travisbale/mailman/internal/metrics"
+)
+
+// The metrics are shared by the whole process, so these tests use provider
+// names of their own and do not run in parallel with tests that send emails.
+
+type stubEmailClient struct {
+	err error
+}
+
+func (s stubEmailClient) Send(_ context.Context, _ email.JobArgs) error {
+	return s.err
+}
+
+type stubRateLimiter struct {
+	wait time.Duration
+}
+
+func (s stubRateLimiter) Reserve(_ context.Context, _ email.JobArgs, _, _ string) (time.Duration, error) {
+	return s.wait, nil
+}
+
+// sendCount returns how many delivery attempts the provider has recorded with the outcome
+func sendCount(t *testing.T, provider, outcome string) uint64 {
+	t.Helper()
+
+	var m dto.Metric
+	require.NoError(t, metrics.SendDuration.WithLabelValues(provider, outcome).(prometheus.Metric).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestMetrics_SendDuration(t *testing.T) {
+	rejected := fmt.Errorf("%w: 550 no such user", email.ErrRejected)
+
+	tests := []struct {
+		name    string
+		err     error
+		outcome string
+	}{
+		{name: "sent", outcome: metrics.OutcomeSent},
+		{name: "failed", err: errors.New("503 service unavailable"), outcome: metrics.OutcomeFailed},
+		{name: "rejected", err: rejected, outcome: metrics.OutcomeRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := "metrics-test-" + tt.name
+			before := sendCount(t, provider, tt.outcome)
+
+			client := NewFailoverClient(nil, nil, Provider{Name: provider, Client: stubEmailClient{err: tt.err}})
+			_ = client.Send(context.Background(), email.JobArgs{})
+
+			assert.Equal(t, before+1, sendCount(t, provider, tt.outcome))
+		})
+	}
+}
+
+func TestMetrics_RateLimited(t *testing.T) {
+	provider := "metrics-test-limited"
+	limited := metrics.RateLimited.WithLabelValues(provider)
+	before := testutil.ToFloat64(limited)
+	sends := sendCount(t, provider, metrics.OutcomeSent)
+
+	client := NewFailoverClient(stubRateLimiter{wait: time.Second}, nil, Provider{Name: provider, Client: stubEmailClient{}})
+	var snooze *riverqueue.JobSnoozeError
+	require.ErrorAs(t, client.Send(context.Background(), email.JobArgs{}), &snooze)
+
+	assert.Equal(t, before+1, testutil.ToFloat64(limited))
+	assert.Equal(t, sends, sendCount(t, provider, metrics.OutcomeSent), "a snoozed email is not sent")
+}
+
+func TestMetrics_SendRetries(t *testing.T) {
+	worker := NewSendEmailWorker(stubEmailClient{})
+	before := testutil.ToFloat64(metrics.SendRetries)
+
+	// A first attempt is not a retry
+	first := &riverqueue.Job[email.JobArgs]{JobRow: &rivertype.JobRow{ID: 1, Attempt: 1, MaxAttempts: 5}}
+	require.NoError(t, worker.Work(context.Background(), first))
+	assert.Equal(t, before, testutil.ToFloat64(metrics.SendRetries))
+
+	retry := &riverqueue.Job[email.JobArgs]{JobRow: &rivertype.JobRow{
+		ID:          1,
+		Attempt:     2,
+		MaxAttempts: 5,
+		Errors:      []rivertype.AttemptError{{Attempt: 1, Error: "503 service unavailable"}},
+	}}
+	require.NoError(t, worker.Work(context.Background(), retry))
+	assert.Equal(t, before+1, testutil.ToFloat64(metrics.SendRetries))
+}
+
+func TestDepthCollector(t *testing.T) {
+	t.Parallel()
+
+	collector := &depthCollector{countJobs: func(context.Context) (map[rivertype.JobState]int64, error) {
+		return map[rivertype.JobState]int64{
+			rivertype.JobStateAvailable: 3,
+			rivertype.JobStateRetryable: 1,
+		}, nil
+	}}
+
+	// States without jobs are reported as zero
+	expected := `
+# HELP mailman_queue_jobs Email jobs in the queue, by state.
+# TYPE mailman_queue_jobs gauge
+mailman_queue_jobs{state="available"} 3
+mailman_queue_jobs{state="cancelled"} 0
+mailman_queue_jobs{state="completed"} 0
+mailman_queue_jobs{state="discarded"} 0
+mailman_queue_jobs{state="pending"} 0
+mailman_queue_jobs{state="retryable"} 1
+mailman_queue_jobs{state="running"} 0
+mailman_queue_jobs{state="scheduled"} 0
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+
+	failing := &depthCollector{countJobs: func(context.Context) (map[rivertype.JobState]int64, error) {
+		return nil, errors.New("connection refused")
+	}}
+	assert.Error(t, testutil.CollectAndCompare(failing, strings.NewReader("")))
+}
@@ -0,0 +1,70 @@
+package river
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/riverqueue/river/rivertype"
+	"github.com/travisbale/mailman/internal/email"
+)
+
+// formatMessageID exposes a River job ID as an opaque message ID
+func formatMessageID(jobID int64) string {
+	return strconv.FormatInt(jobID, 10)
+}
+
+// parseMessageID converts a message ID back into the River job ID it was derived from
+func parseMessageID(messageID string) (int64, error) {
+	jobID, err := strconv.ParseInt(messageID, 10, 64)
+	if err != nil || jobID <= 0 {
+		return 0, fmt.Errorf("%w: %s", email.ErrMessageNotFound, messageID)
+	}
+	return jobID, nil
+}
+
+// convertJobToStatus maps a River job row onto the domain delivery status
+func convertJobToStatus(job *rivertype.JobRow) *email.Status {
+	status := &email.Status{
+		MessageID:   formatMessageID(job.ID),
+		State:       convertJobState(job.State),
+		Attempts:    job.Attempt,
+		MaxAttempts: job.MaxAttempts,
+		CreatedAt:   job.CreatedAt,
+		ScheduledAt: job.ScheduledAt,
+		AttemptedAt: job.AttemptedAt,
+		FinalizedAt: job.FinalizedAt,
+	}
+
+	if len(job.Errors) > 0 {
+		status.LastError = job.Errors[len(job.Errors)-1].Error
+	}
+
+	var output deliveryOutput
+	if err := json.Unmarshal(job.Output(), &output); err == nil {
+		status.Provider = output.Provider
+	}
+
+	return status
+}
+
+// convertJobState maps River's job states onto the states exposed to callers
+func convertJobState(state rivertype.JobState) email.State {
+	switch state {
+	case rivertype.JobStateScheduled:
+		return email.StateScheduled
+	case rivertype.JobStateRunning:
+		return email.StateSending
+	case rivertype.JobStateCompleted:
+		return email.StateSent
+	case rivertype.JobStateRetryable:
+		return email.StateRetrying
+	case rivertype.JobStateDiscarded:
+		return email.StateFailed
+	case rivertype.JobStateCancelled:
+		return email.StateCancelled
+	default:
+		// available and pending jobs are waiting for a worker
+		return email.StateQueued
+	}
+}
@@ -2,12 +2,24 @@ package river
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
 	"github.com/travisbale/mailman/internal/email"
+	"github.com/travisbale/mailman/internal/logging"
+	"github.com/travisbale/mailman/internal/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/travisbale/mailman/internal/queue/river")
+
 // EmailClient defines the interface for delivering pre-rendered emails
 type EmailClient interface {
 	Send(ctx context.Context, args email.JobArgs) error
@@ -26,11 +38,72 @@ func NewSendEmailWorker(client EmailClient) *SendEmailWorker {
 	}
 }
 
-// Work delivers a pre-rendered email via the configured client
+// Work delivers a pre-rendered email via the configured client. Each attempt is
+// traced as part of the trace of the request that enqueued the email.
 func (w *SendEmailWorker) Work(ctx context.Context, job *river.Job[email.JobArgs]) error {
-	if err := w.client.Send(ctx, job.Args); err != nil {
-		return fmt.Errorf("failed to send email: %w", err)
+	args := job.Args
+	args.MessageID = formatMessageID(job.ID)
+	args.Tenant = jobTenant(job.JobRow)
+	args.TemplateName = jobTemplate(job.JobRow)
+
+	ctx, span := tracer.Start(withJobTraceContext(ctx, job.Metadata), "email.Deliver",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("mailman.message_id", args.MessageID),
+			attribute.String("mailman.template", args.TemplateName),
+			attribute.Int("mailman.attempt", job.Attempt),
+		),
+	)
+	defer span.End()
+
+	ctx = logging.NewContext(ctx, "message_id", args.MessageID, "tenant", args.Tenant, "template", args.TemplateName, "attempt", job.Attempt)
+
+	if len(job.Errors) > 0 {
+		metrics.SendRetries.Inc()
 	}
 
+	err := w.client.Send(ctx, args)
+
+	// Snoozing reschedules a rate limited job without using up one of its attempts
+	var snooze *river.JobSnoozeError
+	if errors.As(err, &snooze) {
+		return err
+	}
+
+	if err != nil {
+		err = fmt.Errorf("failed to send email: %w", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		logging.FromContext(ctx).Warn("Delivery attempt failed", "max_attempts", job.MaxAttempts, "error", err)
+
+		// The provider would reject the email again, so no attempts are left to retry it
+		if errors.Is(err, email.ErrRejected) {
+			return river.JobCancel(err)
+		}
+		return err
+	}
+
+	logging.FromContext(ctx).Info("Email delivered")
 	return nil
 }
+
+// jobTemplate returns the name of the template an email was rendered from, or
+// empty for emails enqueued before jobs recorded their template
+func jobTemplate(job *rivertype.JobRow) string {
+	var meta jobMetadata
+	if err := json.Unmarshal(job.Metadata, &meta); err != nil {
+		return ""
+	}
+	return meta.Template
+}
+
+// withJobTraceContext restores the trace context stored in a job's metadata, so
+// spans started from the returned context belong to the enqueuing request's trace
+func withJobTraceContext(ctx context.Context, metadata []byte) context.Context {
+	var meta jobMetadata
+	if err := json.Unmarshal(metadata, &meta); err != nil || len(meta.TraceContext) == 0 {
+		return ctx
+	}
+
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(meta.TraceContext))
+}
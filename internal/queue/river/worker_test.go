@@ -0,0 +1,58 @@
+package river_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	riverqueue "github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/internal/email"
+	"github.com/travisbale/mailman/internal/queue/river"
+)
+
+func TestSendEmailWorker_Work(t *testing.T) {
+	t.Parallel()
+
+	outage := errors.New("503 service unavailable")
+	rejected := fmt.Errorf("%w: 550 no such user", email.ErrRejected)
+
+	job := &riverqueue.Job[email.JobArgs]{JobRow: &rivertype.JobRow{ID: 1, Attempt: 1, MaxAttempts: 5}}
+
+	// Transient failures are retried
+	err := river.NewSendEmailWorker(&mockEmailClient{err: outage}).Work(context.Background(), job)
+	require.ErrorIs(t, err, outage)
+	var cancel *riverqueue.JobCancelError
+	assert.False(t, errors.As(err, &cancel))
+
+	// Rejected emails are cancelled rather than sent again on every attempt
+	err = river.NewSendEmailWorker(&mockEmailClient{err: rejected}).Work(context.Background(), job)
+	require.ErrorAs(t, err, &cancel)
+	assert.ErrorIs(t, err, email.ErrRejected)
+
+	require.NoError(t, river.NewSendEmailWorker(&mockEmailClient{}).Work(context.Background(), job))
+}
+
+func TestSendEmailWorker_Work_JobMetadata(t *testing.T) {
+	t.Parallel()
+
+	client := &mockEmailClient{}
+	job := &riverqueue.Job[email.JobArgs]{JobRow: &rivertype.JobRow{
+		ID:          42,
+		Attempt:     1,
+		MaxAttempts: 5,
+		Metadata:    []byte(`{"tenant":"payroll","template":"welcome"}`),
+	}}
+	require.NoError(t, river.NewSendEmailWorker(client).Work(context.Background(), job))
+	assert.Equal(t, "payroll", client.last.Tenant)
+	assert.Equal(t, "welcome", client.last.TemplateName)
+
+	// Emails enqueued before jobs recorded their template have none
+	job.Metadata = []byte(`{}`)
+	require.NoError(t, river.NewSendEmailWorker(client).Work(context.Background(), job))
+	assert.Equal(t, email.DefaultTenant, client.last.Tenant)
+	assert.Empty(t, client.last.TemplateName)
+}
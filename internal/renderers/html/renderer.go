@@ -5,51 +5,86 @@ import (
 	"context"
 	"fmt"
 	"html/template"
+	"strings"
+	"sync"
 
 	"github.com/travisbale/mailman/internal/email"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// TemplateDB defines the interface for fetching templates from the database.
-type TemplateDB interface {
-	GetTemplate(ctx context.Context, name string) (*email.Template, error)
-}
+// maxParsed caps how many parsed inheritance chains are kept. Each pinned version
+// sent is parsed under its own key, so the cache starts over once it is full.
+const maxParsed = 1000
 
-// Renderer renders HTML email templates using templates stored in the database.
+// Renderer renders HTML email templates along with the base templates they
+// inherit from, caching each parsed inheritance chain.
 type Renderer struct {
-	db TemplateDB
+	mu     sync.RWMutex
+	parsed map[string]*parsedTemplate
+}
+
+// parsedTemplate holds the parsed parts of a template and its bases
+type parsedTemplate struct {
+	subject *template.Template
+	html    *template.Template
+	text    *template.Template // Nil if the template has no text body
 }
 
 // New creates a new HTML renderer
-func New(db TemplateDB) *Renderer {
+func New() *Renderer {
 	return &Renderer{
-		db: db,
+		parsed: make(map[string]*parsedTemplate),
 	}
 }
 
-// Render renders an email template by fetching it from the database and executing it with the provided variables.
-func (r *Renderer) Render(ctx context.Context, templateName string, variables map[string]string) (*email.RenderedTemplate, error) {
-	tmpl, err := r.db.GetTemplate(ctx, templateName)
+var tracer = otel.Tracer("github.com/travisbale/mailman/internal/renderers/html")
+
+// Render executes an email template with the provided variables. Base templates
+// must already be resolved through Template.Base.
+func (r *Renderer) Render(ctx context.Context, tmpl *email.Template, variables map[string]any) (*email.RenderedTemplate, error) {
+	_, span := tracer.Start(ctx, "html.Render", trace.WithAttributes(
+		attribute.String("mailman.template", tmpl.Name),
+		attribute.String("mailman.locale", tmpl.Locale),
+		attribute.Int("mailman.template_version", int(tmpl.Version)),
+	))
+	defer span.End()
+
+	rendered, err := r.render(tmpl, variables)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return rendered, err
+}
+
+// render checks the variables, then executes the parsed template
+func (r *Renderer) render(tmpl *email.Template, variables map[string]any) (*email.RenderedTemplate, error) {
+	if err := tmpl.CheckVariables(variables); err != nil {
 		return nil, err
 	}
 
-	if err := validateTemplate(tmpl, variables); err != nil {
+	parsed, err := r.parse(tmpl)
+	if err != nil {
 		return nil, err
 	}
 
-	subject, err := renderString(tmpl.Subject, variables)
+	subject, err := execute(parsed.subject, variables)
 	if err != nil {
 		return nil, fmt.Errorf("failed to render subject: %w", err)
 	}
 
-	htmlBody, err := r.renderHTMLWithBase(ctx, tmpl, variables)
+	htmlBody, err := execute(parsed.html, variables)
 	if err != nil {
 		return nil, fmt.Errorf("failed to render HTML body: %w", err)
 	}
 
 	textBody := ""
-	if tmpl.TextBody != nil && *tmpl.TextBody != "" {
-		textBody, err = r.renderTextWithBase(ctx, tmpl, variables)
+	if parsed.text != nil {
+		textBody, err = execute(parsed.text, variables)
 		if err != nil {
 			return nil, fmt.Errorf("failed to render text body: %w", err)
 		}
@@ -62,79 +97,57 @@ func (r *Renderer) Render(ctx context.Context, templateName string, variables ma
 	}, nil
 }
 
-// renderHTMLWithBase renders HTML body, loading base templates if needed
-func (r *Renderer) renderHTMLWithBase(ctx context.Context, tmpl *email.Template, variables map[string]string) (string, error) {
-	if tmpl.BaseTemplateName == nil || *tmpl.BaseTemplateName == "" {
-		return renderString(tmpl.HTMLBody, variables)
-	}
+// Reset discards every parsed template. Published versions never change, so this
+// only frees memory held for versions that are no longer sent.
+func (r *Renderer) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.parsed = make(map[string]*parsedTemplate)
+}
 
-	templates, err := r.loadTemplateChain(ctx, tmpl, func(t *email.Template) string {
-		return t.HTMLBody
-	})
+// parse returns the parsed inheritance chain of a template, parsing it on first use
+func (r *Renderer) parse(tmpl *email.Template) (*parsedTemplate, error) {
+	chain, err := loadTemplateChain(tmpl)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	// Parse in reverse order so base templates can reference child {{define}} blocks
-	tmplSet := template.New("base")
-	for i := len(templates) - 1; i >= 0; i-- {
-		_, err := tmplSet.Parse(templates[i])
-		if err != nil {
-			return "", fmt.Errorf("failed to parse template in chain: %w", err)
+	key, cacheable := chainKey(chain)
+	if cacheable {
+		r.mu.RLock()
+		parsed, ok := r.parsed[key]
+		r.mu.RUnlock()
+		if ok {
+			return parsed, nil
 		}
 	}
 
-	var buf bytes.Buffer
-	if err := tmplSet.Execute(&buf, variables); err != nil {
-		return "", fmt.Errorf("failed to execute template: %w", err)
-	}
-
-	return buf.String(), nil
-}
-
-// renderTextWithBase renders text body, loading base templates if needed
-func (r *Renderer) renderTextWithBase(ctx context.Context, tmpl *email.Template, variables map[string]string) (string, error) {
-	if tmpl.BaseTemplateName == nil || *tmpl.BaseTemplateName == "" {
-		return renderString(*tmpl.TextBody, variables)
-	}
-
-	templates, err := r.loadTemplateChain(ctx, tmpl, func(t *email.Template) string {
-		if t.TextBody != nil {
-			return *t.TextBody
-		}
-		return ""
-	})
+	parsed, err := parseChain(chain)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	tmplSet := template.New("base")
-	for i := len(templates) - 1; i >= 0; i-- {
-		if templates[i] != "" {
-			_, err := tmplSet.Parse(templates[i])
-			if err != nil {
-				return "", fmt.Errorf("failed to parse template in chain: %w", err)
-			}
+	if cacheable {
+		r.mu.Lock()
+		if len(r.parsed) >= maxParsed {
+			r.parsed = make(map[string]*parsedTemplate)
 		}
+		r.parsed[key] = parsed
+		r.mu.Unlock()
 	}
 
-	var buf bytes.Buffer
-	if err := tmplSet.Execute(&buf, variables); err != nil {
-		return "", fmt.Errorf("failed to execute template: %w", err)
-	}
-
-	return buf.String(), nil
+	return parsed, nil
 }
 
-// loadTemplateChain recursively loads all templates in the inheritance chain
-func (r *Renderer) loadTemplateChain(ctx context.Context, tmpl *email.Template, extract func(*email.Template) string) ([]string, error) {
-	result := []string{extract(tmpl)}
+// loadTemplateChain lists a template followed by each base it inherits from
+func loadTemplateChain(tmpl *email.Template) ([]*email.Template, error) {
+	chain := []*email.Template{tmpl}
 
-	current := tmpl
 	seen := make(map[string]bool)
-	seen[current.Name] = true
+	seen[tmpl.Name] = true
 
-	for current.BaseTemplateName != nil && *current.BaseTemplateName != "" {
+	for current := tmpl; current.BaseTemplateName != nil && *current.BaseTemplateName != ""; current = current.Base {
 		baseName := *current.BaseTemplateName
 
 		// Safety net: circular references should be caught at creation time
@@ -143,39 +156,86 @@ func (r *Renderer) loadTemplateChain(ctx context.Context, tmpl *email.Template,
 		}
 		seen[baseName] = true
 
-		base, err := r.db.GetTemplate(ctx, baseName)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load base template %s: %w", baseName, err)
+		if current.Base == nil {
+			return nil, fmt.Errorf("failed to load base template %s: not resolved", baseName)
 		}
 
-		result = append(result, extract(base))
-		current = base
+		chain = append(chain, current.Base)
 	}
 
-	return result, nil
+	return chain, nil
+}
+
+// chainKey identifies the content of an inheritance chain by the tenant, name, locale
+// and version of each template in it. Templates that were never stored have no version
+// to identify them by, so chains containing one are not cached.
+func chainKey(chain []*email.Template) (string, bool) {
+	var key strings.Builder
+	for _, t := range chain {
+		if t.Version == 0 {
+			return "", false
+		}
+		fmt.Fprintf(&key, "%s\x00%s\x00%s\x00%d\x00", t.TenantID, t.Name, t.Locale, t.Version)
+	}
+	return key.String(), true
 }
 
-// renderString renders a single string template with variables
-func renderString(templateStr string, variables map[string]string) (string, error) {
-	tmpl, err := template.New("email").Parse(templateStr)
+// parseChain parses the subject of the first template in the chain, and the
+// bodies of every template in it
+func parseChain(chain []*email.Template) (*parsedTemplate, error) {
+	var parsed parsedTemplate
+	var err error
+
+	parsed.subject, err = template.New("email").Parse(chain[0].Subject)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse template: %w", err)
+		return nil, fmt.Errorf("failed to render subject: failed to parse template: %w", err)
 	}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, variables); err != nil {
-		return "", fmt.Errorf("failed to execute template: %w", err)
+	htmlBodies := make([]string, len(chain))
+	for i, t := range chain {
+		htmlBodies[i] = t.HTMLBody
 	}
 
-	return buf.String(), nil
+	parsed.html, err = parseBodies(htmlBodies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render HTML body: %w", err)
+	}
+
+	if chain[0].TextBody != nil && *chain[0].TextBody != "" {
+		textBodies := make([]string, 0, len(chain))
+		for _, t := range chain {
+			if t.TextBody != nil && *t.TextBody != "" {
+				textBodies = append(textBodies, *t.TextBody)
+			}
+		}
+
+		parsed.text, err = parseBodies(textBodies)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render text body: %w", err)
+		}
+	}
+
+	return &parsed, nil
 }
 
-// validateTemplate checks if all required variables are provided
-func validateTemplate(tmpl *email.Template, variables map[string]string) error {
-	for _, required := range tmpl.Variables {
-		if _, ok := variables[required]; !ok {
-			return fmt.Errorf("missing variable: %s", required)
+// parseBodies parses bodies into one template set, base templates first so they
+// can reference child {{define}} blocks
+func parseBodies(bodies []string) (*template.Template, error) {
+	tmplSet := template.New("email")
+	for i := len(bodies) - 1; i >= 0; i-- {
+		if _, err := tmplSet.Parse(bodies[i]); err != nil {
+			return nil, fmt.Errorf("failed to parse template: %w", err)
 		}
 	}
-	return nil
+	return tmplSet, nil
+}
+
+// execute renders a parsed template with variables
+func execute(tmpl *template.Template, variables map[string]any) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, variables); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return buf.String(), nil
 }
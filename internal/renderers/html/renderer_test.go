@@ -2,7 +2,6 @@ package html_test
 
 import (
 	"context"
-	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -12,36 +11,20 @@ import (
 	"github.com/travisbale/mailman/internal/email"
 )
 
-type mockTemplateDB struct {
-	templates map[string]*email.Template
-}
-
-func (m *mockTemplateDB) GetTemplate(ctx context.Context, name string) (*email.Template, error) {
-	tmpl, ok := m.templates[name]
-	if !ok {
-		return nil, fmt.Errorf("template not found: %s", name)
-	}
-	return tmpl, nil
-}
-
 func strPtr(s string) *string { return &s }
 
 func TestRenderer_SimpleTemplate(t *testing.T) {
 	t.Parallel()
 
-	db := &mockTemplateDB{
-		templates: map[string]*email.Template{
-			"welcome": {
-				Name:      "welcome",
-				Subject:   "Welcome {{index . \"UserName\"}}!",
-				HTMLBody:  "<h1>Hello {{index . \"UserName\"}}</h1>",
-				Variables: []string{"UserName"},
-			},
-		},
+	tmpl := &email.Template{
+		Name:      "welcome",
+		Subject:   "Welcome {{index . \"UserName\"}}!",
+		HTMLBody:  "<h1>Hello {{index . \"UserName\"}}</h1>",
+		Variables: []string{"UserName"},
 	}
 
-	r := htmlrenderer.New(db)
-	result, err := r.Render(context.Background(), "welcome", map[string]string{
+	r := htmlrenderer.New()
+	result, err := r.Render(context.Background(), tmpl, map[string]any{
 		"UserName": "Alice",
 	})
 
@@ -54,20 +37,16 @@ func TestRenderer_SimpleTemplate(t *testing.T) {
 func TestRenderer_TemplateWithTextBody(t *testing.T) {
 	t.Parallel()
 
-	db := &mockTemplateDB{
-		templates: map[string]*email.Template{
-			"notify": {
-				Name:      "notify",
-				Subject:   "Notification",
-				HTMLBody:  "<p>{{index . \"Message\"}}</p>",
-				TextBody:  strPtr("{{index . \"Message\"}}"),
-				Variables: []string{"Message"},
-			},
-		},
+	tmpl := &email.Template{
+		Name:      "notify",
+		Subject:   "Notification",
+		HTMLBody:  "<p>{{index . \"Message\"}}</p>",
+		TextBody:  strPtr("{{index . \"Message\"}}"),
+		Variables: []string{"Message"},
 	}
 
-	r := htmlrenderer.New(db)
-	result, err := r.Render(context.Background(), "notify", map[string]string{
+	r := htmlrenderer.New()
+	result, err := r.Render(context.Background(), tmpl, map[string]any{
 		"Message": "Hello world",
 	})
 
@@ -80,19 +59,15 @@ func TestRenderer_TemplateWithTextBody(t *testing.T) {
 func TestRenderer_TemplateWithoutTextBody(t *testing.T) {
 	t.Parallel()
 
-	db := &mockTemplateDB{
-		templates: map[string]*email.Template{
-			"html_only": {
-				Name:     "html_only",
-				Subject:  "HTML Only",
-				HTMLBody: "<p>Content</p>",
-				TextBody: nil,
-			},
-		},
+	tmpl := &email.Template{
+		Name:     "html_only",
+		Subject:  "HTML Only",
+		HTMLBody: "<p>Content</p>",
+		TextBody: nil,
 	}
 
-	r := htmlrenderer.New(db)
-	result, err := r.Render(context.Background(), "html_only", map[string]string{})
+	r := htmlrenderer.New()
+	result, err := r.Render(context.Background(), tmpl, map[string]any{})
 
 	require.NoError(t, err)
 	assert.Equal(t, "<p>Content</p>", result.HTMLBody)
@@ -102,29 +77,25 @@ func TestRenderer_TemplateWithoutTextBody(t *testing.T) {
 func TestRenderer_NestedTemplateWithBase(t *testing.T) {
 	t.Parallel()
 
-	db := &mockTemplateDB{
-		templates: map[string]*email.Template{
-			"base_layout": {
-				Name:    "base_layout",
-				Subject: "",
-				HTMLBody: `<html><body>` +
-					`<header>MyApp</header>` +
-					`{{template "content" .}}` +
-					`<footer>Footer</footer>` +
-					`</body></html>`,
-			},
-			"child_email": {
-				Name:             "child_email",
-				Subject:          "Hello {{index . \"UserName\"}}",
-				HTMLBody:         `{{define "content"}}<p>Welcome {{index . "UserName"}}!</p>{{end}}`,
-				BaseTemplateName: strPtr("base_layout"),
-				Variables:        []string{"UserName"},
-			},
+	tmpl := &email.Template{
+		Name:             "child_email",
+		Subject:          "Hello {{index . \"UserName\"}}",
+		HTMLBody:         `{{define "content"}}<p>Welcome {{index . "UserName"}}!</p>{{end}}`,
+		BaseTemplateName: strPtr("base_layout"),
+		Base: &email.Template{
+			Name:    "base_layout",
+			Subject: "",
+			HTMLBody: `<html><body>` +
+				`<header>MyApp</header>` +
+				`{{template "content" .}}` +
+				`<footer>Footer</footer>` +
+				`</body></html>`,
 		},
+		Variables: []string{"UserName"},
 	}
 
-	r := htmlrenderer.New(db)
-	result, err := r.Render(context.Background(), "child_email", map[string]string{
+	r := htmlrenderer.New()
+	result, err := r.Render(context.Background(), tmpl, map[string]any{
 		"UserName": "Bob",
 	})
 
@@ -135,22 +106,44 @@ func TestRenderer_NestedTemplateWithBase(t *testing.T) {
 	assert.Contains(t, result.HTMLBody, "<footer>Footer</footer>")
 }
 
-func TestRenderer_MissingVariable(t *testing.T) {
+func TestRenderer_StructuredVariables(t *testing.T) {
 	t.Parallel()
 
-	db := &mockTemplateDB{
-		templates: map[string]*email.Template{
-			"requires_vars": {
-				Name:      "requires_vars",
-				Subject:   "Hi {{index . \"UserName\"}}",
-				HTMLBody:  "<p>body</p>",
-				Variables: []string{"UserName", "ResetLink"},
+	tmpl := &email.Template{
+		Name:      "receipt",
+		Subject:   "Order {{.Order.ID}}",
+		HTMLBody:  `<ul>{{range .Order.Items}}<li>{{.Quantity}} x {{.Name}}</li>{{end}}</ul>`,
+		Variables: []string{"Order.ID", "Order.Items"},
+	}
+
+	r := htmlrenderer.New()
+	result, err := r.Render(context.Background(), tmpl, map[string]any{
+		"Order": map[string]any{
+			"ID": "A-100",
+			"Items": []any{
+				map[string]any{"Name": "Mug", "Quantity": float64(2)},
+				map[string]any{"Name": "<Poster>", "Quantity": float64(1)},
 			},
 		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Order A-100", result.Subject)
+	assert.Equal(t, "<ul><li>2 x Mug</li><li>1 x &lt;Poster&gt;</li></ul>", result.HTMLBody)
+}
+
+func TestRenderer_MissingVariable(t *testing.T) {
+	t.Parallel()
+
+	tmpl := &email.Template{
+		Name:      "requires_vars",
+		Subject:   "Hi {{index . \"UserName\"}}",
+		HTMLBody:  "<p>body</p>",
+		Variables: []string{"UserName", "ResetLink"},
 	}
 
-	r := htmlrenderer.New(db)
-	_, err := r.Render(context.Background(), "requires_vars", map[string]string{
+	r := htmlrenderer.New()
+	_, err := r.Render(context.Background(), tmpl, map[string]any{
 		// UserName is missing
 		"ResetLink": "https://example.com/reset",
 	})
@@ -159,43 +152,92 @@ func TestRenderer_MissingVariable(t *testing.T) {
 	assert.Contains(t, err.Error(), "UserName")
 }
 
-func TestRenderer_TemplateNotFound(t *testing.T) {
+func TestRenderer_BaseTemplateNotFound(t *testing.T) {
 	t.Parallel()
 
-	db := &mockTemplateDB{
-		templates: map[string]*email.Template{},
+	tmpl := &email.Template{
+		Name:             "orphan",
+		Subject:          "Subject",
+		HTMLBody:         `{{define "content"}}Content{{end}}`,
+		BaseTemplateName: strPtr("nonexistent"),
 	}
 
-	r := htmlrenderer.New(db)
-	_, err := r.Render(context.Background(), "nonexistent", map[string]string{})
+	r := htmlrenderer.New()
+	_, err := r.Render(context.Background(), tmpl, map[string]any{})
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "nonexistent")
 }
 
+func TestRenderer_CachesParsedVersions(t *testing.T) {
+	t.Parallel()
+
+	r := htmlrenderer.New()
+	render := func(tmpl *email.Template) string {
+		result, err := r.Render(context.Background(), tmpl, map[string]any{})
+		require.NoError(t, err)
+		return result.HTMLBody
+	}
+
+	v1 := &email.Template{Name: "welcome", HTMLBody: "<p>v1</p>", Version: 1}
+	v2 := &email.Template{Name: "welcome", HTMLBody: "<p>v2</p>", Version: 2}
+	assert.Equal(t, "<p>v1</p>", render(v1))
+	assert.Equal(t, "<p>v2</p>", render(v2))
+
+	// Published versions never change, so a version is only parsed once
+	assert.Equal(t, "<p>v1</p>", render(&email.Template{Name: "welcome", HTMLBody: "<p>edited</p>", Version: 1}))
+
+	// Each tenant has its own namespace of templates and versions
+	assert.Equal(t, "<p>other</p>", render(&email.Template{TenantID: "other", Name: "welcome", HTMLBody: "<p>other</p>", Version: 1}))
+
+	r.Reset()
+	assert.Equal(t, "<p>edited</p>", render(&email.Template{Name: "welcome", HTMLBody: "<p>edited</p>", Version: 1}))
+
+	// Templates that were never stored are not cached
+	assert.Equal(t, "<p>a</p>", render(&email.Template{Name: "draft", HTMLBody: "<p>a</p>"}))
+	assert.Equal(t, "<p>b</p>", render(&email.Template{Name: "draft", HTMLBody: "<p>b</p>"}))
+}
+
+func TestRenderer_CacheIsBounded(t *testing.T) {
+	t.Parallel()
+
+	r := htmlrenderer.New()
+	render := func(tmpl *email.Template) string {
+		result, err := r.Render(context.Background(), tmpl, map[string]any{})
+		require.NoError(t, err)
+		return result.HTMLBody
+	}
+
+	for version := int32(1); version <= 1000; version++ {
+		render(&email.Template{Name: "welcome", HTMLBody: "<p>v</p>", Version: version})
+	}
+	assert.Equal(t, "<p>v</p>", render(&email.Template{Name: "welcome", HTMLBody: "<p>edited</p>", Version: 1}))
+
+	// A full cache starts over rather than growing with every pinned version
+	render(&email.Template{Name: "welcome", HTMLBody: "<p>v</p>", Version: 1001})
+	assert.Equal(t, "<p>edited</p>", render(&email.Template{Name: "welcome", HTMLBody: "<p>edited</p>", Version: 1}))
+}
+
 func TestRenderer_CircularReferenceDetection(t *testing.T) {
 	t.Parallel()
 
-	// Template A -> Template B -> Template A (cycle)
-	db := &mockTemplateDB{
-		templates: map[string]*email.Template{
-			"template_a": {
-				Name:             "template_a",
-				Subject:          "Subject",
-				HTMLBody:         `{{define "content"}}Content A{{end}}`,
-				BaseTemplateName: strPtr("template_b"),
-			},
-			"template_b": {
-				Name:             "template_b",
-				Subject:          "",
-				HTMLBody:         `{{template "content" .}}`,
-				BaseTemplateName: strPtr("template_a"),
-			},
+	// Template A -> Template B -> Template A (cycle). Resolution stops at the
+	// repeated template, leaving B's base unresolved.
+	tmpl := &email.Template{
+		Name:             "template_a",
+		Subject:          "Subject",
+		HTMLBody:         `{{define "content"}}Content A{{end}}`,
+		BaseTemplateName: strPtr("template_b"),
+		Base: &email.Template{
+			Name:             "template_b",
+			Subject:          "",
+			HTMLBody:         `{{template "content" .}}`,
+			BaseTemplateName: strPtr("template_a"),
 		},
 	}
 
-	r := htmlrenderer.New(db)
-	_, err := r.Render(context.Background(), "template_a", map[string]string{})
+	r := htmlrenderer.New()
+	_, err := r.Render(context.Background(), tmpl, map[string]any{})
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "circular")
@@ -6,8 +6,13 @@ import (
 	"fmt"
 
 	"github.com/travisbale/mailman/internal/email"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/travisbale/mailman/internal/renderers/json")
+
 // Renderer renders email templates as JSON for testing
 type Renderer struct{}
 
@@ -18,17 +23,22 @@ func New() *Renderer {
 
 // emailData represents the JSON structure of a rendered email
 type emailData struct {
-	Template  string            `json:"template"`
-	Variables map[string]string `json:"variables"`
-	Subject   string            `json:"subject"`
+	Template  string         `json:"template"`
+	Locale    string         `json:"locale,omitempty"` // Variant that was rendered
+	Variables map[string]any `json:"variables"`
+	Subject   string         `json:"subject"`
 }
 
 // Render renders an email template as JSON with the template name and all variables.
-func (r *Renderer) Render(ctx context.Context, templateName string, variables map[string]string) (*email.RenderedTemplate, error) {
+func (r *Renderer) Render(ctx context.Context, tmpl *email.Template, variables map[string]any) (*email.RenderedTemplate, error) {
+	_, span := tracer.Start(ctx, "json.Render", trace.WithAttributes(attribute.String("mailman.template", tmpl.Name)))
+	defer span.End()
+
 	data := emailData{
-		Template:  templateName,
+		Template:  tmpl.Name,
+		Locale:    tmpl.Locale,
 		Variables: variables,
-		Subject:   fmt.Sprintf("[%s]", templateName),
+		Subject:   fmt.Sprintf("[%s]", tmpl.Name),
 	}
 
 	jsonBody, err := json.MarshalIndent(data, "", "  ")
@@ -6,8 +6,13 @@ import (
 	"strings"
 
 	"github.com/travisbale/mailman/internal/email"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/travisbale/mailman/internal/renderers/text")
+
 // template represents a hardcoded text template
 type template struct {
 	subject           string
@@ -38,10 +43,15 @@ func New() *Renderer {
 }
 
 // Render renders an email template using hardcoded templates and simple variable substitution.
-func (r *Renderer) Render(ctx context.Context, templateName string, variables map[string]string) (*email.RenderedTemplate, error) {
-	tmpl, exists := templates[templateName]
+// Only the name of the requested template is used; its content comes from the hardcoded set,
+// which is not localized.
+func (r *Renderer) Render(ctx context.Context, requested *email.Template, variables map[string]any) (*email.RenderedTemplate, error) {
+	_, span := tracer.Start(ctx, "text.Render", trace.WithAttributes(attribute.String("mailman.template", requested.Name)))
+	defer span.End()
+
+	tmpl, exists := templates[requested.Name]
 	if !exists {
-		return nil, fmt.Errorf("template not found: %s", templateName)
+		return nil, fmt.Errorf("template not found: %s", requested.Name)
 	}
 
 	for _, required := range tmpl.requiredVariables {
@@ -55,8 +65,8 @@ func (r *Renderer) Render(ctx context.Context, templateName string, variables ma
 
 	for key, value := range variables {
 		placeholder := fmt.Sprintf("{{.%s}}", key)
-		subject = strings.ReplaceAll(subject, placeholder, value)
-		textBody = strings.ReplaceAll(textBody, placeholder, value)
+		subject = strings.ReplaceAll(subject, placeholder, fmt.Sprint(value))
+		textBody = strings.ReplaceAll(textBody, placeholder, fmt.Sprint(value))
 	}
 
 	return &email.RenderedTemplate{
@@ -2,12 +2,21 @@ package sdk
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/travisbale/mailman/internal/pb"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -23,6 +32,12 @@ type GRPCClientOption func(*grpcClientConfig)
 type grpcClientConfig struct {
 	dialOptions []grpc.DialOption
 	timeout     time.Duration
+	apiKey      string
+
+	tls            bool
+	caFile         string // Empty trusts the system's root CAs
+	clientCertFile string
+	clientKeyFile  string
 }
 
 // WithDialOptions allows setting custom gRPC dial options
@@ -39,6 +54,75 @@ func WithTimeout(timeout time.Duration) GRPCClientOption {
 	}
 }
 
+// WithAPIKey authenticates every call with an API key issued by `mailman apikey create`
+func WithAPIKey(apiKey string) GRPCClientOption {
+	return func(c *grpcClientConfig) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithTLS connects over TLS, verifying the server against the PEM CA bundle in
+// caFile, or against the system's root CAs when caFile is empty
+func WithTLS(caFile string) GRPCClientOption {
+	return func(c *grpcClientConfig) {
+		c.tls = true
+		c.caFile = caFile
+	}
+}
+
+// WithClientCertificate presents a PEM certificate and key to servers that require
+// mutual TLS. It implies WithTLS; combine the two to also set the CA bundle.
+func WithClientCertificate(certFile, keyFile string) GRPCClientOption {
+	return func(c *grpcClientConfig) {
+		c.tls = true
+		c.clientCertFile = certFile
+		c.clientKeyFile = keyFile
+	}
+}
+
+// tlsConfig builds the client TLS configuration from the TLS options
+func (c *grpcClientConfig) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if c.caFile != "" {
+		pem, err := os.ReadFile(c.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.New("failed to load CA bundle: no PEM certificates found")
+		}
+	}
+
+	if c.clientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.clientCertFile, c.clientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// apiKeyCredentials sends an API key as a bearer token with every call
+type apiKeyCredentials struct {
+	apiKey string
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials
+func (a apiKeyCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + a.apiKey}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials. Keys may be
+// sent over plaintext connections for local development; use TLS in production.
+func (a apiKeyCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
 // NewGRPCClient creates a new gRPC client for the mailman API
 // address should be in the format "host:port" (e.g., "localhost:50051")
 func NewGRPCClient(address string, opts ...GRPCClientOption) (*GRPCClient, error) {
@@ -51,6 +135,16 @@ func NewGRPCClient(address string, opts ...GRPCClientOption) (*GRPCClient, error
 		opt(config)
 	}
 
+	if config.tls {
+		tlsConfig, err := config.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		config.dialOptions = append(config.dialOptions,
+			grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
+		)
+	}
+
 	// Add default dial options if none provided
 	if len(config.dialOptions) == 0 {
 		config.dialOptions = append(config.dialOptions,
@@ -58,6 +152,17 @@ func NewGRPCClient(address string, opts ...GRPCClientOption) (*GRPCClient, error
 		)
 	}
 
+	if config.apiKey != "" {
+		config.dialOptions = append(config.dialOptions,
+			grpc.WithPerRPCCredentials(apiKeyCredentials{apiKey: config.apiKey}),
+		)
+	}
+
+	// Allow requests large enough to carry the maximum attachment payload
+	config.dialOptions = append(config.dialOptions,
+		grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(MaxMessageSize)),
+	)
+
 	// Establish connection
 	conn, err := grpc.NewClient(address, config.dialOptions...)
 	if err != nil {
@@ -85,12 +190,28 @@ func (c *GRPCClient) SendEmail(ctx context.Context, req SendEmailRequest) (*Send
 		return nil, fmt.Errorf("invalid request: %w", err)
 	}
 
+	data, err := convertDataToProto(req.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
 	// Convert to protobuf request
 	pbReq := &pb.SendEmailRequest{
-		TemplateId: req.TemplateID,
-		To:         req.To,
-		Variables:  req.Variables,
-		Priority:   req.Priority,
+		TemplateId:      req.TemplateID,
+		TemplateVersion: req.TemplateVersion,
+		Locale:          req.Locale,
+		To:              req.toRecipients(),
+		Cc:              req.CC,
+		Bcc:             req.BCC,
+		ReplyTo:         req.ReplyTo,
+		Sender:          req.Sender,
+		CancellationKey: req.CancellationKey,
+		IdempotencyKey:  req.IdempotencyKey,
+		Variables:       req.Variables,
+		Data:            data,
+		Attachments:     convertAttachmentsToProto(req.Attachments),
+		Priority:        req.Priority,
+		DryRun:          req.DryRun,
 	}
 
 	if req.ScheduledAt != nil {
@@ -98,12 +219,14 @@ func (c *GRPCClient) SendEmail(ctx context.Context, req SendEmailRequest) (*Send
 	}
 
 	// Call gRPC service
-	_, err := c.client.SendEmail(ctx, pbReq)
+	pbResp, err := c.client.SendEmail(ctx, pbReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send email: %w", err)
 	}
 
-	return &SendEmailResponse{}, nil
+	return &SendEmailResponse{
+		MessageID: pbResp.MessageId,
+	}, nil
 }
 
 // SendEmailBatch sends multiple emails in a single request
@@ -116,11 +239,27 @@ func (c *GRPCClient) SendEmailBatch(ctx context.Context, req SendEmailBatchReque
 	// Convert to protobuf requests
 	pbEmails := make([]*pb.SendEmailRequest, len(req.Emails))
 	for i, email := range req.Emails {
+		data, err := convertDataToProto(email.Data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid request: email at index %d: %w", i, err)
+		}
+
 		pbEmails[i] = &pb.SendEmailRequest{
-			TemplateId: email.TemplateID,
-			To:         email.To,
-			Variables:  email.Variables,
-			Priority:   email.Priority,
+			TemplateId:      email.TemplateID,
+			TemplateVersion: email.TemplateVersion,
+			Locale:          email.Locale,
+			To:              email.toRecipients(),
+			Cc:              email.CC,
+			Bcc:             email.BCC,
+			ReplyTo:         email.ReplyTo,
+			Sender:          email.Sender,
+			CancellationKey: email.CancellationKey,
+			IdempotencyKey:  email.IdempotencyKey,
+			Variables:       email.Variables,
+			Data:            data,
+			Attachments:     convertAttachmentsToProto(email.Attachments),
+			Priority:        email.Priority,
+			DryRun:          email.DryRun,
 		}
 		if email.ScheduledAt != nil {
 			pbEmails[i].ScheduledAt = timestamppb.New(*email.ScheduledAt)
@@ -129,6 +268,7 @@ func (c *GRPCClient) SendEmailBatch(ctx context.Context, req SendEmailBatchReque
 
 	pbReq := &pb.SendEmailBatchRequest{
 		Emails: pbEmails,
+		Mode:   convertBatchModeToProto(req.Mode),
 	}
 
 	// Call gRPC service
@@ -138,12 +278,179 @@ func (c *GRPCClient) SendEmailBatch(ctx context.Context, req SendEmailBatchReque
 	}
 
 	// Convert response
-	results := make([]SendEmailResponse, len(pbResp.Results))
+	results := make([]SendEmailBatchResult, len(pbResp.Results))
+	for i, result := range pbResp.Results {
+		results[i] = SendEmailBatchResult{
+			MessageID: result.MessageId,
+			Code:      codes.Code(result.Code),
+			Error:     result.Error,
+		}
+	}
+
 	return &SendEmailBatchResponse{
 		Results: results,
 	}, nil
 }
 
+// RenderEmail renders a template as SendEmail would, without sending anything
+func (c *GRPCClient) RenderEmail(ctx context.Context, req RenderEmailRequest) (*RenderedEmail, error) {
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	data, err := convertDataToProto(req.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	// Call gRPC service
+	pbResp, err := c.client.RenderEmail(ctx, &pb.RenderEmailRequest{
+		TemplateId:      req.TemplateID,
+		TemplateVersion: req.TemplateVersion,
+		Locale:          req.Locale,
+		Variables:       req.Variables,
+		Data:            data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render email: %w", err)
+	}
+
+	// Convert response
+	return &RenderedEmail{
+		Subject:  pbResp.Subject,
+		HTMLBody: pbResp.HtmlBody,
+		TextBody: pbResp.TextBody,
+	}, nil
+}
+
+// GetEmailStatus returns the delivery status of a previously sent email
+func (c *GRPCClient) GetEmailStatus(ctx context.Context, messageID string) (*EmailStatus, error) {
+	if messageID == "" {
+		return nil, fmt.Errorf("invalid request: message_id is required")
+	}
+
+	// Call gRPC service
+	pbResp, err := c.client.GetEmailStatus(ctx, &pb.GetEmailStatusRequest{
+		MessageId: messageID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email status: %w", err)
+	}
+
+	// Convert response
+	emailStatus := &EmailStatus{
+		MessageID:   pbResp.MessageId,
+		State:       convertEmailState(pbResp.State),
+		Attempts:    pbResp.Attempts,
+		MaxAttempts: pbResp.MaxAttempts,
+		LastError:   pbResp.LastError,
+		CreatedAt:   pbResp.CreatedAt.AsTime(),
+		ScheduledAt: pbResp.ScheduledAt.AsTime(),
+		Provider:    pbResp.Provider,
+	}
+
+	if pbResp.AttemptedAt != nil {
+		attemptedAt := pbResp.AttemptedAt.AsTime()
+		emailStatus.AttemptedAt = &attemptedAt
+	}
+	if pbResp.FinalizedAt != nil {
+		finalizedAt := pbResp.FinalizedAt.AsTime()
+		emailStatus.FinalizedAt = &finalizedAt
+	}
+
+	for _, event := range pbResp.Events {
+		emailStatus.Events = append(emailStatus.Events, DeliveryEvent{
+			Type:       event.Type,
+			Recipient:  event.Recipient,
+			Reason:     event.Reason,
+			OccurredAt: event.OccurredAt.AsTime(),
+			Provider:   event.Provider,
+		})
+	}
+
+	return emailStatus, nil
+}
+
+// CancelEmail stops a pending email, or every pending email sharing a cancellation key.
+// Cancelling a single email that is already sending or sent fails with FailedPrecondition.
+func (c *GRPCClient) CancelEmail(ctx context.Context, req CancelEmailRequest) (*CancelEmailResponse, error) {
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	// Call gRPC service
+	pbResp, err := c.client.CancelEmail(ctx, &pb.CancelEmailRequest{
+		MessageId:       req.MessageID,
+		CancellationKey: req.CancellationKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel email: %w", err)
+	}
+
+	return &CancelEmailResponse{
+		Cancelled: int(pbResp.CancelledCount),
+	}, nil
+}
+
+// RescheduleEmail moves a pending email, or every pending email sharing a
+// cancellation key, to a new delivery time
+func (c *GRPCClient) RescheduleEmail(ctx context.Context, req RescheduleEmailRequest) (*RescheduleEmailResponse, error) {
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	// Call gRPC service
+	pbResp, err := c.client.RescheduleEmail(ctx, &pb.RescheduleEmailRequest{
+		MessageId:       req.MessageID,
+		CancellationKey: req.CancellationKey,
+		ScheduledAt:     timestamppb.New(req.ScheduledAt),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reschedule email: %w", err)
+	}
+
+	return &RescheduleEmailResponse{
+		Rescheduled: int(pbResp.RescheduledCount),
+	}, nil
+}
+
+// convertBatchModeToProto maps an SDK batch mode to its protobuf enum
+func convertBatchModeToProto(mode BatchMode) pb.BatchMode {
+	switch mode {
+	case BatchModeAtomic:
+		return pb.BatchMode_BATCH_MODE_ATOMIC
+	case BatchModeBestEffort:
+		return pb.BatchMode_BATCH_MODE_BEST_EFFORT
+	default:
+		return pb.BatchMode_BATCH_MODE_UNSPECIFIED
+	}
+}
+
+// convertEmailState maps the protobuf delivery state onto the SDK type
+func convertEmailState(state pb.EmailState) EmailState {
+	switch state {
+	case pb.EmailState_EMAIL_STATE_SCHEDULED:
+		return EmailStateScheduled
+	case pb.EmailState_EMAIL_STATE_QUEUED:
+		return EmailStateQueued
+	case pb.EmailState_EMAIL_STATE_SENDING:
+		return EmailStateSending
+	case pb.EmailState_EMAIL_STATE_SENT:
+		return EmailStateSent
+	case pb.EmailState_EMAIL_STATE_RETRYING:
+		return EmailStateRetrying
+	case pb.EmailState_EMAIL_STATE_FAILED:
+		return EmailStateFailed
+	case pb.EmailState_EMAIL_STATE_CANCELLED:
+		return EmailStateCancelled
+	default:
+		return EmailStateUnknown
+	}
+}
+
 // ListTemplates returns all available email templates
 func (c *GRPCClient) ListTemplates(ctx context.Context) (*ListTemplatesResponse, error) {
 	// Call gRPC service
@@ -155,15 +462,478 @@ func (c *GRPCClient) ListTemplates(ctx context.Context) (*ListTemplatesResponse,
 	// Convert response
 	templates := make([]EmailTemplate, len(pbResp.Templates))
 	for i, tmpl := range pbResp.Templates {
-		templates[i] = EmailTemplate{
-			ID:        tmpl.Id,
-			Subject:   tmpl.Subject,
-			Variables: tmpl.Variables,
-			Version:   tmpl.Version,
-		}
+		templates[i] = *convertTemplate(tmpl)
 	}
 
 	return &ListTemplatesResponse{
 		Templates: templates,
 	}, nil
 }
+
+// CreateTemplate stores a new email template
+func (c *GRPCClient) CreateTemplate(ctx context.Context, req CreateTemplateRequest) (*EmailTemplate, error) {
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	// Call gRPC service
+	pbResp, err := c.client.CreateTemplate(ctx, &pb.CreateTemplateRequest{
+		Id:                req.ID,
+		Locale:            req.Locale,
+		Subject:           req.Subject,
+		HtmlBody:          req.HTMLBody,
+		TextBody:          req.TextBody,
+		BaseTemplateName:  req.BaseTemplateID,
+		DefaultSender:     req.DefaultSender,
+		SuppressionPolicy: string(req.SuppressionPolicy),
+		Variables:         req.Variables,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+
+	return convertTemplate(pbResp.Template), nil
+}
+
+// GetTemplate returns a single email template's default variant including its bodies
+func (c *GRPCClient) GetTemplate(ctx context.Context, id string) (*EmailTemplate, error) {
+	return c.GetLocalizedTemplate(ctx, id, "")
+}
+
+// GetLocalizedTemplate returns the variant of a template a send in the locale
+// would use, including its bodies; an empty locale returns the default variant
+func (c *GRPCClient) GetLocalizedTemplate(ctx context.Context, id, locale string) (*EmailTemplate, error) {
+	if id == "" {
+		return nil, fmt.Errorf("invalid request: id is required")
+	}
+	if err := validateLocale(locale); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	// Call gRPC service
+	pbResp, err := c.client.GetTemplate(ctx, &pb.GetTemplateRequest{
+		Id:     id,
+		Locale: locale,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	return convertTemplate(pbResp.Template), nil
+}
+
+// UpdateTemplate replaces a template's content. The call fails with codes.Aborted
+// if the template was modified since req.Version was read.
+func (c *GRPCClient) UpdateTemplate(ctx context.Context, req UpdateTemplateRequest) (*EmailTemplate, error) {
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	// Call gRPC service
+	pbResp, err := c.client.UpdateTemplate(ctx, &pb.UpdateTemplateRequest{
+		Id:                req.ID,
+		Locale:            req.Locale,
+		Subject:           req.Subject,
+		HtmlBody:          req.HTMLBody,
+		TextBody:          req.TextBody,
+		BaseTemplateName:  req.BaseTemplateID,
+		DefaultSender:     req.DefaultSender,
+		SuppressionPolicy: string(req.SuppressionPolicy),
+		Variables:         req.Variables,
+		Version:           req.Version,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update template: %w", err)
+	}
+
+	return convertTemplate(pbResp.Template), nil
+}
+
+// DeleteTemplate removes a template's default variant. A non-zero version must
+// match the stored version. Templates used as the base of other templates
+// cannot be deleted.
+func (c *GRPCClient) DeleteTemplate(ctx context.Context, id string, version int32) error {
+	return c.DeleteLocalizedTemplate(ctx, id, "", version)
+}
+
+// DeleteLocalizedTemplate removes one locale's variant of a template; an empty
+// locale is the default variant. A non-zero version must match the stored version.
+func (c *GRPCClient) DeleteLocalizedTemplate(ctx context.Context, id, locale string, version int32) error {
+	if id == "" {
+		return fmt.Errorf("invalid request: id is required")
+	}
+	if err := validateLocale(locale); err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+
+	// Call gRPC service
+	_, err := c.client.DeleteTemplate(ctx, &pb.DeleteTemplateRequest{
+		Id:      id,
+		Locale:  locale,
+		Version: version,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	return nil
+}
+
+// AddSuppression stops emails from being sent to an address. Suppressing an
+// address again replaces its reason, source and expiry.
+func (c *GRPCClient) AddSuppression(ctx context.Context, req AddSuppressionRequest) (*Suppression, error) {
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	pbReq := &pb.AddSuppressionRequest{
+		Address: req.Address,
+		Reason:  string(req.Reason),
+		Source:  req.Source,
+	}
+	if req.ExpiresAt != nil {
+		pbReq.ExpiresAt = timestamppb.New(*req.ExpiresAt)
+	}
+
+	// Call gRPC service
+	pbResp, err := c.client.AddSuppression(ctx, pbReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add suppression: %w", err)
+	}
+
+	return convertSuppression(pbResp.Suppression), nil
+}
+
+// ListSuppressions returns every suppressed address, including expired suppressions
+func (c *GRPCClient) ListSuppressions(ctx context.Context) ([]Suppression, error) {
+	// Call gRPC service
+	pbResp, err := c.client.ListSuppressions(ctx, &pb.ListSuppressionsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list suppressions: %w", err)
+	}
+
+	// Convert response
+	suppressions := make([]Suppression, len(pbResp.Suppressions))
+	for i, suppression := range pbResp.Suppressions {
+		suppressions[i] = *convertSuppression(suppression)
+	}
+
+	return suppressions, nil
+}
+
+// RemoveSuppression allows emails to be sent to an address again
+func (c *GRPCClient) RemoveSuppression(ctx context.Context, address string) error {
+	if address == "" {
+		return fmt.Errorf("invalid request: address is required")
+	}
+
+	// Call gRPC service
+	_, err := c.client.RemoveSuppression(ctx, &pb.RemoveSuppressionRequest{Address: address})
+	if err != nil {
+		return fmt.Errorf("failed to remove suppression: %w", err)
+	}
+
+	return nil
+}
+
+// ListJobs returns a page of enqueued emails matching the filter, newest first
+func (c *GRPCClient) ListJobs(ctx context.Context, req ListJobsRequest) (*ListJobsResponse, error) {
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	// Call gRPC service
+	pbResp, err := c.client.ListJobs(ctx, &pb.ListJobsRequest{
+		Filter:    convertJobFilterToProto(&req.Filter),
+		PageSize:  req.PageSize,
+		PageToken: req.PageToken,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	// Convert response
+	jobs := make([]Job, len(pbResp.Jobs))
+	for i, job := range pbResp.Jobs {
+		jobs[i] = *convertJob(job)
+	}
+
+	return &ListJobsResponse{
+		Jobs:          jobs,
+		NextPageToken: pbResp.NextPageToken,
+	}, nil
+}
+
+// GetJob returns an enqueued email with the error from every failed attempt
+func (c *GRPCClient) GetJob(ctx context.Context, messageID string) (*Job, error) {
+	if messageID == "" {
+		return nil, fmt.Errorf("invalid request: message_id is required")
+	}
+
+	// Call gRPC service
+	pbResp, err := c.client.GetJob(ctx, &pb.GetJobRequest{MessageId: messageID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+
+	return convertJob(pbResp.Job), nil
+}
+
+// RetryJobs makes failed emails available for delivery again and returns how many
+// were retried. Listed emails that have not failed are skipped and reported in a
+// *SkippedJobsError. If retrying fails part way, the count of emails retried
+// before it failed is returned with the error.
+func (c *GRPCClient) RetryJobs(ctx context.Context, req JobsRequest) (int, error) {
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return 0, fmt.Errorf("invalid request: %w", err)
+	}
+
+	// Call gRPC service
+	pbResp, err := c.client.RetryJobs(ctx, &pb.RetryJobsRequest{
+		MessageIds: req.MessageIDs,
+		Filter:     convertJobFilterToProto(req.Filter),
+	})
+	if err != nil {
+		// A failure part way carries the emails changed before it
+		var partial pb.RetryJobsResponse
+		partialJobResponse(err, &partial)
+		return int(partial.RetriedCount), fmt.Errorf("failed to retry jobs: %w", err)
+	}
+
+	return int(pbResp.RetriedCount), skippedJobsError(pbResp.Skipped)
+}
+
+// CancelJobs stops pending emails from being sent and returns how many were
+// cancelled. Listed emails that are no longer pending are skipped and reported
+// in a *SkippedJobsError. If cancelling fails part way, the count of emails
+// cancelled before it failed is returned with the error.
+func (c *GRPCClient) CancelJobs(ctx context.Context, req JobsRequest) (int, error) {
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return 0, fmt.Errorf("invalid request: %w", err)
+	}
+
+	// Call gRPC service
+	pbResp, err := c.client.CancelJobs(ctx, &pb.CancelJobsRequest{
+		MessageIds: req.MessageIDs,
+		Filter:     convertJobFilterToProto(req.Filter),
+	})
+	if err != nil {
+		// A failure part way carries the emails changed before it
+		var partial pb.CancelJobsResponse
+		partialJobResponse(err, &partial)
+		return int(partial.CancelledCount), fmt.Errorf("failed to cancel jobs: %w", err)
+	}
+
+	return int(pbResp.CancelledCount), skippedJobsError(pbResp.Skipped)
+}
+
+// DiscardJobs deletes failed and cancelled emails and returns how many were
+// deleted. Listed emails in other states are skipped and reported in a
+// *SkippedJobsError. If deleting fails part way, the count of emails deleted
+// before it failed is returned with the error.
+func (c *GRPCClient) DiscardJobs(ctx context.Context, req JobsRequest) (int, error) {
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return 0, fmt.Errorf("invalid request: %w", err)
+	}
+
+	// Call gRPC service
+	pbResp, err := c.client.DiscardJobs(ctx, &pb.DiscardJobsRequest{
+		MessageIds: req.MessageIDs,
+		Filter:     convertJobFilterToProto(req.Filter),
+	})
+	if err != nil {
+		// A failure part way carries the emails changed before it
+		var partial pb.DiscardJobsResponse
+		partialJobResponse(err, &partial)
+		return int(partial.DiscardedCount), fmt.Errorf("failed to discard jobs: %w", err)
+	}
+
+	return int(pbResp.DiscardedCount), skippedJobsError(pbResp.Skipped)
+}
+
+// partialJobResponse reads the response a bulk change's error status carries,
+// leaving resp empty if there isn't one
+func partialJobResponse(err error, resp proto.Message) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return
+	}
+
+	for _, detail := range st.Proto().GetDetails() {
+		if detail.UnmarshalTo(resp) == nil {
+			return
+		}
+	}
+}
+
+// skippedJobsError reports the emails a bulk change skipped, or nil if it
+// skipped none
+func skippedJobsError(skipped []*pb.SkippedJob) error {
+	if len(skipped) == 0 {
+		return nil
+	}
+
+	err := &SkippedJobsError{Skipped: make([]SkippedJob, len(skipped))}
+	for i, job := range skipped {
+		err.Skipped[i] = SkippedJob{MessageID: job.MessageId, Reason: job.Reason}
+	}
+	return err
+}
+
+// convertJobFilterToProto converts an SDK job filter to its protobuf representation
+func convertJobFilterToProto(filter *JobFilter) *pb.JobFilter {
+	if filter == nil {
+		return nil
+	}
+
+	pbFilter := &pb.JobFilter{
+		TemplateId: filter.TemplateID,
+		Recipient:  filter.Recipient,
+	}
+	for _, state := range filter.States {
+		pbFilter.States = append(pbFilter.States, convertEmailStateToProto(state))
+	}
+	if filter.CreatedAfter != nil {
+		pbFilter.CreatedAfter = timestamppb.New(*filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		pbFilter.CreatedBefore = timestamppb.New(*filter.CreatedBefore)
+	}
+
+	return pbFilter
+}
+
+// convertEmailStateToProto maps an SDK delivery state to its protobuf enum
+func convertEmailStateToProto(state EmailState) pb.EmailState {
+	switch state {
+	case EmailStateScheduled:
+		return pb.EmailState_EMAIL_STATE_SCHEDULED
+	case EmailStateQueued:
+		return pb.EmailState_EMAIL_STATE_QUEUED
+	case EmailStateSending:
+		return pb.EmailState_EMAIL_STATE_SENDING
+	case EmailStateSent:
+		return pb.EmailState_EMAIL_STATE_SENT
+	case EmailStateRetrying:
+		return pb.EmailState_EMAIL_STATE_RETRYING
+	case EmailStateFailed:
+		return pb.EmailState_EMAIL_STATE_FAILED
+	case EmailStateCancelled:
+		return pb.EmailState_EMAIL_STATE_CANCELLED
+	default:
+		return pb.EmailState_EMAIL_STATE_UNSPECIFIED
+	}
+}
+
+// convertJob converts a protobuf job to the SDK type
+func convertJob(job *pb.Job) *Job {
+	result := &Job{
+		MessageID:   job.MessageId,
+		State:       convertEmailState(job.State),
+		TemplateID:  job.TemplateId,
+		To:          job.To,
+		CC:          job.Cc,
+		BCC:         job.Bcc,
+		From:        job.From,
+		Subject:     job.Subject,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		CreatedAt:   job.CreatedAt.AsTime(),
+		ScheduledAt: job.ScheduledAt.AsTime(),
+		Provider:    job.Provider,
+	}
+
+	if job.AttemptedAt != nil {
+		attemptedAt := job.AttemptedAt.AsTime()
+		result.AttemptedAt = &attemptedAt
+	}
+	if job.FinalizedAt != nil {
+		finalizedAt := job.FinalizedAt.AsTime()
+		result.FinalizedAt = &finalizedAt
+	}
+
+	for _, jobErr := range job.Errors {
+		result.Errors = append(result.Errors, JobError{
+			Attempt:    jobErr.Attempt,
+			Error:      jobErr.Error,
+			OccurredAt: jobErr.OccurredAt.AsTime(),
+		})
+	}
+
+	return result
+}
+
+// convertSuppression converts a protobuf suppression to the SDK type
+func convertSuppression(suppression *pb.Suppression) *Suppression {
+	result := &Suppression{
+		Address:   suppression.Address,
+		Reason:    SuppressionReason(suppression.Reason),
+		Source:    suppression.Source,
+		CreatedAt: suppression.CreatedAt.AsTime(),
+	}
+
+	if suppression.ExpiresAt != nil {
+		expiresAt := suppression.ExpiresAt.AsTime()
+		result.ExpiresAt = &expiresAt
+	}
+
+	return result
+}
+
+// convertTemplate converts a protobuf template to the SDK type
+func convertTemplate(tmpl *pb.EmailTemplate) *EmailTemplate {
+	return &EmailTemplate{
+		ID:                tmpl.Id,
+		Locale:            tmpl.Locale,
+		Subject:           tmpl.Subject,
+		Variables:         tmpl.Variables,
+		Version:           tmpl.Version,
+		HTMLBody:          tmpl.HtmlBody,
+		TextBody:          tmpl.TextBody,
+		BaseTemplateID:    tmpl.BaseTemplateName,
+		DefaultSender:     tmpl.DefaultSender,
+		SuppressionPolicy: SuppressionPolicy(tmpl.SuppressionPolicy),
+		CreatedAt:         tmpl.CreatedAt.AsTime(),
+		UpdatedAt:         tmpl.UpdatedAt.AsTime(),
+	}
+}
+
+// convertAttachmentsToProto converts SDK attachments to their protobuf representation
+func convertAttachmentsToProto(attachments []Attachment) []*pb.Attachment {
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	pbAttachments := make([]*pb.Attachment, len(attachments))
+	for i, a := range attachments {
+		pbAttachments[i] = &pb.Attachment{
+			Filename:    a.Filename,
+			ContentType: a.ContentType,
+			Content:     a.Content,
+			Disposition: a.Disposition,
+			ContentId:   a.ContentID,
+		}
+	}
+	return pbAttachments
+}
+
+// convertDataToProto converts structured variables to a protobuf Struct. Values
+// must be JSON-like: strings, numbers, booleans, nil, []any and map[string]any.
+func convertDataToProto(data map[string]any) (*structpb.Struct, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	pbData, err := structpb.NewStruct(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data: %w", err)
+	}
+	return pbData, nil
+}
@@ -2,17 +2,34 @@ package sdk
 
 import (
 	"fmt"
+	"mime"
 	"net/mail"
+	"strings"
 	"time"
+
+	"golang.org/x/text/language"
+	"google.golang.org/grpc/codes"
 )
 
 // SendEmailRequest represents a request to send an email
 type SendEmailRequest struct {
-	TemplateID  string            `json:"template_id"`
-	To          string            `json:"to"`
-	Variables   map[string]string `json:"variables,omitempty"`
-	Priority    int32             `json:"priority,omitempty"`
-	ScheduledAt *time.Time        `json:"scheduled_at,omitempty"`
+	TemplateID      string            `json:"template_id"`
+	TemplateVersion int32             `json:"template_version,omitempty"` // Zero sends the latest version
+	Locale          string            `json:"locale,omitempty"`           // e.g. fr-CA, falling back to fr and then the default template
+	To              string            `json:"to,omitempty"`               // May include a display name, e.g. "Jane <jane@example.com>"
+	Recipients      []string          `json:"recipients,omitempty"`       // Further To recipients, sent the same message as To
+	CC              []string          `json:"cc,omitempty"`
+	BCC             []string          `json:"bcc,omitempty"`
+	ReplyTo         string            `json:"reply_to,omitempty"`
+	Sender          string            `json:"sender,omitempty"`           // Registered sender address; empty uses the template or server default
+	CancellationKey string            `json:"cancellation_key,omitempty"` // Groups emails for CancelEmail and RescheduleEmail
+	IdempotencyKey  string            `json:"idempotency_key,omitempty"`  // Retrying with the same key returns the original message ID
+	Variables       map[string]string `json:"variables,omitempty"`
+	Data            map[string]any    `json:"data,omitempty"` // Structured variables such as lists and nested objects
+	Attachments     []Attachment      `json:"attachments,omitempty"`
+	Priority        int32             `json:"priority,omitempty"`
+	ScheduledAt     *time.Time        `json:"scheduled_at,omitempty"`
+	DryRun          bool              `json:"dry_run,omitempty"` // Validate and render without sending; no message ID is returned
 }
 
 // Validate validates the send email request
@@ -20,29 +37,269 @@ func (r *SendEmailRequest) Validate() error {
 	if r.TemplateID == "" {
 		return fmt.Errorf("template_id is required")
 	}
-	if r.To == "" {
+	if r.TemplateVersion < 0 {
+		return fmt.Errorf("template_version cannot be negative")
+	}
+	if err := validateLocale(r.Locale); err != nil {
+		return err
+	}
+	to := r.toRecipients()
+	if len(to) == 0 {
 		return fmt.Errorf("to is required")
 	}
-	if _, err := mail.ParseAddress(r.To); err != nil {
-		return fmt.Errorf("invalid email address: %s", r.To)
+	if err := validateRecipients(to, r.CC, r.BCC); err != nil {
+		return err
+	}
+	if r.ReplyTo != "" {
+		if _, err := mail.ParseAddress(r.ReplyTo); err != nil {
+			return fmt.Errorf("invalid reply_to address: %s", r.ReplyTo)
+		}
+	}
+	if r.Sender != "" {
+		if _, err := mail.ParseAddress(r.Sender); err != nil {
+			return fmt.Errorf("invalid sender address: %s", r.Sender)
+		}
+	}
+	if len(r.CancellationKey) > MaxCancellationKeyLength {
+		return fmt.Errorf("cancellation_key exceeds %d characters", MaxCancellationKeyLength)
+	}
+	if len(r.IdempotencyKey) > MaxIdempotencyKeyLength {
+		return fmt.Errorf("idempotency_key exceeds %d characters", MaxIdempotencyKeyLength)
+	}
+	if err := validateData(r.Variables, r.Data); err != nil {
+		return err
+	}
+
+	var total int
+	for i, a := range r.Attachments {
+		if err := a.Validate(); err != nil {
+			return fmt.Errorf("invalid attachment at index %d: %w", i, err)
+		}
+		total += len(a.Content)
+	}
+	if total > MaxTotalAttachmentSize {
+		return fmt.Errorf("attachments exceed the %d byte total limit", MaxTotalAttachmentSize)
+	}
+	return nil
+}
+
+// toRecipients combines To and Recipients into the To list that is sent
+func (r *SendEmailRequest) toRecipients() []string {
+	if r.To == "" {
+		return r.Recipients
+	}
+	return append([]string{r.To}, r.Recipients...)
+}
+
+// MaxRecipients caps the combined to, cc and bcc lists; SendGrid rejects
+// messages addressed to more than 1000 recipients
+const MaxRecipients = 1000
+
+// validateRecipients checks that every address parses and none is repeated,
+// since providers reject an address appearing in more than one list
+func validateRecipients(lists ...[]string) error {
+	seen := make(map[string]bool)
+	for _, list := range lists {
+		for _, s := range list {
+			addr, err := mail.ParseAddress(s)
+			if err != nil {
+				return fmt.Errorf("invalid email address: %s", s)
+			}
+
+			key := strings.ToLower(addr.Address)
+			if seen[key] {
+				return fmt.Errorf("duplicate recipient: %s", addr.Address)
+			}
+			seen[key] = true
+		}
+	}
+
+	if len(seen) > MaxRecipients {
+		return fmt.Errorf("too many recipients: %d exceeds the limit of %d", len(seen), MaxRecipients)
+	}
+	return nil
+}
+
+// Attachment size limits. Providers reject messages above roughly 25-30 MB
+// once base64 encoding overhead is included.
+const (
+	MaxAttachmentSize      = 10 << 20
+	MaxTotalAttachmentSize = 20 << 20
+
+	// MaxBatchAttachmentSize caps attachments across every email in a batch,
+	// since the batch travels as one gRPC message and is enqueued in one
+	// transaction
+	MaxBatchAttachmentSize = 24 << 20
+
+	// MaxMessageSize is the largest gRPC message the client and server accept,
+	// leaving headroom above MaxTotalAttachmentSize for the rest of the request
+	MaxMessageSize = 32 << 20
+)
+
+// Attachment dispositions
+const (
+	DispositionAttachment = "attachment"
+	DispositionInline     = "inline"
+)
+
+// Attachment is a file sent with an email
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type,omitempty"` // Defaults to application/octet-stream
+	Content     []byte `json:"content"`
+	Disposition string `json:"disposition,omitempty"` // Defaults to attachment
+	ContentID   string `json:"content_id,omitempty"`  // Referenced from HTML as cid:<content_id>
+}
+
+// Validate validates the attachment
+func (a *Attachment) Validate() error {
+	if a.Filename == "" {
+		return fmt.Errorf("filename is required")
+	}
+	if len(a.Content) == 0 {
+		return fmt.Errorf("content is required")
+	}
+	if len(a.Content) > MaxAttachmentSize {
+		return fmt.Errorf("%s exceeds the %d byte attachment limit", a.Filename, MaxAttachmentSize)
+	}
+	if a.ContentType != "" {
+		if err := validateContentType(a.ContentType); err != nil {
+			return fmt.Errorf("invalid content_type for %s: %w", a.Filename, err)
+		}
+	}
+	if strings.ContainsAny(a.ContentID, "\r\n<>") {
+		return fmt.Errorf("invalid content_id for %s", a.Filename)
+	}
+	switch a.Disposition {
+	case "", DispositionAttachment:
+	case DispositionInline:
+		if a.ContentID == "" {
+			return fmt.Errorf("content_id is required for inline attachment %s", a.Filename)
+		}
+	default:
+		return fmt.Errorf("invalid disposition %q", a.Disposition)
+	}
+	return nil
+}
+
+// validateContentType checks for a type/subtype media type. Content types are
+// copied into MIME headers, so line breaks are rejected outright rather than
+// relying on the parser, which accepts folded parameters.
+func validateContentType(contentType string) error {
+	if strings.ContainsAny(contentType, "\r\n") {
+		return fmt.Errorf("%q contains a line break", contentType)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("%q: %w", contentType, err)
+	}
+	if !strings.Contains(mediaType, "/") {
+		return fmt.Errorf("%q is not a type/subtype media type", contentType)
 	}
 	return nil
 }
 
 // SendEmailResponse is returned after successfully enqueuing an email
 type SendEmailResponse struct {
+	MessageID string `json:"message_id"`
+}
+
+// RenderEmailRequest identifies the template to preview and its variables
+type RenderEmailRequest struct {
+	TemplateID      string            `json:"template_id"`
+	TemplateVersion int32             `json:"template_version,omitempty"` // Zero renders the latest version
+	Locale          string            `json:"locale,omitempty"`           // e.g. fr-CA, falling back to fr and then the default template
+	Variables       map[string]string `json:"variables,omitempty"`
+	Data            map[string]any    `json:"data,omitempty"` // Structured variables such as lists and nested objects
+}
+
+// Validate validates the render email request
+func (r *RenderEmailRequest) Validate() error {
+	if r.TemplateID == "" {
+		return fmt.Errorf("template_id is required")
+	}
+	if r.TemplateVersion < 0 {
+		return fmt.Errorf("template_version cannot be negative")
+	}
+	if err := validateLocale(r.Locale); err != nil {
+		return err
+	}
+	return validateData(r.Variables, r.Data)
+}
+
+// validateLocale checks that a locale, if set, is a BCP 47 language tag such as fr-CA
+func validateLocale(locale string) error {
+	if locale == "" {
+		return nil
+	}
+	if _, err := language.Parse(strings.ReplaceAll(locale, "_", "-")); err != nil {
+		return fmt.Errorf("invalid locale %q", locale)
+	}
+	return nil
+}
+
+// validateData rejects structured variables that would shadow string variables
+func validateData(variables map[string]string, data map[string]any) error {
+	for key := range data {
+		if _, ok := variables[key]; ok {
+			return fmt.Errorf("variable %q is set in both variables and data", key)
+		}
+	}
+	return nil
+}
+
+// RenderedEmail is the content an email would be sent with
+type RenderedEmail struct {
+	Subject  string `json:"subject"`
+	HTMLBody string `json:"html_body"`
+	TextBody string `json:"text_body,omitempty"`
 }
 
+// BatchMode controls how SendEmailBatch handles emails that cannot be sent
+type BatchMode string
+
+const (
+	// BatchModeAtomic renders every email first and enqueues them together. If any
+	// email fails the whole batch is rejected and nothing is sent. This is the default.
+	BatchModeAtomic BatchMode = "atomic"
+
+	// BatchModeBestEffort enqueues each email independently and reports the
+	// outcome of every email in the response
+	BatchModeBestEffort BatchMode = "best_effort"
+)
+
 // SendEmailBatchRequest represents a batch email request
 type SendEmailBatchRequest struct {
 	Emails []SendEmailRequest `json:"emails"`
+	Mode   BatchMode          `json:"mode,omitempty"` // Empty uses BatchModeAtomic
 }
 
-// Validate validates the batch email request
+// Validate validates the batch email request. In best-effort mode invalid
+// emails are reported in the per-email results instead of failing the batch,
+// but the batch attachment limit always applies.
 func (r *SendEmailBatchRequest) Validate() error {
 	if len(r.Emails) == 0 {
 		return fmt.Errorf("emails list cannot be empty")
 	}
+
+	var total int
+	for _, email := range r.Emails {
+		for _, a := range email.Attachments {
+			total += len(a.Content)
+		}
+	}
+	if total > MaxBatchAttachmentSize {
+		return fmt.Errorf("attachments exceed the %d byte batch limit", MaxBatchAttachmentSize)
+	}
+
+	switch r.Mode {
+	case "", BatchModeAtomic:
+	case BatchModeBestEffort:
+		return nil
+	default:
+		return fmt.Errorf("invalid batch mode %q", r.Mode)
+	}
+
 	for i, email := range r.Emails {
 		if err := email.Validate(); err != nil {
 			return fmt.Errorf("invalid email at index %d: %w", i, err)
@@ -51,20 +308,399 @@ func (r *SendEmailBatchRequest) Validate() error {
 	return nil
 }
 
-// SendEmailBatchResponse represents the batch email response
+// SendEmailBatchResponse represents the batch email response. Results are in
+// the same order as the request's emails.
 type SendEmailBatchResponse struct {
-	Results []SendEmailResponse `json:"results"`
+	Results []SendEmailBatchResult `json:"results"`
+}
+
+// SendEmailBatchResult is the outcome of one email in a batch
+type SendEmailBatchResult struct {
+	MessageID string     `json:"message_id,omitempty"` // Empty if the email was not enqueued
+	Code      codes.Code `json:"code"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// OK reports whether the email was enqueued
+func (r SendEmailBatchResult) OK() bool {
+	return r.Code == codes.OK
+}
+
+// MaxCancellationKeyLength bounds the caller-provided key used to group emails
+const MaxCancellationKeyLength = 255
+
+// MaxIdempotencyKeyLength bounds the caller-provided key used to deduplicate sends
+const MaxIdempotencyKeyLength = 255
+
+// CancelEmailRequest identifies the emails to cancel by exactly one of
+// MessageID or CancellationKey
+type CancelEmailRequest struct {
+	MessageID       string `json:"message_id,omitempty"`
+	CancellationKey string `json:"cancellation_key,omitempty"`
+}
+
+// Validate validates the cancel email request
+func (r *CancelEmailRequest) Validate() error {
+	return validateEmailTarget(r.MessageID, r.CancellationKey)
+}
+
+// CancelEmailResponse reports how many emails were cancelled
+type CancelEmailResponse struct {
+	Cancelled int `json:"cancelled"`
+}
+
+// RescheduleEmailRequest identifies the emails to move by exactly one of
+// MessageID or CancellationKey
+type RescheduleEmailRequest struct {
+	MessageID       string    `json:"message_id,omitempty"`
+	CancellationKey string    `json:"cancellation_key,omitempty"`
+	ScheduledAt     time.Time `json:"scheduled_at"` // A time in the past sends immediately
+}
+
+// Validate validates the reschedule email request
+func (r *RescheduleEmailRequest) Validate() error {
+	if err := validateEmailTarget(r.MessageID, r.CancellationKey); err != nil {
+		return err
+	}
+	if r.ScheduledAt.IsZero() {
+		return fmt.Errorf("scheduled_at is required")
+	}
+	return nil
+}
+
+// RescheduleEmailResponse reports how many emails were rescheduled
+type RescheduleEmailResponse struct {
+	Rescheduled int `json:"rescheduled"`
+}
+
+// validateEmailTarget checks that exactly one way of selecting emails was given
+func validateEmailTarget(messageID, cancellationKey string) error {
+	switch {
+	case messageID == "" && cancellationKey == "":
+		return fmt.Errorf("message_id or cancellation_key is required")
+	case messageID != "" && cancellationKey != "":
+		return fmt.Errorf("only one of message_id or cancellation_key may be set")
+	case len(cancellationKey) > MaxCancellationKeyLength:
+		return fmt.Errorf("cancellation_key exceeds %d characters", MaxCancellationKeyLength)
+	}
+	return nil
+}
+
+// EmailState describes where an email is in the delivery lifecycle
+type EmailState string
+
+const (
+	EmailStateUnknown   EmailState = "unknown"
+	EmailStateScheduled EmailState = "scheduled"
+	EmailStateQueued    EmailState = "queued"
+	EmailStateSending   EmailState = "sending"
+	EmailStateSent      EmailState = "sent"
+	EmailStateRetrying  EmailState = "retrying"
+	EmailStateFailed    EmailState = "failed"
+	EmailStateCancelled EmailState = "cancelled"
+)
+
+// EmailStatus describes the delivery progress of an enqueued email
+type EmailStatus struct {
+	MessageID   string          `json:"message_id"`
+	State       EmailState      `json:"state"`
+	Attempts    int32           `json:"attempts"`
+	MaxAttempts int32           `json:"max_attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	ScheduledAt time.Time       `json:"scheduled_at"`
+	AttemptedAt *time.Time      `json:"attempted_at,omitempty"`
+	FinalizedAt *time.Time      `json:"finalized_at,omitempty"`
+	Provider    string          `json:"provider,omitempty"` // Which provider delivered the email, once sent
+	Events      []DeliveryEvent `json:"events,omitempty"`   // Reported by the provider, oldest first
+}
+
+// DeliveryEvent is something the provider reported happening to an email, such
+// as "delivered", "bounced" or "opened"
+type DeliveryEvent struct {
+	Type       string    `json:"type"`
+	Recipient  string    `json:"recipient"`
+	Reason     string    `json:"reason,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Provider   string    `json:"provider"`
+}
+
+// SuppressionPolicy decides what happens when a template is sent to a suppressed recipient
+type SuppressionPolicy string
+
+const (
+	// SuppressionPolicyReject fails the send with codes.FailedPrecondition. This is the default.
+	SuppressionPolicyReject SuppressionPolicy = "reject"
+
+	// SuppressionPolicyDrop removes suppressed recipients and skips the email,
+	// returning an empty message ID, if no To recipients remain
+	SuppressionPolicyDrop SuppressionPolicy = "drop"
+)
+
+func (p SuppressionPolicy) validate() error {
+	switch p {
+	case "", SuppressionPolicyReject, SuppressionPolicyDrop:
+		return nil
+	default:
+		return fmt.Errorf("invalid suppression_policy %q", p)
+	}
 }
 
 // EmailTemplate represents an email template
 type EmailTemplate struct {
-	ID        string   `json:"id"`
-	Subject   string   `json:"subject"`
-	Variables []string `json:"variables"`
-	Version   int32    `json:"version"`
+	ID                string            `json:"id"`
+	Locale            string            `json:"locale,omitempty"` // Empty for the default variant
+	Subject           string            `json:"subject"`
+	Variables         []string          `json:"variables"`
+	Version           int32             `json:"version"`
+	HTMLBody          string            `json:"html_body"`
+	TextBody          string            `json:"text_body,omitempty"`
+	BaseTemplateID    string            `json:"base_template_id,omitempty"`
+	DefaultSender     string            `json:"default_sender,omitempty"`
+	SuppressionPolicy SuppressionPolicy `json:"suppression_policy,omitempty"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+}
+
+// CreateTemplateRequest represents a request to create an email template
+type CreateTemplateRequest struct {
+	ID                string            `json:"id"`
+	Locale            string            `json:"locale,omitempty"` // Creates a localized variant; empty creates the default
+	Subject           string            `json:"subject"`
+	HTMLBody          string            `json:"html_body"`
+	TextBody          string            `json:"text_body,omitempty"`
+	BaseTemplateID    string            `json:"base_template_id,omitempty"`
+	Variables         []string          `json:"variables,omitempty"`
+	DefaultSender     string            `json:"default_sender,omitempty"`     // Must be a registered sender address
+	SuppressionPolicy SuppressionPolicy `json:"suppression_policy,omitempty"` // Empty uses SuppressionPolicyReject
+}
+
+// Validate validates the create template request
+func (r *CreateTemplateRequest) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if r.HTMLBody == "" {
+		return fmt.Errorf("html_body is required")
+	}
+	if err := validateLocale(r.Locale); err != nil {
+		return err
+	}
+	return r.SuppressionPolicy.validate()
+}
+
+// UpdateTemplateRequest replaces the content of an existing template. Version
+// must be the template's current version, as returned by GetTemplate.
+type UpdateTemplateRequest struct {
+	ID                string            `json:"id"`
+	Locale            string            `json:"locale,omitempty"` // Variant to update; empty is the default
+	Subject           string            `json:"subject"`
+	HTMLBody          string            `json:"html_body"`
+	TextBody          string            `json:"text_body,omitempty"`
+	BaseTemplateID    string            `json:"base_template_id,omitempty"`
+	Variables         []string          `json:"variables,omitempty"`
+	DefaultSender     string            `json:"default_sender,omitempty"`     // Must be a registered sender address
+	SuppressionPolicy SuppressionPolicy `json:"suppression_policy,omitempty"` // Empty uses SuppressionPolicyReject
+	Version           int32             `json:"version"`
+}
+
+// Validate validates the update template request
+func (r *UpdateTemplateRequest) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if r.HTMLBody == "" {
+		return fmt.Errorf("html_body is required")
+	}
+	if r.Version <= 0 {
+		return fmt.Errorf("version is required")
+	}
+	if err := validateLocale(r.Locale); err != nil {
+		return err
+	}
+	return r.SuppressionPolicy.validate()
 }
 
 // ListTemplatesResponse represents the response from listing templates
 type ListTemplatesResponse struct {
 	Templates []EmailTemplate `json:"templates"`
 }
+
+// SuppressionReason records why an address is suppressed
+type SuppressionReason string
+
+const (
+	SuppressionReasonBounce      SuppressionReason = "bounce"
+	SuppressionReasonComplaint   SuppressionReason = "complaint"
+	SuppressionReasonUnsubscribe SuppressionReason = "unsubscribe"
+	SuppressionReasonManual      SuppressionReason = "manual"
+)
+
+// Suppression stops emails from being sent to an address
+type Suppression struct {
+	Address   string            `json:"address"`
+	Reason    SuppressionReason `json:"reason"`
+	Source    string            `json:"source,omitempty"`
+	ExpiresAt *time.Time        `json:"expires_at,omitempty"` // Nil for permanent suppressions
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// AddSuppressionRequest suppresses an address, replacing any existing suppression for it
+type AddSuppressionRequest struct {
+	Address   string            `json:"address"`
+	Reason    SuppressionReason `json:"reason"`
+	Source    string            `json:"source,omitempty"`     // Where the suppression came from, e.g. "crm"
+	ExpiresAt *time.Time        `json:"expires_at,omitempty"` // Nil suppresses the address permanently
+}
+
+// Validate validates the add suppression request
+func (r *AddSuppressionRequest) Validate() error {
+	if r.Address == "" {
+		return fmt.Errorf("address is required")
+	}
+	if _, err := mail.ParseAddress(r.Address); err != nil {
+		return fmt.Errorf("invalid email address: %s", r.Address)
+	}
+
+	switch r.Reason {
+	case SuppressionReasonBounce, SuppressionReasonComplaint, SuppressionReasonUnsubscribe, SuppressionReasonManual:
+	case "":
+		return fmt.Errorf("reason is required")
+	default:
+		return fmt.Errorf("invalid reason %q", r.Reason)
+	}
+
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("expires_at must be in the future")
+	}
+	return nil
+}
+
+// MaxJobPageSize caps the jobs returned by one ListJobs call
+const MaxJobPageSize = 1000
+
+// JobFilter selects enqueued emails. Zero fields match every email.
+type JobFilter struct {
+	States        []EmailState `json:"states,omitempty"`
+	TemplateID    string       `json:"template_id,omitempty"`
+	Recipient     string       `json:"recipient,omitempty"` // Matches to, cc or bcc
+	CreatedAfter  *time.Time   `json:"created_after,omitempty"`
+	CreatedBefore *time.Time   `json:"created_before,omitempty"`
+}
+
+// Validate validates the job filter
+func (f *JobFilter) Validate() error {
+	for _, state := range f.States {
+		switch state {
+		case EmailStateScheduled, EmailStateQueued, EmailStateSending, EmailStateSent,
+			EmailStateRetrying, EmailStateFailed, EmailStateCancelled:
+		default:
+			return fmt.Errorf("invalid state %q", state)
+		}
+	}
+	if f.Recipient != "" {
+		if _, err := mail.ParseAddress(f.Recipient); err != nil {
+			return fmt.Errorf("invalid recipient: %s", f.Recipient)
+		}
+	}
+	if f.CreatedAfter != nil && f.CreatedBefore != nil && !f.CreatedAfter.Before(*f.CreatedBefore) {
+		return fmt.Errorf("created_after must be before created_before")
+	}
+	return nil
+}
+
+// Job is an enqueued email as seen by an operator
+type Job struct {
+	MessageID   string     `json:"message_id"`
+	State       EmailState `json:"state"`
+	TemplateID  string     `json:"template_id,omitempty"` // Empty for emails enqueued before templates were recorded
+	To          []string   `json:"to"`
+	CC          []string   `json:"cc,omitempty"`
+	BCC         []string   `json:"bcc,omitempty"`
+	From        string     `json:"from"`
+	Subject     string     `json:"subject"`
+	Attempts    int32      `json:"attempts"`
+	MaxAttempts int32      `json:"max_attempts"`
+	Errors      []JobError `json:"errors,omitempty"` // One per failed attempt, oldest first
+	CreatedAt   time.Time  `json:"created_at"`
+	ScheduledAt time.Time  `json:"scheduled_at"`
+	AttemptedAt *time.Time `json:"attempted_at,omitempty"`
+	FinalizedAt *time.Time `json:"finalized_at,omitempty"`
+	Provider    string     `json:"provider,omitempty"` // Which provider delivered the email, once sent
+}
+
+// JobError is the error a delivery attempt failed with
+type JobError struct {
+	Attempt    int32     `json:"attempt"`
+	Error      string    `json:"error"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// ListJobsRequest retrieves a page of enqueued emails, newest first
+type ListJobsRequest struct {
+	Filter    JobFilter `json:"filter"`
+	PageSize  int32     `json:"page_size,omitempty"`  // Zero returns 50
+	PageToken string    `json:"page_token,omitempty"` // NextPageToken from the previous page
+}
+
+// Validate validates the list jobs request
+func (r *ListJobsRequest) Validate() error {
+	if r.PageSize < 0 || r.PageSize > MaxJobPageSize {
+		return fmt.Errorf("page_size must be between 0 and %d", MaxJobPageSize)
+	}
+	return r.Filter.Validate()
+}
+
+// ListJobsResponse contains a page of enqueued emails
+type ListJobsResponse struct {
+	Jobs          []Job  `json:"jobs"`
+	NextPageToken string `json:"next_page_token,omitempty"` // Empty on the last page
+}
+
+// JobsRequest selects the emails for RetryJobs, CancelJobs or DiscardJobs by
+// exactly one of MessageIDs or Filter. Emails a filter matches that are in the
+// wrong state for the change are skipped.
+type JobsRequest struct {
+	MessageIDs []string   `json:"message_ids,omitempty"`
+	Filter     *JobFilter `json:"filter,omitempty"` // An empty filter matches every email
+}
+
+// SkippedJobsError is returned by RetryJobs, CancelJobs and DiscardJobs, along
+// with how many emails were changed, when some of the MessageIDs were left
+// unchanged because they were in the wrong state for the change or don't exist
+type SkippedJobsError struct {
+	Skipped []SkippedJob
+}
+
+// SkippedJob is an email selected by message ID that was left unchanged
+type SkippedJob struct {
+	MessageID string `json:"message_id"`
+	Reason    string `json:"reason"`
+}
+
+func (e *SkippedJobsError) Error() string {
+	reasons := make([]string, len(e.Skipped))
+	for i, job := range e.Skipped {
+		reasons[i] = job.Reason
+	}
+	return fmt.Sprintf("%d emails skipped: %s", len(e.Skipped), strings.Join(reasons, "; "))
+}
+
+// Validate validates the jobs request
+func (r *JobsRequest) Validate() error {
+	switch {
+	case len(r.MessageIDs) == 0 && r.Filter == nil:
+		return fmt.Errorf("message_ids or filter is required")
+	case len(r.MessageIDs) > 0 && r.Filter != nil:
+		return fmt.Errorf("only one of message_ids or filter may be set")
+	case r.Filter != nil:
+		return r.Filter.Validate()
+	}
+
+	for _, messageID := range r.MessageIDs {
+		if messageID == "" {
+			return fmt.Errorf("message_ids cannot contain an empty ID")
+		}
+	}
+	return nil
+}
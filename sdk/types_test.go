@@ -2,6 +2,7 @@ package sdk
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -30,6 +31,43 @@ func TestSendEmailRequest_Validate(t *testing.T) {
 		assert.Contains(t, err.Error(), "template_id")
 	})
 
+	t.Run("invalid locale", func(t *testing.T) {
+		t.Parallel()
+		r := &SendEmailRequest{
+			TemplateID: "welcome",
+			To:         "user@example.com",
+			Locale:     "not a locale",
+		}
+		err := r.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid locale")
+	})
+
+	t.Run("variable in both variables and data", func(t *testing.T) {
+		t.Parallel()
+		r := &SendEmailRequest{
+			TemplateID: "welcome",
+			To:         "user@example.com",
+			Variables:  map[string]string{"Name": "Alice"},
+			Data:       map[string]any{"Name": map[string]any{"First": "Alice"}},
+		}
+		err := r.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"Name" is set in both`)
+	})
+
+	t.Run("negative template version", func(t *testing.T) {
+		t.Parallel()
+		r := &SendEmailRequest{
+			TemplateID:      "welcome",
+			TemplateVersion: -1,
+			To:              "user@example.com",
+		}
+		err := r.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "template_version")
+	})
+
 	t.Run("missing recipient", func(t *testing.T) {
 		t.Parallel()
 		r := &SendEmailRequest{
@@ -71,6 +109,165 @@ func TestSendEmailRequest_Validate(t *testing.T) {
 	})
 }
 
+func TestSendEmailRequest_ValidateRecipients(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		req     SendEmailRequest
+		wantErr string
+	}{
+		{
+			name: "display names and all lists",
+			req: SendEmailRequest{
+				To:         "Jane Doe <jane@example.com>",
+				Recipients: []string{"bob@example.com"},
+				CC:         []string{"Support <support@example.com>"},
+				BCC:        []string{"audit@example.com"},
+				ReplyTo:    "Ticket 42 <tickets@example.com>",
+			},
+		},
+		{
+			name: "recipients without to",
+			req:  SendEmailRequest{Recipients: []string{"jane@example.com", "bob@example.com"}},
+		},
+		{
+			name:    "duplicate between to and recipients",
+			req:     SendEmailRequest{To: "jane@example.com", Recipients: []string{"Jane <jane@example.com>"}},
+			wantErr: "duplicate recipient",
+		},
+		{
+			name:    "invalid cc",
+			req:     SendEmailRequest{To: "jane@example.com", CC: []string{"support"}},
+			wantErr: "invalid email address: support",
+		},
+		{
+			name:    "invalid reply-to",
+			req:     SendEmailRequest{To: "jane@example.com", ReplyTo: "tickets"},
+			wantErr: "invalid reply_to address",
+		},
+		{
+			name:    "duplicate across lists",
+			req:     SendEmailRequest{To: "jane@example.com", BCC: []string{"Jane <JANE@example.com>"}},
+			wantErr: "duplicate recipient",
+		},
+		{
+			name:    "cc without to",
+			req:     SendEmailRequest{CC: []string{"jane@example.com"}},
+			wantErr: "to is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tt.req.TemplateID = "welcome"
+			err := tt.req.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSendEmailRequest_ValidateAttachments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		attachments []Attachment
+		wantErr     string
+	}{
+		{
+			name:        "valid attachment",
+			attachments: []Attachment{{Filename: "invoice.pdf", ContentType: "application/pdf", Content: []byte("pdf")}},
+		},
+		{
+			name:        "valid inline attachment",
+			attachments: []Attachment{{Filename: "logo.png", Content: []byte("png"), Disposition: DispositionInline, ContentID: "logo"}},
+		},
+		{
+			name:        "missing filename",
+			attachments: []Attachment{{Content: []byte("pdf")}},
+			wantErr:     "filename is required",
+		},
+		{
+			name:        "empty content",
+			attachments: []Attachment{{Filename: "invoice.pdf"}},
+			wantErr:     "content is required",
+		},
+		{
+			name:        "inline without content ID",
+			attachments: []Attachment{{Filename: "logo.png", Content: []byte("png"), Disposition: DispositionInline}},
+			wantErr:     "content_id is required",
+		},
+		{
+			name:        "unknown disposition",
+			attachments: []Attachment{{Filename: "logo.png", Content: []byte("png"), Disposition: "embedded"}},
+			wantErr:     "invalid disposition",
+		},
+		{
+			name:        "content type with parameters",
+			attachments: []Attachment{{Filename: "notes.txt", ContentType: "text/plain; charset=utf-8", Content: []byte("txt")}},
+		},
+		{
+			name:        "content type without subtype",
+			attachments: []Attachment{{Filename: "notes.txt", ContentType: "text", Content: []byte("txt")}},
+			wantErr:     "invalid content_type",
+		},
+		{
+			name:        "content type injecting a header",
+			attachments: []Attachment{{Filename: "notes.txt", ContentType: "text/plain\r\nBcc: victim@example.com", Content: []byte("txt")}},
+			wantErr:     "invalid content_type",
+		},
+		{
+			name:        "content type with a folded parameter",
+			attachments: []Attachment{{Filename: "notes.txt", ContentType: "text/plain;\r\n charset=utf-8", Content: []byte("txt")}},
+			wantErr:     "invalid content_type",
+		},
+		{
+			name:        "content ID injecting a header",
+			attachments: []Attachment{{Filename: "logo.png", Content: []byte("png"), Disposition: DispositionInline, ContentID: "logo>\r\nBcc: victim@example.com"}},
+			wantErr:     "invalid content_id",
+		},
+		{
+			name:        "attachment too large",
+			attachments: []Attachment{{Filename: "big.bin", Content: make([]byte, MaxAttachmentSize+1)}},
+			wantErr:     "attachment limit",
+		},
+		{
+			name: "total too large",
+			attachments: []Attachment{
+				{Filename: "a.bin", Content: make([]byte, MaxAttachmentSize)},
+				{Filename: "b.bin", Content: make([]byte, MaxAttachmentSize)},
+				{Filename: "c.bin", Content: make([]byte, 1)},
+			},
+			wantErr: "total limit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := &SendEmailRequest{
+				TemplateID:  "welcome",
+				To:          "user@example.com",
+				Attachments: tt.attachments,
+			}
+			err := r.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
 func TestSendEmailBatchRequest_Validate(t *testing.T) {
 	t.Parallel()
 
@@ -107,4 +304,194 @@ func TestSendEmailBatchRequest_Validate(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "index 1")
 	})
+
+	t.Run("best effort defers per-email validation to the server", func(t *testing.T) {
+		t.Parallel()
+		r := &SendEmailBatchRequest{
+			Mode: BatchModeBestEffort,
+			Emails: []SendEmailRequest{
+				{TemplateID: "welcome", To: "alice@example.com"},
+				{TemplateID: "welcome", To: "notanemail"},
+			},
+		}
+		require.NoError(t, r.Validate())
+	})
+
+	t.Run("attachments over the batch limit", func(t *testing.T) {
+		t.Parallel()
+		attachments := []Attachment{{Filename: "report.pdf", Content: make([]byte, MaxAttachmentSize)}}
+		r := &SendEmailBatchRequest{
+			Mode: BatchModeBestEffort,
+			Emails: []SendEmailRequest{
+				{TemplateID: "welcome", To: "alice@example.com", Attachments: attachments},
+				{TemplateID: "welcome", To: "bob@example.com", Attachments: attachments},
+				{TemplateID: "welcome", To: "carol@example.com", Attachments: attachments},
+			},
+		}
+		err := r.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "batch limit")
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		t.Parallel()
+		r := &SendEmailBatchRequest{
+			Mode:   "sometimes",
+			Emails: []SendEmailRequest{{TemplateID: "welcome", To: "alice@example.com"}},
+		}
+		err := r.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid batch mode")
+	})
+}
+
+func TestRescheduleEmailRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	later := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		req     RescheduleEmailRequest
+		wantErr string
+	}{
+		{
+			name: "by message ID",
+			req:  RescheduleEmailRequest{MessageID: "42", ScheduledAt: later},
+		},
+		{
+			name: "by cancellation key",
+			req:  RescheduleEmailRequest{CancellationKey: "onboarding:alice", ScheduledAt: later},
+		},
+		{
+			name:    "no target",
+			req:     RescheduleEmailRequest{ScheduledAt: later},
+			wantErr: "message_id or cancellation_key is required",
+		},
+		{
+			name:    "both targets",
+			req:     RescheduleEmailRequest{MessageID: "42", CancellationKey: "onboarding:alice", ScheduledAt: later},
+			wantErr: "only one of",
+		},
+		{
+			name:    "missing time",
+			req:     RescheduleEmailRequest{MessageID: "42"},
+			wantErr: "scheduled_at is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.req.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestAddSuppressionRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		req     AddSuppressionRequest
+		wantErr string
+	}{
+		{
+			name: "permanent suppression",
+			req:  AddSuppressionRequest{Address: "user@example.com", Reason: SuppressionReasonBounce},
+		},
+		{
+			name: "expiring suppression",
+			req:  AddSuppressionRequest{Address: "user@example.com", Reason: SuppressionReasonUnsubscribe, ExpiresAt: &future},
+		},
+		{
+			name:    "missing address",
+			req:     AddSuppressionRequest{Reason: SuppressionReasonManual},
+			wantErr: "address is required",
+		},
+		{
+			name:    "invalid address",
+			req:     AddSuppressionRequest{Address: "notanemail", Reason: SuppressionReasonManual},
+			wantErr: "invalid email address",
+		},
+		{
+			name:    "missing reason",
+			req:     AddSuppressionRequest{Address: "user@example.com"},
+			wantErr: "reason is required",
+		},
+		{
+			name:    "unknown reason",
+			req:     AddSuppressionRequest{Address: "user@example.com", Reason: "annoyed"},
+			wantErr: "invalid reason",
+		},
+		{
+			name:    "expiry in the past",
+			req:     AddSuppressionRequest{Address: "user@example.com", Reason: SuppressionReasonManual, ExpiresAt: &past},
+			wantErr: "expires_at must be in the future",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.req.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestRenderEmailRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		req     RenderEmailRequest
+		wantErr string
+	}{
+		{
+			name: "latest version",
+			req:  RenderEmailRequest{TemplateID: "welcome", Variables: map[string]string{"Name": "Alice"}},
+		},
+		{
+			name: "pinned version",
+			req:  RenderEmailRequest{TemplateID: "welcome", TemplateVersion: 2},
+		},
+		{
+			name:    "missing template",
+			req:     RenderEmailRequest{},
+			wantErr: "template_id is required",
+		},
+		{
+			name:    "negative version",
+			req:     RenderEmailRequest{TemplateID: "welcome", TemplateVersion: -1},
+			wantErr: "template_version cannot be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.req.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
 }
@@ -2,11 +2,15 @@ package test
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/travisbale/mailman/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestSendEmail(t *testing.T) {
@@ -20,10 +24,64 @@ func TestSendEmail(t *testing.T) {
 		Variables:  map[string]string{"Name": "Alice"},
 	}
 
+	resp, err := testClient.SendEmail(ctx, req)
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.MessageID)
+}
+
+func TestSendEmailWithMultipleRecipients(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	req := sdk.SendEmailRequest{
+		TemplateID: "simple_template",
+		To:         "Alice <alice@example.com>",
+		Recipients: []string{"bob@example.com"},
+		CC:         []string{"support@example.com"},
+		BCC:        []string{"audit@example.com"},
+		ReplyTo:    "Tickets <tickets@example.com>",
+		Variables:  map[string]string{"Name": "Alice"},
+	}
+
+	resp, err := testClient.SendEmail(ctx, req)
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.MessageID)
+}
+
+func TestSendEmailWithSender(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	req := sdk.SendEmailRequest{
+		TemplateID: "simple_template",
+		To:         "user@example.com",
+		Sender:     "billing@example.com",
+		Variables:  map[string]string{"Name": "Alice"},
+	}
+
 	_, err := testClient.SendEmail(ctx, req)
 	require.NoError(t, err)
 }
 
+func TestSendEmailUnregisteredSender(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	req := sdk.SendEmailRequest{
+		TemplateID: "simple_template",
+		To:         "user@example.com",
+		Sender:     "ceo@example.com",
+		Variables:  map[string]string{"Name": "Alice"},
+	}
+
+	_, err := testClient.SendEmail(ctx, req)
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
 func TestSendEmailWithMultipleVariables(t *testing.T) {
 	t.Parallel()
 
@@ -90,6 +148,130 @@ func TestSendEmailMissingVariables(t *testing.T) {
 	assert.Contains(t, err.Error(), "Company")
 }
 
+func TestSendEmailDryRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	resp, err := testClient.SendEmail(ctx, sdk.SendEmailRequest{
+		TemplateID: "simple_template",
+		To:         "user@example.com",
+		Variables:  map[string]string{"Name": "Alice"},
+		DryRun:     true,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, resp.MessageID, "dry runs are not enqueued")
+
+	// Dry runs still fail validation
+	_, err = testClient.SendEmail(ctx, sdk.SendEmailRequest{
+		TemplateID: "multi_var_template",
+		To:         "user@example.com",
+		Variables:  map[string]string{"Name": "Alice"},
+		DryRun:     true,
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestSendEmailIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Keys outlive a test run, so each run needs its own
+	key := fmt.Sprintf("receipt:%d", time.Now().UnixNano())
+	req := sdk.SendEmailRequest{
+		TemplateID:     "simple_template",
+		To:             "user@example.com",
+		Variables:      map[string]string{"Name": "Alice"},
+		IdempotencyKey: key,
+	}
+
+	first, err := testClient.SendEmail(ctx, req)
+	require.NoError(t, err)
+	require.NotEmpty(t, first.MessageID)
+
+	// A retry returns the original email even if the request changed
+	req.Variables = map[string]string{"Name": "Bob"}
+	retry, err := testClient.SendEmail(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, first.MessageID, retry.MessageID)
+
+	// Identical emails without a key are both sent
+	req.IdempotencyKey = ""
+	second, err := testClient.SendEmail(ctx, req)
+	require.NoError(t, err)
+	third, err := testClient.SendEmail(ctx, req)
+	require.NoError(t, err)
+	assert.NotEqual(t, second.MessageID, third.MessageID)
+
+	// Within a batch, repeated keys share one email
+	batchKey := key + ":batch"
+	batch, err := testClient.SendEmailBatch(ctx, sdk.SendEmailBatchRequest{
+		Emails: []sdk.SendEmailRequest{
+			{TemplateID: "simple_template", To: "user@example.com", Variables: map[string]string{"Name": "Alice"}, IdempotencyKey: batchKey},
+			{TemplateID: "simple_template", To: "user@example.com", Variables: map[string]string{"Name": "Alice"}, IdempotencyKey: batchKey},
+			{TemplateID: "simple_template", To: "user@example.com", Variables: map[string]string{"Name": "Alice"}, IdempotencyKey: key},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, batch.Results, 3)
+	assert.Equal(t, batch.Results[0].MessageID, batch.Results[1].MessageID)
+	assert.Equal(t, first.MessageID, batch.Results[2].MessageID)
+}
+
+func TestRenderEmail(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	rendered, err := testClient.RenderEmail(ctx, sdk.RenderEmailRequest{
+		TemplateID: "simple_template",
+		Variables:  map[string]string{"Name": "Alice"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "[simple_template]", rendered.Subject)
+	assert.Contains(t, rendered.HTMLBody, "Alice")
+}
+
+func TestRenderEmailWithData(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	rendered, err := testClient.RenderEmail(ctx, sdk.RenderEmailRequest{
+		TemplateID: "simple_template",
+		Data:       map[string]any{"Name": "Alice", "Order": map[string]any{"Items": []any{"book", "pen"}}},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, rendered.HTMLBody, "Alice")
+
+	// A variable may not be set in both places
+	_, err = testClient.RenderEmail(ctx, sdk.RenderEmailRequest{
+		TemplateID: "simple_template",
+		Variables:  map[string]string{"Name": "Alice"},
+		Data:       map[string]any{"Name": "Bob"},
+	})
+	require.Error(t, err)
+}
+
+func TestRenderEmailErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	_, err := testClient.RenderEmail(ctx, sdk.RenderEmailRequest{
+		TemplateID: "multi_var_template",
+		Variables:  map[string]string{"Name": "Alice"},
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = testClient.RenderEmail(ctx, sdk.RenderEmailRequest{TemplateID: "does_not_exist"})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
 func TestSendEmailBatch(t *testing.T) {
 	t.Parallel()
 
@@ -113,4 +295,254 @@ func TestSendEmailBatch(t *testing.T) {
 	resp, err := testClient.SendEmailBatch(ctx, req)
 	require.NoError(t, err)
 	assert.Len(t, resp.Results, 2)
+	assert.NotEmpty(t, resp.Results[0].MessageID)
+	assert.NotEqual(t, resp.Results[0].MessageID, resp.Results[1].MessageID)
+}
+
+func TestSendEmailBatchBestEffort(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	req := sdk.SendEmailBatchRequest{
+		Mode: sdk.BatchModeBestEffort,
+		Emails: []sdk.SendEmailRequest{
+			{
+				TemplateID: "simple_template",
+				To:         "besteffort1@example.com",
+				Variables:  map[string]string{"Name": "Alice"},
+			},
+			{
+				TemplateID: "does_not_exist",
+				To:         "besteffort2@example.com",
+			},
+			{
+				TemplateID: "simple_template",
+				To:         "notanemail",
+			},
+		},
+	}
+
+	resp, err := testClient.SendEmailBatch(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 3)
+
+	assert.True(t, resp.Results[0].OK())
+	assert.NotEmpty(t, resp.Results[0].MessageID)
+
+	assert.Equal(t, codes.NotFound, resp.Results[1].Code)
+	assert.Empty(t, resp.Results[1].MessageID)
+	assert.Contains(t, resp.Results[1].Error, "does_not_exist")
+
+	assert.Equal(t, codes.InvalidArgument, resp.Results[2].Code)
+}
+
+func TestGetEmailStatus(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	scheduledAt := time.Now().Add(1 * time.Hour)
+	req := sdk.SendEmailRequest{
+		TemplateID:  "simple_template",
+		To:          "status@example.com",
+		Variables:   map[string]string{"Name": "Alice"},
+		ScheduledAt: &scheduledAt,
+	}
+
+	sent, err := testClient.SendEmail(ctx, req)
+	require.NoError(t, err)
+
+	emailStatus, err := testClient.GetEmailStatus(ctx, sent.MessageID)
+	require.NoError(t, err)
+	assert.Equal(t, sent.MessageID, emailStatus.MessageID)
+	assert.Equal(t, sdk.EmailStateScheduled, emailStatus.State)
+	assert.Equal(t, int32(0), emailStatus.Attempts)
+	assert.WithinDuration(t, scheduledAt, emailStatus.ScheduledAt, time.Second)
+}
+
+func TestGetEmailStatusNotFound(t *testing.T) {
+	t.Parallel()
+
+	_, err := testClient.GetEmailStatus(context.Background(), "999999999")
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCancelScheduledEmail(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	scheduledAt := time.Now().Add(1 * time.Hour)
+	sent, err := testClient.SendEmail(ctx, sdk.SendEmailRequest{
+		TemplateID:  "simple_template",
+		To:          "cancel@example.com",
+		Variables:   map[string]string{"Name": "Alice"},
+		ScheduledAt: &scheduledAt,
+	})
+	require.NoError(t, err)
+
+	resp, err := testClient.CancelEmail(ctx, sdk.CancelEmailRequest{MessageID: sent.MessageID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.Cancelled)
+
+	emailStatus, err := testClient.GetEmailStatus(ctx, sent.MessageID)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.EmailStateCancelled, emailStatus.State)
+
+	// A cancelled email can no longer be rescheduled
+	_, err = testClient.RescheduleEmail(ctx, sdk.RescheduleEmailRequest{
+		MessageID:   sent.MessageID,
+		ScheduledAt: time.Now().Add(2 * time.Hour),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestCancelEmailGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	scheduledAt := time.Now().Add(1 * time.Hour)
+	for _, to := range []string{"group1@example.com", "group2@example.com"} {
+		_, err := testClient.SendEmail(ctx, sdk.SendEmailRequest{
+			TemplateID:      "simple_template",
+			To:              to,
+			Variables:       map[string]string{"Name": "Alice"},
+			ScheduledAt:     &scheduledAt,
+			CancellationKey: "onboarding:group-test",
+		})
+		require.NoError(t, err)
+	}
+
+	// An otherwise identical email with another key is enqueued separately
+	other, err := testClient.SendEmail(ctx, sdk.SendEmailRequest{
+		TemplateID:      "simple_template",
+		To:              "group1@example.com",
+		Variables:       map[string]string{"Name": "Alice"},
+		ScheduledAt:     &scheduledAt,
+		CancellationKey: "onboarding:other-group-test",
+	})
+	require.NoError(t, err)
+
+	resp, err := testClient.CancelEmail(ctx, sdk.CancelEmailRequest{CancellationKey: "onboarding:group-test"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.Cancelled)
+
+	// and is left alone
+	emailStatus, err := testClient.GetEmailStatus(ctx, other.MessageID)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.EmailStateScheduled, emailStatus.State)
+
+	rescheduled, err := testClient.RescheduleEmail(ctx, sdk.RescheduleEmailRequest{
+		CancellationKey: "onboarding:other-group-test",
+		ScheduledAt:     scheduledAt.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, rescheduled.Rescheduled)
+}
+
+func TestRescheduleEmail(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	scheduledAt := time.Now().Add(1 * time.Hour)
+	sent, err := testClient.SendEmail(ctx, sdk.SendEmailRequest{
+		TemplateID:  "simple_template",
+		To:          "reschedule@example.com",
+		Variables:   map[string]string{"Name": "Alice"},
+		ScheduledAt: &scheduledAt,
+	})
+	require.NoError(t, err)
+
+	newTime := time.Now().Add(3 * time.Hour)
+	resp, err := testClient.RescheduleEmail(ctx, sdk.RescheduleEmailRequest{
+		MessageID:   sent.MessageID,
+		ScheduledAt: newTime,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.Rescheduled)
+
+	emailStatus, err := testClient.GetEmailStatus(ctx, sent.MessageID)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.EmailStateScheduled, emailStatus.State)
+	assert.WithinDuration(t, newTime, emailStatus.ScheduledAt, time.Second)
+
+	// Rescheduling into the past hands the email to River's workers straight away
+	_, err = testClient.RescheduleEmail(ctx, sdk.RescheduleEmailRequest{
+		MessageID:   sent.MessageID,
+		ScheduledAt: time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		job, err := testClient.GetJob(ctx, sent.MessageID)
+		return err == nil && job.Attempts > 0
+	}, 30*time.Second, 250*time.Millisecond)
+}
+
+func TestCancelEmailNotFound(t *testing.T) {
+	t.Parallel()
+
+	_, err := testClient.CancelEmail(context.Background(), sdk.CancelEmailRequest{MessageID: "999999999"})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestJobs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	scheduledAt := time.Now().Add(1 * time.Hour)
+	var messageIDs []string
+	for _, to := range []string{"jobs1@example.com", "Jobs2@Example.com"} {
+		sent, err := testClient.SendEmail(ctx, sdk.SendEmailRequest{
+			TemplateID:  "simple_template",
+			To:          to,
+			Variables:   map[string]string{"Name": "Alice"},
+			ScheduledAt: &scheduledAt,
+		})
+		require.NoError(t, err)
+		messageIDs = append(messageIDs, sent.MessageID)
+	}
+
+	filter := &sdk.JobFilter{TemplateID: "simple_template", Recipient: "jobs2@example.com"}
+	list, err := testClient.ListJobs(ctx, sdk.ListJobsRequest{Filter: *filter})
+	require.NoError(t, err)
+	require.Len(t, list.Jobs, 1)
+	assert.Equal(t, messageIDs[1], list.Jobs[0].MessageID)
+	assert.Equal(t, sdk.EmailStateScheduled, list.Jobs[0].State)
+	assert.Equal(t, "simple_template", list.Jobs[0].TemplateID)
+
+	job, err := testClient.GetJob(ctx, messageIDs[0])
+	require.NoError(t, err)
+	assert.Equal(t, []string{"jobs1@example.com"}, job.To)
+
+	// Scheduled emails haven't failed, so there is nothing to retry or discard
+	retried, err := testClient.RetryJobs(ctx, sdk.JobsRequest{MessageIDs: append(messageIDs[:1:1], "999999999")})
+	var skippedErr *sdk.SkippedJobsError
+	require.ErrorAs(t, err, &skippedErr)
+	assert.Zero(t, retried)
+	require.Len(t, skippedErr.Skipped, 2)
+	assert.Equal(t, messageIDs[0], skippedErr.Skipped[0].MessageID)
+
+	cancelled, err := testClient.CancelJobs(ctx, sdk.JobsRequest{Filter: filter})
+	require.NoError(t, err)
+	assert.Equal(t, 1, cancelled)
+
+	discarded, err := testClient.DiscardJobs(ctx, sdk.JobsRequest{Filter: filter})
+	require.NoError(t, err)
+	assert.Equal(t, 1, discarded)
+
+	_, err = testClient.GetJob(ctx, messageIDs[1])
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = testClient.ListJobs(ctx, sdk.ListJobsRequest{PageToken: "not a token"})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
 }
@@ -34,13 +34,20 @@ func TestMain(m *testing.M) {
 		os.Exit(1)
 	}
 
-	// Create SDK client
-	testClient, err = newTestClient(ctx, mailman)
+	// Create SDK clients
+	testClient, err = newTestClient(ctx, mailman, apiKey)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to create test client: %v\n", err)
 		os.Exit(1)
 	}
 	defer testClient.Close()
 
+	tenantClient, err = newTestClient(ctx, mailman, tenantAPIKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create tenant test client: %v\n", err)
+		os.Exit(1)
+	}
+	defer tenantClient.Close()
+
 	os.Exit(m.Run())
 }
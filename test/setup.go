@@ -2,6 +2,7 @@ package test
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"time"
 
@@ -14,6 +15,9 @@ import (
 // testClient is the shared SDK client used by all tests.
 var testClient *sdk.GRPCClient
 
+// tenantClient acts for a second tenant, to check that tenants are isolated.
+var tenantClient *sdk.GRPCClient
+
 // grpcAddress is the host:port address of the mailman gRPC server, used by raw
 // gRPC clients in validation tests.
 var grpcAddress string
@@ -24,6 +28,22 @@ const (
 	dbPassword = "test_password"
 )
 
+// Test API key, seeded with every scope. Keys are "mm_<id>_<secret>".
+const (
+	apiKeyID     = "integration"
+	apiKeySecret = "test_secret"
+	apiKey       = "mm_" + apiKeyID + "_" + apiKeySecret
+)
+
+// Second tenant, seeded with its own copy of simple_template and a key that can
+// send and manage its suppressions.
+const (
+	tenantID           = "payroll"
+	tenantAPIKeyID     = "integration-payroll"
+	tenantAPIKeySecret = "test_payroll_secret"
+	tenantAPIKey       = "mm_" + tenantAPIKeyID + "_" + tenantAPIKeySecret
+)
+
 // postgresContainer starts a PostgreSQL testcontainer and returns the container
 // along with the connection string accessible from the host and from within the
 // Docker network.
@@ -81,13 +101,13 @@ func seedTemplates(ctx context.Context, databaseURL string) error {
 	}
 	defer pool.Close()
 
-	// Wait for the email_templates table to exist (mailman runs migrations on startup)
+	// Wait for the api_keys table to exist (mailman runs migrations on startup)
 	for range 30 {
 		var exists bool
 		err := pool.QueryRow(ctx, `
 			SELECT EXISTS (
 				SELECT FROM information_schema.tables
-				WHERE table_name = 'email_templates'
+				WHERE table_name = 'api_keys'
 			)
 		`).Scan(&exists)
 
@@ -139,13 +159,60 @@ func seedTemplates(ctx context.Context, databaseURL string) error {
 		_, err := pool.Exec(ctx, `
 			INSERT INTO email_templates (name, subject, html_body, text_body, base_template_name, variables)
 			VALUES ($1, $2, $3, $4, $5, $6)
-			ON CONFLICT (name) DO NOTHING
+			ON CONFLICT DO NOTHING
 		`, t.name, t.subject, t.htmlBody, t.textBody, t.baseTemplateName, t.vars)
 		if err != nil {
 			return fmt.Errorf("failed to seed template %s: %w", t.name, err)
 		}
 	}
 
+	_, err = pool.Exec(ctx, `
+		INSERT INTO senders (address, name)
+		VALUES ('billing@example.com', 'Example Billing')
+		ON CONFLICT (address) DO NOTHING
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to seed sender: %w", err)
+	}
+
+	secretHash := sha256.Sum256([]byte(apiKeySecret))
+	_, err = pool.Exec(ctx, `
+		INSERT INTO api_keys (id, name, secret_hash, scopes)
+		VALUES ($1, 'integration tests', $2, '{send,templates:read,templates:write,suppressions:write,jobs:read,jobs:write}')
+		ON CONFLICT (id) DO NOTHING
+	`, apiKeyID, secretHash[:])
+	if err != nil {
+		return fmt.Errorf("failed to seed API key: %w", err)
+	}
+
+	_, err = pool.Exec(ctx, `
+		INSERT INTO tenants (id, name)
+		VALUES ($1, 'Payroll')
+		ON CONFLICT (id) DO NOTHING
+	`, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to seed tenant: %w", err)
+	}
+
+	_, err = pool.Exec(ctx, `
+		INSERT INTO email_templates (tenant_id, name, subject, html_body, variables)
+		VALUES ($1, 'simple_template', 'Payslip for {{.Name}}', '<p>Hi {{.Name}}</p>', '{Name}')
+		ON CONFLICT DO NOTHING
+	`, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to seed tenant template: %w", err)
+	}
+
+	tenantSecretHash := sha256.Sum256([]byte(tenantAPIKeySecret))
+	_, err = pool.Exec(ctx, `
+		INSERT INTO api_keys (id, tenant_id, name, secret_hash, scopes)
+		VALUES ($1, $2, 'integration tests (payroll)', $3, '{send,suppressions:write}')
+		ON CONFLICT (id) DO NOTHING
+	`, tenantAPIKeyID, tenantID, tenantSecretHash[:])
+	if err != nil {
+		return fmt.Errorf("failed to seed tenant API key: %w", err)
+	}
+
 	return nil
 }
 
@@ -180,8 +247,9 @@ func mailmanContainer(ctx context.Context, internalDSN string) (testcontainers.C
 	return container, nil
 }
 
-// newTestClient creates an SDK client connected to the mailman container's gRPC port.
-func newTestClient(ctx context.Context, mailman testcontainers.Container) (*sdk.GRPCClient, error) {
+// newTestClient creates an SDK client connected to the mailman container's gRPC
+// port that authenticates with the given API key.
+func newTestClient(ctx context.Context, mailman testcontainers.Container, apiKey string) (*sdk.GRPCClient, error) {
 	host, err := mailman.Host(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get mailman host: %w", err)
@@ -194,7 +262,7 @@ func newTestClient(ctx context.Context, mailman testcontainers.Container) (*sdk.
 
 	grpcAddress = fmt.Sprintf("%s:%s", host, port.Port())
 
-	client, err := sdk.NewGRPCClient(grpcAddress)
+	client, err := sdk.NewGRPCClient(grpcAddress, sdk.WithAPIKey(apiKey))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
 	}
@@ -0,0 +1,86 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSendEmailSuppressedRecipient(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	suppression, err := testClient.AddSuppression(ctx, sdk.AddSuppressionRequest{
+		Address: "Bounced@Example.com",
+		Reason:  sdk.SuppressionReasonBounce,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "bounced@example.com", suppression.Address)
+	assert.Equal(t, "api", suppression.Source)
+	assert.Nil(t, suppression.ExpiresAt)
+
+	req := sdk.SendEmailRequest{
+		TemplateID: "simple_template",
+		To:         "bounced@example.com",
+		Variables:  map[string]string{"Name": "Alice"},
+	}
+
+	_, err = testClient.SendEmail(ctx, req)
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	require.NoError(t, testClient.RemoveSuppression(ctx, "bounced@example.com"))
+
+	_, err = testClient.SendEmail(ctx, req)
+	require.NoError(t, err)
+}
+
+func TestExpiredSuppression(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(2 * time.Second)
+	_, err := testClient.AddSuppression(ctx, sdk.AddSuppressionRequest{
+		Address:   "snoozed@example.com",
+		Reason:    sdk.SuppressionReasonUnsubscribe,
+		ExpiresAt: &expiresAt,
+	})
+	require.NoError(t, err)
+
+	suppressions, err := testClient.ListSuppressions(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, suppressionAddresses(suppressions), "snoozed@example.com")
+
+	time.Sleep(time.Until(expiresAt) + 500*time.Millisecond)
+
+	_, err = testClient.SendEmail(ctx, sdk.SendEmailRequest{
+		TemplateID: "simple_template",
+		To:         "snoozed@example.com",
+		Variables:  map[string]string{"Name": "Alice"},
+	})
+	require.NoError(t, err)
+}
+
+func TestRemoveSuppressionNotFound(t *testing.T) {
+	t.Parallel()
+
+	err := testClient.RemoveSuppression(context.Background(), "never-suppressed@example.com")
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func suppressionAddresses(suppressions []sdk.Suppression) []string {
+	addresses := make([]string, len(suppressions))
+	for i, suppression := range suppressions {
+		addresses[i] = suppression.Address
+	}
+	return addresses
+}
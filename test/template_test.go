@@ -6,6 +6,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestListTemplates(t *testing.T) {
@@ -44,3 +47,245 @@ func TestListTemplates(t *testing.T) {
 	assert.Equal(t, "Welcome {{.Name}} from {{.Company}}!", multi.subject)
 	assert.ElementsMatch(t, []string{"Name", "Company"}, multi.vars)
 }
+
+// The CRUD tests below are deliberately not parallel: Go runs them before any
+// parallel test starts, and each one deletes what it creates, so
+// TestListTemplates still sees only the seeded templates.
+
+func TestTemplateCRUD(t *testing.T) {
+	ctx := context.Background()
+
+	created, err := testClient.CreateTemplate(ctx, sdk.CreateTemplateRequest{
+		ID:        "crud_template",
+		Subject:   "Hi {{.Name}}",
+		HTMLBody:  "<p>Hi {{.Name}}</p>",
+		TextBody:  "Hi {{.Name}}",
+		Variables: []string{"Name"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), created.Version)
+
+	fetched, err := testClient.GetTemplate(ctx, "crud_template")
+	require.NoError(t, err)
+	assert.Equal(t, "<p>Hi {{.Name}}</p>", fetched.HTMLBody)
+	assert.Equal(t, "Hi {{.Name}}", fetched.TextBody)
+
+	updated, err := testClient.UpdateTemplate(ctx, sdk.UpdateTemplateRequest{
+		ID:        "crud_template",
+		Subject:   "Hello {{.Name}}",
+		HTMLBody:  "<p>Hello {{.Name}}</p>",
+		Variables: []string{"Name"},
+		Version:   fetched.Version,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), updated.Version)
+	assert.Equal(t, "Hello {{.Name}}", updated.Subject)
+
+	// A second writer holding the old version loses
+	_, err = testClient.UpdateTemplate(ctx, sdk.UpdateTemplateRequest{
+		ID:       "crud_template",
+		HTMLBody: "<p>stale</p>",
+		Version:  fetched.Version,
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	require.NoError(t, testClient.DeleteTemplate(ctx, "crud_template", updated.Version))
+
+	_, err = testClient.GetTemplate(ctx, "crud_template")
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestLocalizedTemplate(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := testClient.CreateTemplate(ctx, sdk.CreateTemplateRequest{
+		ID:       "localized_template",
+		Subject:  "Welcome",
+		HTMLBody: "<p>Welcome</p>",
+	})
+	require.NoError(t, err)
+
+	french, err := testClient.CreateTemplate(ctx, sdk.CreateTemplateRequest{
+		ID:       "localized_template",
+		Locale:   "fr",
+		Subject:  "Bienvenue",
+		HTMLBody: "<p>Bienvenue</p>",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "fr", french.Locale)
+
+	// fr-CA falls back to fr, and locales without a variant fall back to the default
+	fetched, err := testClient.GetLocalizedTemplate(ctx, "localized_template", "fr-CA")
+	require.NoError(t, err)
+	assert.Equal(t, "Bienvenue", fetched.Subject)
+
+	fetched, err = testClient.GetLocalizedTemplate(ctx, "localized_template", "de")
+	require.NoError(t, err)
+	assert.Equal(t, "Welcome", fetched.Subject)
+	assert.Empty(t, fetched.Locale)
+
+	rendered, err := testClient.RenderEmail(ctx, sdk.RenderEmailRequest{
+		TemplateID: "localized_template",
+		Locale:     "fr-CA",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, rendered.HTMLBody, `"locale": "fr"`)
+
+	_, err = testClient.RenderEmail(ctx, sdk.RenderEmailRequest{
+		TemplateID: "localized_template",
+		Locale:     "not a locale",
+	})
+	require.Error(t, err)
+
+	require.NoError(t, testClient.DeleteLocalizedTemplate(ctx, "localized_template", "fr", 0))
+	require.NoError(t, testClient.DeleteTemplate(ctx, "localized_template", 0))
+}
+
+func TestCreateTemplateDuplicate(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := testClient.CreateTemplate(ctx, sdk.CreateTemplateRequest{
+		ID:       "simple_template",
+		HTMLBody: "<p>duplicate</p>",
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
+
+func TestDeleteTemplateInUse(t *testing.T) {
+	ctx := context.Background()
+
+	// nested_template inherits from base_layout
+	err := testClient.DeleteTemplate(ctx, "base_layout", 0)
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Contains(t, err.Error(), "nested_template")
+}
+
+func TestUpdateTemplateCircularReference(t *testing.T) {
+	ctx := context.Background()
+
+	layout, err := testClient.GetTemplate(ctx, "base_layout")
+	require.NoError(t, err)
+
+	_, err = testClient.UpdateTemplate(ctx, sdk.UpdateTemplateRequest{
+		ID:             "base_layout",
+		HTMLBody:       layout.HTMLBody,
+		BaseTemplateID: "nested_template",
+		Version:        layout.Version,
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestSendPinnedTemplateVersion(t *testing.T) {
+	ctx := context.Background()
+
+	created, err := testClient.CreateTemplate(ctx, sdk.CreateTemplateRequest{
+		ID:        "pinned_template",
+		Subject:   "v1 {{.Name}}",
+		HTMLBody:  "<p>v1 {{.Name}}</p>",
+		Variables: []string{"Name"},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = testClient.DeleteTemplate(ctx, "pinned_template", 0) })
+
+	// v2 drops the Name variable, so only a send pinned to v1 requires it
+	_, err = testClient.UpdateTemplate(ctx, sdk.UpdateTemplateRequest{
+		ID:       "pinned_template",
+		Subject:  "v2",
+		HTMLBody: "<p>v2</p>",
+		Version:  created.Version,
+	})
+	require.NoError(t, err)
+
+	_, err = testClient.SendEmail(ctx, sdk.SendEmailRequest{
+		TemplateID:      "pinned_template",
+		TemplateVersion: created.Version,
+		To:              "user@example.com",
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = testClient.SendEmail(ctx, sdk.SendEmailRequest{
+		TemplateID:      "pinned_template",
+		TemplateVersion: created.Version,
+		To:              "user@example.com",
+		Variables:       map[string]string{"Name": "Alice"},
+	})
+	require.NoError(t, err)
+
+	_, err = testClient.SendEmail(ctx, sdk.SendEmailRequest{
+		TemplateID:      "pinned_template",
+		TemplateVersion: 99,
+		To:              "user@example.com",
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestPinnedTemplateVersionKeepsItsBase(t *testing.T) {
+	ctx := context.Background()
+
+	layout, err := testClient.CreateTemplate(ctx, sdk.CreateTemplateRequest{
+		ID:       "pinned_layout",
+		HTMLBody: `<main>v1 {{template "content" .}}</main>`,
+	})
+	require.NoError(t, err)
+
+	page, err := testClient.CreateTemplate(ctx, sdk.CreateTemplateRequest{
+		ID:             "pinned_page",
+		Subject:        "Page",
+		HTMLBody:       `{{define "content"}}page{{end}}`,
+		BaseTemplateID: "pinned_layout",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = testClient.DeleteTemplate(ctx, "pinned_page", 0)
+		_ = testClient.DeleteTemplate(ctx, "pinned_layout", 0)
+	})
+
+	_, err = testClient.UpdateTemplate(ctx, sdk.UpdateTemplateRequest{
+		ID:       "pinned_layout",
+		HTMLBody: `<main>v2 {{template "content" .}}</main>`,
+		Version:  layout.Version,
+	})
+	require.NoError(t, err)
+
+	// The latest version uses the current base, a pinned one the base it was published with
+	latest, err := testClient.RenderEmail(ctx, sdk.RenderEmailRequest{TemplateID: "pinned_page"})
+	require.NoError(t, err)
+	assert.Contains(t, latest.HTMLBody, "v2 page")
+
+	pinned, err := testClient.RenderEmail(ctx, sdk.RenderEmailRequest{TemplateID: "pinned_page", TemplateVersion: page.Version})
+	require.NoError(t, err)
+	assert.Contains(t, pinned.HTMLBody, "v1 page")
+}
+
+func TestDeletedTemplateKeepsHistory(t *testing.T) {
+	ctx := context.Background()
+
+	created, err := testClient.CreateTemplate(ctx, sdk.CreateTemplateRequest{
+		ID:       "recreated_template",
+		Subject:  "First",
+		HTMLBody: "<p>first</p>",
+	})
+	require.NoError(t, err)
+	require.NoError(t, testClient.DeleteTemplate(ctx, "recreated_template", created.Version))
+
+	// A template created again under the name carries on from the deleted one's versions
+	recreated, err := testClient.CreateTemplate(ctx, sdk.CreateTemplateRequest{
+		ID:       "recreated_template",
+		Subject:  "Second",
+		HTMLBody: "<p>second</p>",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = testClient.DeleteTemplate(ctx, "recreated_template", 0) })
+	assert.Greater(t, recreated.Version, created.Version)
+
+	first, err := testClient.RenderEmail(ctx, sdk.RenderEmailRequest{TemplateID: "recreated_template", TemplateVersion: created.Version})
+	require.NoError(t, err)
+	assert.Equal(t, "First", first.Subject)
+}
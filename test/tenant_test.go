@@ -0,0 +1,63 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/travisbale/mailman/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTenantSuppressionsAreIsolated(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	_, err := testClient.AddSuppression(ctx, sdk.AddSuppressionRequest{
+		Address: "isolated@example.com",
+		Reason:  sdk.SuppressionReasonBounce,
+	})
+	require.NoError(t, err)
+
+	// Another tenant neither sees nor can lift the suppression
+	suppressions, err := tenantClient.ListSuppressions(ctx)
+	require.NoError(t, err)
+	assert.NotContains(t, suppressionAddresses(suppressions), "isolated@example.com")
+
+	err = tenantClient.RemoveSuppression(ctx, "isolated@example.com")
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// and can still email the address
+	_, err = tenantClient.SendEmail(ctx, sdk.SendEmailRequest{
+		TemplateID: "simple_template",
+		To:         "isolated@example.com",
+		Variables:  map[string]string{"Name": "Alice"},
+	})
+	require.NoError(t, err)
+
+	_, err = testClient.SendEmail(ctx, sdk.SendEmailRequest{
+		TemplateID: "simple_template",
+		To:         "isolated@example.com",
+		Variables:  map[string]string{"Name": "Alice"},
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestTenantCannotSendAsAnotherTenantsSender(t *testing.T) {
+	t.Parallel()
+
+	// billing@example.com is registered by the default tenant only
+	_, err := tenantClient.SendEmail(context.Background(), sdk.SendEmailRequest{
+		TemplateID: "simple_template",
+		To:         "user@example.com",
+		Sender:     "billing@example.com",
+		Variables:  map[string]string{"Name": "Alice"},
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -10,6 +10,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -20,6 +21,10 @@ func rawGRPCClient(t *testing.T) pb.MailmanServiceClient {
 	conn, err := grpc.NewClient(
 		grpcAddress,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+apiKey)
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}),
 	)
 	require.NoError(t, err)
 	t.Cleanup(func() { conn.Close() })
@@ -34,7 +39,7 @@ func TestSendEmailMissingTemplateID(t *testing.T) {
 
 	_, err := client.SendEmail(context.Background(), &pb.SendEmailRequest{
 		TemplateId: "",
-		To:         "user@example.com",
+		To:         []string{"user@example.com"},
 	})
 
 	require.Error(t, err)
@@ -51,7 +56,7 @@ func TestSendEmailMissingRecipient(t *testing.T) {
 
 	_, err := client.SendEmail(context.Background(), &pb.SendEmailRequest{
 		TemplateId: "simple_template",
-		To:         "",
+		To:         nil,
 	})
 
 	require.Error(t, err)
@@ -68,7 +73,7 @@ func TestSendEmailInvalidEmailFormat(t *testing.T) {
 
 	_, err := client.SendEmail(context.Background(), &pb.SendEmailRequest{
 		TemplateId: "simple_template",
-		To:         "notanemail",
+		To:         []string{"notanemail"},
 	})
 
 	require.Error(t, err)
@@ -87,12 +92,12 @@ func TestSendEmailBatchPartialFailure(t *testing.T) {
 		Emails: []*pb.SendEmailRequest{
 			{
 				TemplateId: "simple_template",
-				To:         "valid@example.com",
+				To:         []string{"valid@example.com"},
 				Variables:  map[string]string{"Name": "Alice"},
 			},
 			{
 				TemplateId: "simple_template",
-				To:         "", // Missing recipient
+				To:         nil, // Missing recipient
 			},
 		},
 	})
@@ -102,6 +107,7 @@ func TestSendEmailBatchPartialFailure(t *testing.T) {
 	st, ok := status.FromError(err)
 	require.True(t, ok)
 	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Contains(t, st.Message(), "index 1")
 }
 
 func TestSendEmailBatchEmpty(t *testing.T) {